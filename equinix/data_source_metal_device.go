@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
-	"sort"
 	"strings"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
@@ -120,6 +119,9 @@ func dataSourceMetalDevice() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"raid":             deviceRaidSchema(),
+			"hardware_details": deviceHardwareDetailsSchema(),
+			"inventory":        deviceInventorySchema(),
 			"root_password": {
 				Type:        schema.TypeString,
 				Description: "Root password to the server (if still available)",
@@ -165,6 +167,11 @@ func dataSourceMetalDevice() *schema.Resource {
 							Description: "Whether the address is routable from the Internet",
 							Computed:    true,
 						},
+						"reservation_id": {
+							Type:        schema.TypeString,
+							Description: "ID of the IP reservation block the address was allocated from",
+							Computed:    true,
+						},
 					},
 				},
 			},
@@ -207,6 +214,11 @@ func dataSourceMetalDevice() *schema.Resource {
 				Description: "The hostname to use for [Serial over SSH](https://deploy.equinix.com/developers/docs/metal/resilience-recovery/serial-over-ssh/) access to the device",
 				Computed:    true,
 			},
+			"image_url": {
+				Type:        schema.TypeString,
+				Description: "The URL of the image the device was provisioned from, when the operating system reports one",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -263,6 +275,7 @@ func dataSourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta
 	d.Set("always_pxe", device.GetAlwaysPxe())
 	d.Set("root_password", device.GetRootPassword())
 	d.Set("sos_hostname", device.GetSos())
+	d.Set("image_url", device.GetImageUrl())
 
 	if device.Storage != nil {
 		rawStorageBytes, err := json.Marshal(device.Storage)
@@ -276,6 +289,9 @@ func dataSourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta
 		}
 		d.Set("storage", storageString)
 	}
+	d.Set("raid", getRaid(device.Storage))
+	d.Set("hardware_details", getHardwareDetails(device.Plan))
+	d.Set("inventory", getDeviceInventory(device))
 
 	if device.HardwareReservation != nil {
 		d.Set("hardware_reservation_id", device.HardwareReservation.GetId())
@@ -296,14 +312,6 @@ func dataSourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta
 	d.Set("ssh_key_ids", keyIDs)
 	networkInfo := getNetworkInfo(device.IpAddresses)
 
-	sort.SliceStable(networkInfo.Networks, func(i, j int) bool {
-		famI := networkInfo.Networks[i]["family"].(int32)
-		famJ := networkInfo.Networks[j]["family"].(int32)
-		pubI := networkInfo.Networks[i]["public"].(bool)
-		pubJ := networkInfo.Networks[j]["public"].(bool)
-		return getNetworkRank(int(famI), pubI) < getNetworkRank(int(famJ), pubJ)
-	})
-
 	d.Set("network", networkInfo.Networks)
 	d.Set("access_public_ipv4", networkInfo.PublicIPv4)
 	d.Set("access_private_ipv4", networkInfo.PrivateIPv4)
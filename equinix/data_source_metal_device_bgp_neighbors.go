@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -103,6 +104,45 @@ func dataSourceMetalDeviceBGPNeighbors() *schema.Resource {
 				Computed:    true,
 				Elem:        bgpNeighborSchema(),
 			},
+			"bgp_sessions": {
+				Type:        schema.TypeList,
+				Description: "BGP sessions of the device and their status",
+				Computed:    true,
+				Elem:        bgpSessionStatusSchema(),
+			},
+		},
+	}
+}
+
+func bgpSessionStatusSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Description: "ID of the BGP session",
+				Computed:    true,
+			},
+			"address_family": {
+				Type:        schema.TypeString,
+				Description: "ipv4 or ipv6",
+				Computed:    true,
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "Status of the session, up or down once known. Devices connected to several switches report one status per switch, separated by spaces",
+				Computed:    true,
+			},
+			"default_route": {
+				Type:        schema.TypeBool,
+				Description: "Whether the session announces a default route to the device",
+				Computed:    true,
+			},
+			"learned_routes": {
+				Type:        schema.TypeList,
+				Description: "Routes learned from the device over the session",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
@@ -111,12 +151,18 @@ func dataSourceMetalDeviceBGPNeighborsRead(ctx context.Context, d *schema.Resour
 	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	deviceID := d.Get("device_id").(string)
 
-	bgpNeighborsRaw, _, err := client.DevicesApi.GetBgpNeighborData(ctx, deviceID).Execute()
+	bgpNeighborsRaw, resp, err := client.DevicesApi.GetBgpNeighborData(ctx, deviceID).Execute()
 	if err != nil {
-		return diag.FromErr(err)
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+
+	bgpSessions, resp, err := client.DevicesApi.FindBgpSessions(ctx, deviceID).Execute()
+	if err != nil {
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
 	d.Set("bgp_neighbors", getBgpNeighbors(bgpNeighborsRaw))
+	d.Set("bgp_sessions", getBgpSessionStatuses(bgpSessions.BgpSessions))
 	d.SetId(deviceID)
 	return nil
 }
@@ -150,3 +196,17 @@ func getBgpNeighbors(ns *metalv1.BgpSessionNeighbors) []map[string]interface{} {
 	}
 	return ret
 }
+
+func getBgpSessionStatuses(sessions []metalv1.BgpSession) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(sessions))
+	for _, s := range sessions {
+		ret = append(ret, map[string]interface{}{
+			"id":             s.GetId(),
+			"address_family": string(s.GetAddressFamily()),
+			"status":         s.GetStatus(),
+			"default_route":  s.GetDefaultRoute(),
+			"learned_routes": s.GetLearnedRoutes(),
+		})
+	}
+	return ret
+}
@@ -0,0 +1,55 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceMetalDeviceBGPNeighborsRead(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+			w.Write([]byte(`{"bgp_neighbors": [{"address_family": 4, "customer_as": 65000, "peer_as": 65530, "peer_ips": ["169.254.255.1"]}]}`))
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/sessions"):
+			w.Write([]byte(`{"bgp_sessions": [{"id": "sessionId", "address_family": "ipv4", "status": "up down", "learned_routes": ["192.0.2.0/24"]}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	d := schema.TestResourceDataRaw(t, dataSourceMetalDeviceBGPNeighbors().Schema, map[string]interface{}{"device_id": "deviceId"})
+	if diags := dataSourceMetalDeviceBGPNeighborsRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("dataSourceMetalDeviceBGPNeighborsRead() error = %v", diags)
+	}
+
+	if got := d.Get("bgp_neighbors.0.peer_as"); got != 65530 {
+		t.Errorf("bgp_neighbors.0.peer_as = %v, want 65530", got)
+	}
+	for k, want := range map[string]interface{}{
+		"bgp_sessions.0.id":               "sessionId",
+		"bgp_sessions.0.address_family":   "ipv4",
+		"bgp_sessions.0.status":           "up down",
+		"bgp_sessions.0.learned_routes.0": "192.0.2.0/24",
+	} {
+		if got := d.Get(k); got != want {
+			t.Errorf("%s = %v, want %v", k, got, want)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package equinix
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/terraform-provider-equinix/internal/datalist"
@@ -46,6 +48,23 @@ func dataSourceMetalDevices() *schema.Resource {
 				Description: "Search string to filter devices by hostname, description, short_id, reservation short_id, tags, plan name, plan slug, facility code, facility name, operating system name, operating system slug, IP addresses.",
 				Optional:    true,
 			},
+			"facility": {
+				Type:        schema.TypeString,
+				Description: "Facility code to query for devices",
+				Optional:    true,
+			},
+			"metro": {
+				Type:          schema.TypeString,
+				Description:   "Metro code to query for devices, only supported with project_id",
+				Optional:      true,
+				ConflictsWith: []string{"organization_id"},
+			},
+			"tags": {
+				Type:        schema.TypeList,
+				Description: "Tags that all of the returned devices must have",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 	return datalist.NewResource(dataListConfig)
@@ -61,9 +80,11 @@ func getDevices(ctx context.Context, d *schema.ResourceData, meta interface{}, e
 	}
 
 	search := extra["search"].(string)
+	facility := extra["facility"].(string)
+	metro := extra["metro"].(string)
+	tags := converters.IfArrToStringArr(extra["tags"].([]interface{}))
 
 	var devices *metalv1.DeviceList
-	devicesIf := []interface{}{}
 	var err error
 
 	if len(projectID) > 0 {
@@ -72,7 +93,13 @@ func getDevices(ctx context.Context, d *schema.ResourceData, meta interface{}, e
 		if len(search) > 0 {
 			query = query.Search(search)
 		}
-		devices, _, err = query.Execute()
+		if len(facility) > 0 {
+			query = query.Facility(facility)
+		}
+		if len(metro) > 0 {
+			query = query.Metro(metro)
+		}
+		devices, err = query.ExecuteWithPagination()
 	}
 
 	if len(orgID) > 0 {
@@ -81,13 +108,34 @@ func getDevices(ctx context.Context, d *schema.ResourceData, meta interface{}, e
 		if len(search) > 0 {
 			query = query.Search(search)
 		}
-		devices, _, err = query.Execute()
+		if len(facility) > 0 {
+			query = query.Facility(facility)
+		}
+		devices, err = query.ExecuteWithPagination()
 	}
 
+	if err != nil {
+		return nil, err
+	}
+
+	devicesIf := []interface{}{}
 	for _, d := range devices.Devices {
-		devicesIf = append(devicesIf, d)
+		if deviceHasTags(d, tags) {
+			devicesIf = append(devicesIf, d)
+		}
+	}
+	return devicesIf, nil
+}
+
+// deviceHasTags reports whether the device has all of the given tags. The
+// API only accepts a single tag to search for, so the tags are matched here.
+func deviceHasTags(device metalv1.Device, tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(device.GetTags(), tag) {
+			return false
+		}
 	}
-	return devicesIf, err
+	return true
 }
 
 func flattenDevice(rawDevice interface{}, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
@@ -0,0 +1,28 @@
+package equinix
+
+import (
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+func TestDeviceHasTags(t *testing.T) {
+	device := metalv1.Device{Tags: []string{"web", "prod"}}
+	cases := []struct {
+		name string
+		tags []string
+		want bool
+	}{
+		{name: "no tags", tags: nil, want: true},
+		{name: "one tag", tags: []string{"web"}, want: true},
+		{name: "all tags", tags: []string{"prod", "web"}, want: true},
+		{name: "missing tag", tags: []string{"web", "db"}, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := deviceHasTags(device, tc.tags); got != tc.want {
+				t.Errorf("deviceHasTags(%v) = %v, want %v", tc.tags, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package equinix
+
+import (
+	"fmt"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+)
+
+func dataSourceMetalIPAttachment() *schema.Resource {
+	ipAttachmentSchema := metalIPResourceComputedFields()
+	ipAttachmentSchema["id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "ID of the IP address assignment to look up",
+	}
+	ipAttachmentSchema["device_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "ID of the device the address is assigned to",
+	}
+	ipAttachmentSchema["cidr_notation"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "Assigned subnet in CIDR notation",
+	}
+	ipAttachmentSchema["reservation_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "ID of the reserved IP block the subnet belongs to",
+	}
+	return &schema.Resource{
+		Read:   dataSourceMetalIPAttachmentRead,
+		Schema: ipAttachmentSchema,
+	}
+}
+
+func dataSourceMetalIPAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	meta.(*config.Config).AddModuleToMetalUserAgent(d)
+	client := meta.(*config.Config).Metal
+
+	assignmentID := d.Get("id").(string)
+	getOpts := &packngo.GetOptions{Includes: []string{"vrf"}}
+	assignment, _, err := client.DeviceIPs.Get(assignmentID, getOpts)
+	if err != nil {
+		return fmt.Errorf("error reading IP address assignment %s: %s", assignmentID, equinix_errors.FriendlyError(err))
+	}
+
+	return loadIPAttachment(d, assignment)
+}
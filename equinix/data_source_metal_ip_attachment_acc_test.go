@@ -0,0 +1,47 @@
+package equinix
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceMetalIPAttachment_basic(t *testing.T) {
+	rs := acctest.RandString(10)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ExternalProviders:        testExternalProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccMetalIPAttachmentCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMetalIPAttachmentConfig_basic(rs),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.equinix_metal_ip_attachment.test", "address",
+						"equinix_metal_ip_attachment.test", "address"),
+					resource.TestCheckResourceAttrPair(
+						"data.equinix_metal_ip_attachment.test", "gateway",
+						"equinix_metal_ip_attachment.test", "gateway"),
+					resource.TestCheckResourceAttr(
+						"data.equinix_metal_ip_attachment.test", "cidr", "32"),
+					resource.TestCheckResourceAttrPair(
+						"data.equinix_metal_ip_attachment.test", "device_id",
+						"equinix_metal_device.test", "id"),
+					resource.TestCheckResourceAttrSet(
+						"data.equinix_metal_ip_attachment.test", "manageable"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceMetalIPAttachmentConfig_basic(name string) string {
+	return testAccMetalIPAttachmentConfig_metro(name) + `
+
+data "equinix_metal_ip_attachment" "test" {
+	id = equinix_metal_ip_attachment.test.id
+}`
+}
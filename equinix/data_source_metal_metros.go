@@ -0,0 +1,100 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	"github.com/equinix/terraform-provider-equinix/internal/datalist"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+)
+
+// metroRecord is a metro with the slugs of the plans available in it, which the
+// API only reports on the plans
+type metroRecord struct {
+	packngo.Metro
+	plans []string
+}
+
+func dataSourceMetalMetros() *schema.Resource {
+	dataListConfig := &datalist.ResourceConfig{
+		RecordSchema:               metroSchema(),
+		ResultAttributeName:        "metros",
+		ResultAttributeDescription: "Sorted list of metros that match the specified filters",
+		FlattenRecord:              flattenMetro,
+		GetRecords:                 getMetros,
+	}
+
+	return datalist.NewResource(dataListConfig)
+}
+
+func getMetros(_ context.Context, _ *schema.ResourceData, meta interface{}, extra map[string]interface{}) ([]interface{}, error) {
+	client := meta.(*config.Config).Metal
+	metros, _, err := client.Metros.List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error listing metros: %w", err)
+	}
+	plans, _, err := client.Plans.List(&packngo.ListOptions{Includes: []string{"available_in_metros"}})
+	if err != nil {
+		return nil, fmt.Errorf("error listing plans: %w", err)
+	}
+	return metroRecords(metros, plans), nil
+}
+
+func metroRecords(metros []packngo.Metro, plans []packngo.Plan) []interface{} {
+	metroPlans := map[string][]string{}
+	for _, p := range plans {
+		for _, m := range p.AvailableInMetros {
+			metroPlans[m.Code] = append(metroPlans[m.Code], p.Slug)
+		}
+	}
+
+	records := []interface{}{}
+	for _, m := range metros {
+		records = append(records, metroRecord{Metro: m, plans: metroPlans[m.Code]})
+	}
+	return records
+}
+
+func metroSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:        schema.TypeString,
+			Description: "id of the metro",
+		},
+		"code": {
+			Type:        schema.TypeString,
+			Description: "code of the metro, e.g. sv",
+		},
+		"name": {
+			Type:        schema.TypeString,
+			Description: "name of the metro",
+		},
+		"country": {
+			Type:        schema.TypeString,
+			Description: "two letter code of the country of the metro",
+		},
+		"plans": {
+			Type:        schema.TypeSet,
+			Description: "list of slugs of the plans available in the metro",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func flattenMetro(rawMetro interface{}, meta interface{}, extra map[string]interface{}) (map[string]interface{}, error) {
+	metro, ok := rawMetro.(metroRecord)
+	if !ok {
+		return nil, fmt.Errorf("unable to convert to metroRecord")
+	}
+
+	return map[string]interface{}{
+		"id":      metro.ID,
+		"code":    metro.Code,
+		"name":    metro.Name,
+		"country": metro.Country,
+		"plans":   schema.NewSet(schema.HashString, converters.StringArrToIfArr(metro.plans)),
+	}, nil
+}
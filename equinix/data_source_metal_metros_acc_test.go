@@ -0,0 +1,35 @@
+package equinix
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceMetalMetros_country(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ExternalProviders:        testExternalProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMetalMetrosConfig_country,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs(
+						"data.equinix_metal_metros.test", "metros.*",
+						map[string]string{"code": "sv", "country": "US"},
+					),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceMetalMetrosConfig_country = `
+data "equinix_metal_metros" "test" {
+    filter {
+        attribute = "country"
+        values    = ["US"]
+    }
+}
+`
@@ -0,0 +1,79 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceMetalMetros_countryFilter(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.WriteHeader(http.StatusOK)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/locations/metros"):
+			w.Write([]byte(`{"metros": [
+				{"id": "1", "code": "sv", "name": "Silicon Valley", "country": "US"},
+				{"id": "2", "code": "am", "name": "Amsterdam", "country": "NL"},
+				{"id": "3", "code": "ny", "name": "New York", "country": "US"}
+			]}`))
+		case strings.HasSuffix(r.URL.Path, "/plans"):
+			w.Write([]byte(`{"plans": [
+				{"id": "1", "slug": "c3.small.x86", "available_in_metros": [{"code": "sv"}, {"code": "am"}]},
+				{"id": "2", "slug": "m3.large.x86", "available_in_metros": [{"code": "sv"}]}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockAPI.Close()
+
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	resource := dataSourceMetalMetros()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{
+				"attribute": "country",
+				"values":    []interface{}{"US"},
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{
+				"attribute": "code",
+			},
+		},
+	})
+
+	if diags := resource.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("Read() error = %v", diags)
+	}
+
+	metros := d.Get("metros").([]interface{})
+	if len(metros) != 2 {
+		t.Fatalf("Read() returned %d metros, want 2", len(metros))
+	}
+	ny := metros[0].(map[string]interface{})
+	sv := metros[1].(map[string]interface{})
+	if ny["code"] != "ny" || sv["code"] != "sv" {
+		t.Fatalf("Read() metros = %v, %v, want ny and sv", ny["code"], sv["code"])
+	}
+	if plans := ny["plans"].(*schema.Set); plans.Len() != 0 {
+		t.Errorf("Read() ny plans = %v, want none", plans.List())
+	}
+	if plans := sv["plans"].(*schema.Set); plans.Len() != 2 || !plans.Contains("c3.small.x86") || !plans.Contains("m3.large.x86") {
+		t.Errorf("Read() sv plans = %v, want c3.small.x86 and m3.large.x86", plans.List())
+	}
+}
@@ -1,18 +1,21 @@
 package equinix
 
 import (
-	"fmt"
+	"context"
+	"slices"
 	"strings"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/packethost/packngo"
 )
 
 func dataSourceOperatingSystem() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceMetalOperatingSystemRead,
+		ReadWithoutTimeout: dataSourceMetalOperatingSystemRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -39,12 +42,17 @@ func dataSourceOperatingSystem() *schema.Resource {
 				Description: "Operating system slug (same as id)",
 				Computed:    true,
 			},
+			"licensed": {
+				Type:        schema.TypeBool,
+				Description: "Whether the operating system requires a paid license",
+				Computed:    true,
+			},
 		},
 	}
 }
 
-func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*config.Config).Metal
+func dataSourceMetalOperatingSystemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	name, nameOK := d.GetOk("name")
 	distro, distroOK := d.GetOk("distro")
@@ -52,67 +60,55 @@ func dataSourceMetalOperatingSystemRead(d *schema.ResourceData, meta interface{}
 	provisionableOn, provisionableOnOK := d.GetOk("provisionable_on")
 
 	if !nameOK && !distroOK && !versionOK && !provisionableOnOK {
-		return fmt.Errorf("One of name, distro, version, or provisionable_on must be assigned")
+		return diag.Errorf("One of name, distro, version, or provisionable_on must be assigned")
 	}
 
-	oss, _, err := client.OperatingSystems.List()
+	list, resp, err := client.OperatingSystemsApi.FindOperatingSystems(ctx).Execute()
 	if err != nil {
-		return err
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
 	}
 
-	if nameOK {
-		temp := []packngo.OS{}
-		for _, os := range oss {
-			if strings.Contains(strings.ToLower(os.Name), strings.ToLower(name.(string))) {
-				temp = append(temp, os)
-			}
-		}
-		oss = temp
-	}
+	oss := filterOperatingSystems(list.GetOperatingSystems(), name.(string), distro.(string), version.(string), provisionableOn.(string))
 
-	if distroOK && (len(oss) != 0) {
-		temp := []packngo.OS{}
-		for _, v := range oss {
-			if v.Distro == distro.(string) {
-				temp = append(temp, v)
-			}
-		}
-		oss = temp
+	if len(oss) == 0 {
+		return diag.Errorf("There are no operating systems that match the search criteria")
 	}
 
-	if versionOK && (len(oss) != 0) {
-		temp := []packngo.OS{}
-		for _, v := range oss {
-			if v.Version == version.(string) {
-				temp = append(temp, v)
-			}
+	if len(oss) > 1 {
+		slugs := make([]string, 0, len(oss))
+		for _, os := range oss {
+			slugs = append(slugs, os.GetSlug())
 		}
-		oss = temp
+		return diag.Errorf("There is more than one operating system that matches the search criteria: %s", strings.Join(slugs, ", "))
 	}
+	d.Set("name", oss[0].GetName())
+	d.Set("distro", oss[0].GetDistro())
+	d.Set("version", oss[0].GetVersion())
+	d.Set("slug", oss[0].GetSlug())
+	d.Set("licensed", oss[0].GetLicensed())
+	d.SetId(oss[0].GetSlug())
+	return nil
+}
 
-	if provisionableOnOK && (len(oss) != 0) {
-		temp := []packngo.OS{}
-		for _, v := range oss {
-			for _, po := range v.ProvisionableOn {
-				if po == provisionableOn.(string) {
-					temp = append(temp, v)
-				}
-			}
+// filterOperatingSystems returns the operating systems matching all the given
+// filters. Empty filters are ignored. The name filter is a case insensitive
+// substring match, the other filters must match exactly.
+func filterOperatingSystems(oss []metalv1.OperatingSystem, name, distro, version, provisionableOn string) []metalv1.OperatingSystem {
+	ret := []metalv1.OperatingSystem{}
+	for _, os := range oss {
+		if name != "" && !strings.Contains(strings.ToLower(os.GetName()), strings.ToLower(name)) {
+			continue
 		}
-		oss = temp
-	}
-
-	if len(oss) == 0 {
-		return fmt.Errorf("There are no operating systems that match the search criteria")
-	}
-
-	if len(oss) > 1 {
-		return fmt.Errorf("There is more than one operating system that matches the search criteria")
+		if distro != "" && os.GetDistro() != distro {
+			continue
+		}
+		if version != "" && os.GetVersion() != version {
+			continue
+		}
+		if provisionableOn != "" && !slices.Contains(os.GetProvisionableOn(), provisionableOn) {
+			continue
+		}
+		ret = append(ret, os)
 	}
-	d.Set("name", oss[0].Name)
-	d.Set("distro", oss[0].Distro)
-	d.Set("version", oss[0].Version)
-	d.Set("slug", oss[0].Slug)
-	d.SetId(oss[0].Slug)
-	return nil
+	return ret
 }
@@ -0,0 +1,59 @@
+package equinix
+
+import (
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+func TestFilterOperatingSystems(t *testing.T) {
+	oss := []metalv1.OperatingSystem{
+		{
+			Slug:            metalv1.PtrString("ubuntu_22_04"),
+			Name:            metalv1.PtrString("Ubuntu 22.04 LTS"),
+			Distro:          metalv1.PtrString("ubuntu"),
+			Version:         metalv1.PtrString("22.04"),
+			ProvisionableOn: []string{"c3.small.x86", "m3.large.x86"},
+		},
+		{
+			Slug:            metalv1.PtrString("ubuntu_20_04"),
+			Name:            metalv1.PtrString("Ubuntu 20.04 LTS"),
+			Distro:          metalv1.PtrString("ubuntu"),
+			Version:         metalv1.PtrString("20.04"),
+			ProvisionableOn: []string{"c3.small.x86"},
+		},
+		{
+			Slug:     metalv1.PtrString("windows_2022"),
+			Name:     metalv1.PtrString("Windows 2022"),
+			Distro:   metalv1.PtrString("windows"),
+			Version:  metalv1.PtrString("2022"),
+			Licensed: metalv1.PtrBool(true),
+		},
+	}
+
+	tests := []struct {
+		name                                   string
+		osName, distro, version, provisionable string
+		want                                   []string
+	}{
+		{name: "distro", distro: "ubuntu", want: []string{"ubuntu_22_04", "ubuntu_20_04"}},
+		{name: "distro and version", distro: "ubuntu", version: "20.04", want: []string{"ubuntu_20_04"}},
+		{name: "name is case insensitive", osName: "windows", want: []string{"windows_2022"}},
+		{name: "provisionable on", provisionable: "m3.large.x86", want: []string{"ubuntu_22_04"}},
+		{name: "no match", distro: "debian", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterOperatingSystems(oss, tt.osName, tt.distro, tt.version, tt.provisionable)
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterOperatingSystems() returned %d operating systems, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].GetSlug() != tt.want[i] {
+					t.Errorf("filterOperatingSystems()[%d] = %s, want %s", i, got[i].GetSlug(), tt.want[i])
+				}
+			}
+		})
+	}
+}
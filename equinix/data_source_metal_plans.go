@@ -129,6 +129,8 @@ func flattenPlan(rawPlan interface{}, meta interface{}, extra map[string]interfa
 		"available_in_metros": flattenedMetros,
 	}
 
+	// Plans without published pricing leave the price out of the record, so
+	// that price filters do not match them as if they were free
 	if plan.Pricing != nil {
 		flattenedPlan["pricing_hour"] = float64(plan.Pricing.Hour)
 		flattenedPlan["pricing_month"] = float64(plan.Pricing.Month)
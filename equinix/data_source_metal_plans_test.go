@@ -0,0 +1,146 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+)
+
+func TestFlattenPlan_pricing(t *testing.T) {
+	got, err := flattenPlan(packngo.Plan{Slug: "c3.small.x86", Pricing: &packngo.Pricing{Hour: 1.5, Month: 1000}}, nil, nil)
+	if err != nil {
+		t.Fatalf("flattenPlan() error = %v", err)
+	}
+	// the datalist filters and sorts assert these to float64
+	if hour, ok := got["pricing_hour"].(float64); !ok || hour != 1.5 {
+		t.Errorf("flattenPlan() pricing_hour = %#v, want 1.5", got["pricing_hour"])
+	}
+	if month, ok := got["pricing_month"].(float64); !ok || month != 1000 {
+		t.Errorf("flattenPlan() pricing_month = %#v, want 1000", got["pricing_month"])
+	}
+
+	got, err = flattenPlan(packngo.Plan{Slug: "c3.small.x86"}, nil, nil)
+	if err != nil {
+		t.Fatalf("flattenPlan() error = %v", err)
+	}
+	for _, attr := range []string{"pricing_hour", "pricing_month"} {
+		if v, ok := got[attr]; ok {
+			t.Errorf("flattenPlan() without pricing %s = %#v, want it left out", attr, v)
+		}
+	}
+}
+
+func TestDataSourceMetalPlans_priceFilter(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.WriteHeader(http.StatusOK)
+		// the API leaves the pricing out of plans that are not on sale
+		w.Write([]byte(`{"plans": [
+			{"id": "1", "slug": "n3.xlarge.x86", "name": "n3.xlarge.x86"},
+			{"id": "2", "slug": "c3.small.x86", "name": "c3.small.x86", "pricing": {"hour": 0.75, "month": 500}},
+			{"id": "3", "slug": "m3.large.x86", "name": "m3.large.x86", "pricing": {"hour": 3.1, "month": 2000}}
+		]}`))
+	}))
+	defer mockAPI.Close()
+
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	resource := dataSourceMetalPlans()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"filter": []interface{}{
+			map[string]interface{}{
+				"attribute": "pricing_hour",
+				"values":    []interface{}{"5"},
+				"match_by":  "less_than",
+			},
+		},
+		"sort": []interface{}{
+			map[string]interface{}{
+				"attribute": "pricing_hour",
+				"direction": "desc",
+			},
+		},
+	})
+
+	if diags := resource.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("Read() error = %v", diags)
+	}
+
+	plans := d.Get("plans").([]interface{})
+	got := make([]string, len(plans))
+	for i, plan := range plans {
+		got[i] = plan.(map[string]interface{})["slug"].(string)
+	}
+	if want := []string{"m3.large.x86", "c3.small.x86"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() plans = %v, want %v", got, want)
+	}
+}
+
+func TestDataSourceMetalPlans_legacyFilter(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.WriteHeader(http.StatusOK)
+		// the API leaves the legacy flag out of current plans
+		w.Write([]byte(`{"plans": [
+			{"id": "1", "slug": "c3.small.x86", "name": "c3.small.x86"},
+			{"id": "2", "slug": "t1.small.x86", "name": "t1.small.x86", "legacy": true}
+		]}`))
+	}))
+	defer mockAPI.Close()
+
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	tests := []struct {
+		legacy string
+		want   string
+	}{
+		{legacy: "false", want: "c3.small.x86"},
+		{legacy: "true", want: "t1.small.x86"},
+	}
+
+	for _, tt := range tests {
+		t.Run("legacy="+tt.legacy, func(t *testing.T) {
+			resource := dataSourceMetalPlans()
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+				"filter": []interface{}{
+					map[string]interface{}{
+						"attribute": "legacy",
+						"values":    []interface{}{tt.legacy},
+					},
+				},
+			})
+
+			if diags := resource.ReadContext(ctx, d, meta); diags.HasError() {
+				t.Fatalf("Read() error = %v", diags)
+			}
+
+			plans := d.Get("plans").([]interface{})
+			if len(plans) != 1 {
+				t.Fatalf("Read() returned %d plans, want 1", len(plans))
+			}
+			if got := plans[0].(map[string]interface{})["slug"]; got != tt.want {
+				t.Errorf("Read() plan = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -116,6 +116,17 @@ func dataSourceMetalReservedIPBlock() *schema.Resource {
 				Computed:    true,
 				Description: "Address type, one of public_ipv4, public_ipv6, private_ipv4, global_ipv4, and vrf",
 			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "Tags attached to the reserved block",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"custom_data": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Custom data of the reserved block, serialized as JSON",
+			},
 		},
 	}
 }
@@ -19,6 +19,10 @@ resource "equinix_metal_reserved_ip_block" "test" {
 	metro       = "sv"
 	type        = "public_ipv4"
 	quantity    = 2
+	tags        = ["Tag1", "Tag2"]
+	custom_data = jsonencode({
+		"foo": "bar"
+	})
 }
 
 data "equinix_metal_reserved_ip_block" "test" {
@@ -52,6 +56,15 @@ func TestAccDataSourceMetalReservedIPBlock_basic(t *testing.T) {
 						"equinix_metal_reserved_ip_block.test", "cidr_notation",
 						"data.equinix_metal_reserved_ip_block.test_id", "cidr_notation",
 					),
+					resource.TestCheckResourceAttr(
+						"data.equinix_metal_reserved_ip_block.test", "tags.#", "2",
+					),
+					resource.TestCheckTypeSetElemAttr(
+						"data.equinix_metal_reserved_ip_block.test", "tags.*", "Tag1",
+					),
+					resource.TestCheckResourceAttr(
+						"data.equinix_metal_reserved_ip_block.test_id", "custom_data", `{"foo":"bar"}`,
+					),
 				),
 			},
 		},
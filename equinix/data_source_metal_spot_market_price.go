@@ -2,8 +2,10 @@ package equinix
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
@@ -16,18 +18,18 @@ func dataSourceSpotMarketPrice() *schema.Resource {
 		Read: dataSourceMetalSpotMarketPriceRead,
 		Schema: map[string]*schema.Schema{
 			"facility": {
-				Type:          schema.TypeString,
-				Description:   "Name of the facility",
-				Deprecated:    "Use metro instead of facility.  For more information, read the migration guide: https://registry.terraform.io/providers/equinix/equinix/latest/docs/guides/migration_guide_facilities_to_metros_devices",
-				ConflictsWith: []string{"metro"},
-				Optional:      true,
+				Type:         schema.TypeString,
+				Description:  "Name of the facility",
+				Deprecated:   "Use metro instead of facility.  For more information, read the migration guide: https://registry.terraform.io/providers/equinix/equinix/latest/docs/guides/migration_guide_facilities_to_metros_devices",
+				ExactlyOneOf: []string{"facility", "metro"},
+				Optional:     true,
 			},
 			"metro": {
-				Type:          schema.TypeString,
-				Description:   "Name of the metro",
-				ConflictsWith: []string{"facility"},
-				Optional:      true,
-				StateFunc:     converters.ToLowerIf,
+				Type:         schema.TypeString,
+				Description:  "Name of the metro",
+				ExactlyOneOf: []string{"facility", "metro"},
+				Optional:     true,
+				StateFunc:    converters.ToLowerIf,
 			},
 			"plan": {
 				Type:        schema.TypeString,
@@ -47,13 +49,9 @@ func dataSourceMetalSpotMarketPriceRead(d *schema.ResourceData, meta interface{}
 	client := meta.(*config.Config).Metal
 	sms := client.SpotMarket.(*packngo.SpotMarketServiceOp)
 	facility := d.Get("facility").(string)
-	metro := d.Get("metro").(string)
+	metro := strings.ToLower(d.Get("metro").(string))
 	plan := d.Get("plan").(string)
 
-	if facility != "" && metro != "" {
-		return fmt.Errorf("Parameters facility and metro cannot be used together")
-	}
-
 	filter := facility
 	fn := sms.PricesByFacility
 	filterType := "facility"
@@ -65,21 +63,32 @@ func dataSourceMetalSpotMarketPriceRead(d *schema.ResourceData, meta interface{}
 	}
 
 	prices, _, err := fn()
+	if err != nil {
+		return equinix_errors.FriendlyError(err)
+	}
+
+	price, err := spotMarketPrice(prices, filterType, filter, plan)
 	if err != nil {
 		return err
 	}
 
+	d.Set("price", price)
+	d.SetId(fmt.Sprintf("%s-%s-%s", filterType, filter, plan))
+	return nil
+}
+
+// spotMarketPrice looks up the price of a plan in the spot market prices of a
+// metro or facility. Locations and plans are only listed while they have spot
+// market capacity, so a missing entry means that none is available.
+func spotMarketPrice(prices packngo.PriceMap, filterType, filter, plan string) (float64, error) {
 	match, ok := prices[filter]
 	if !ok {
-		return fmt.Errorf("Cannot find %s %s", filterType, filter)
+		return 0, fmt.Errorf("Cannot find spot market prices for %s %s, it has no spot market capacity or does not exist", filterType, filter)
 	}
 
 	price, ok := match[plan]
 	if !ok {
-		return fmt.Errorf("Cannot find price for plan %s in %s %s", plan, filterType, filter)
+		return 0, fmt.Errorf("Plan %s has no spot market availability in %s %s", plan, filterType, filter)
 	}
-
-	d.Set("price", price)
-	d.SetId(fmt.Sprintf("%s-%s-%s", filterType, filter, plan))
-	return nil
+	return price, nil
 }
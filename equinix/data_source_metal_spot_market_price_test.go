@@ -0,0 +1,44 @@
+package equinix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/packethost/packngo"
+)
+
+func TestSpotMarketPrice(t *testing.T) {
+	prices := packngo.PriceMap{
+		"sv": {"c3.small.x86": 0.25},
+	}
+
+	tests := []struct {
+		name    string
+		metro   string
+		plan    string
+		want    float64
+		wantErr string
+	}{
+		{name: "available", metro: "sv", plan: "c3.small.x86", want: 0.25},
+		{name: "unknown metro", metro: "da", plan: "c3.small.x86", wantErr: "no spot market capacity"},
+		{name: "plan not available", metro: "sv", plan: "m3.large.x86", wantErr: "no spot market availability"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := spotMarketPrice(prices, "metro", tt.metro, tt.plan)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("spotMarketPrice() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("spotMarketPrice() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("spotMarketPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
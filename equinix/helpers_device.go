@@ -4,19 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"path"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/packethost/packngo"
 )
 
@@ -25,6 +33,18 @@ const (
 	provisionable  = "provisionable"
 	reprovisioned  = "reprovisioned"
 	errstate       = "error"
+	deleting       = "deleting"
+	deleted        = "deleted"
+
+	provisioningPercentage = "provisioning_percentage"
+
+	// firmwareUpdating is reported by plans that patch their firmware while
+	// being provisioned
+	firmwareUpdating = "firmware_updating"
+
+	// provisioning_timeout_action values
+	provisioningTimeoutDelete = "delete"
+	provisioningTimeoutKeep   = "keep"
 )
 
 var (
@@ -41,15 +61,39 @@ type NetworkInfo struct {
 	PrivateIPv4    string
 }
 
+// getNetworkInfo lists the IP assignments of a device in a stable order, public
+// IPv4 first, then IPv6 and private IPv4, with the management addresses ahead
+// of any attached later. Assignments within a group are ordered by address, so
+// an address attached or detached out of band shows up as an added or removed
+// network instead of reordering the others.
 func getNetworkInfo(ips []metalv1.IPAssignment) NetworkInfo {
+	ips = slices.Clone(ips)
+	sort.SliceStable(ips, func(i, j int) bool {
+		rankI := getNetworkRank(int(ips[i].GetAddressFamily()), ips[i].GetPublic())
+		rankJ := getNetworkRank(int(ips[j].GetAddressFamily()), ips[j].GetPublic())
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		if ips[i].GetManagement() != ips[j].GetManagement() {
+			return ips[i].GetManagement()
+		}
+		return ips[i].GetAddress() < ips[j].GetAddress()
+	})
+
 	ni := NetworkInfo{Networks: make([]map[string]interface{}, 0, 1)}
 	for _, ip := range ips {
+		// The parent block is the IP reservation the address was allocated from
+		reservationID := ""
+		if href := ip.ParentBlock.GetHref(); href != "" {
+			reservationID = path.Base(href)
+		}
 		network := map[string]interface{}{
-			"address": ip.GetAddress(),
-			"gateway": ip.GetGateway(),
-			"family":  ip.GetAddressFamily(),
-			"cidr":    ip.GetCidr(),
-			"public":  ip.GetPublic(),
+			"address":        ip.GetAddress(),
+			"gateway":        ip.GetGateway(),
+			"family":         ip.GetAddressFamily(),
+			"cidr":           ip.GetCidr(),
+			"public":         ip.GetPublic(),
+			"reservation_id": reservationID,
 		}
 		ni.Networks = append(ni.Networks, network)
 
@@ -71,6 +115,21 @@ func getNetworkInfo(ips []metalv1.IPAssignment) NetworkInfo {
 	return ni
 }
 
+// networksChanged reports whether the addresses assigned to a device differ
+// from the ones recorded in state
+func networksChanged(old []interface{}, networks []map[string]interface{}) bool {
+	if len(old) != len(networks) {
+		return true
+	}
+	for i, o := range old {
+		network, ok := o.(map[string]interface{})
+		if !ok || network["address"] != networks[i]["address"] || network["cidr"] != int(networks[i]["cidr"].(int32)) {
+			return true
+		}
+	}
+	return false
+}
+
 func getNetworkType(device *metalv1.Device) (*string, error) {
 	pgDevice := packngo.Device{}
 	res, err := device.MarshalJSON()
@@ -89,7 +148,7 @@ func getNetworkRank(family int, public bool) int {
 		return 0
 	case family == 6:
 		return 1
-	case family == 4 && public:
+	case family == 4 && !public:
 		return 2
 	}
 	return 3
@@ -110,6 +169,166 @@ func getPorts(ps []metalv1.Port) []map[string]interface{} {
 	return ret
 }
 
+// getPortVlanAssignments returns the IDs of the VLANs attached to each port of
+// a device, keyed by port ID. Ports without VLANs are omitted.
+func getPortVlanAssignments(ctx context.Context, client *metalv1.APIClient, deviceID string) (map[string][]string, error) {
+	device, resp, err := client.DevicesApi.FindDeviceById(ctx, deviceID).Include([]string{"network_ports.virtual_networks"}).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+
+	ret := map[string][]string{}
+	for _, p := range device.NetworkPorts {
+		for _, vn := range p.GetVirtualNetworks() {
+			vlanID := vn.GetId()
+			if vlanID == "" && vn.GetHref() != "" {
+				vlanID = path.Base(vn.GetHref())
+			}
+			if vlanID != "" {
+				ret[p.GetId()] = append(ret[p.GetId()], vlanID)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// restorePortVlanAssignments reattaches the VLANs in before that are no longer
+// attached to the same port in after. It returns the assignments that had to
+// be reapplied, as "port_id:vlan_id" strings.
+func restorePortVlanAssignments(ctx context.Context, client *metalv1.APIClient, before, after map[string][]string) ([]string, error) {
+	restored := []string{}
+	portIDs := make([]string, 0, len(before))
+	for portID := range before {
+		portIDs = append(portIDs, portID)
+	}
+	sort.Strings(portIDs)
+
+	for _, portID := range portIDs {
+		for _, vlanID := range before[portID] {
+			if slices.Contains(after[portID], vlanID) {
+				continue
+			}
+			log.Printf("[WARN] VLAN %s was detached from port %s during device reinstall, reattaching", vlanID, portID)
+			input := metalv1.PortAssignInput{Vnid: metalv1.PtrString(vlanID)}
+			if _, resp, err := client.PortsApi.AssignPort(ctx, portID).PortAssignInput(input).Execute(); err != nil {
+				return restored, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+			}
+			restored = append(restored, portID+":"+vlanID)
+		}
+	}
+	return restored, nil
+}
+
+// expandDeviceStorage parses the storage JSON of the device into the layout
+// sent with the create request
+func expandDeviceStorage(v string) (*metalv1.Storage, error) {
+	var storage metalv1.Storage
+	if err := json.Unmarshal([]byte(v), &storage); err != nil {
+		return nil, err
+	}
+	return &storage, nil
+}
+
+// validateDeviceStorage checks that storage can be sent as a storage layout and
+// not only that it is JSON, so that e.g. a partition size given as a number is
+// reported when planning
+func validateDeviceStorage(v interface{}, k string) ([]string, []error) {
+	warnings, errs := validation.StringIsJSON(v, k)
+	if len(errs) > 0 {
+		return warnings, errs
+	}
+	if _, err := expandDeviceStorage(v.(string)); err != nil {
+		return warnings, []error{fmt.Errorf("%q is not a valid storage layout: %s. Partition sizes must be strings, e.g. \"4G\"", k, err)}
+	}
+	return warnings, nil
+}
+
+func getRaid(s *metalv1.Storage) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, 1)
+	if s == nil {
+		return ret
+	}
+	for _, r := range s.Raid {
+		raid := map[string]interface{}{
+			"name":    r.GetName(),
+			"level":   r.GetLevel(),
+			"devices": r.GetDevices(),
+		}
+		ret = append(ret, raid)
+	}
+	return ret
+}
+
+// getDeviceInventory returns the inventory block of a device. The metro of
+// devices deployed in a facility is the metro of the facility.
+func getDeviceInventory(device *metalv1.Device) []map[string]interface{} {
+	metro := device.Metro.GetCode()
+	if metro == "" && device.Facility != nil {
+		facilityMetro := device.Facility.GetMetro()
+		metro = facilityMetro.GetCode()
+	}
+	created := ""
+	if device.CreatedAt != nil {
+		created = device.GetCreatedAt().UTC().Format(time.RFC3339)
+	}
+	return []map[string]interface{}{{
+		"id":          device.GetId(),
+		"hostname":    device.GetHostname(),
+		"project_id":  device.Project.GetId(),
+		"metro":       strings.ToLower(metro),
+		"facility":    strings.ToLower(device.Facility.GetCode()),
+		"plan":        device.Plan.GetSlug(),
+		"state":       string(device.GetState()),
+		"iqn":         device.GetIqn(),
+		"switch_uuid": device.GetSwitchUuid(),
+		"created":     created,
+	}}
+}
+
+func getHardwareDetails(p *metalv1.Plan) []map[string]interface{} {
+	if p == nil || p.Specs == nil {
+		return []map[string]interface{}{}
+	}
+	specs := p.GetSpecs()
+
+	features := specs.GetFeatures()
+	memory := specs.GetMemory()
+
+	cpus := make([]map[string]interface{}, 0, len(specs.Cpus))
+	for _, c := range specs.Cpus {
+		cpus = append(cpus, map[string]interface{}{
+			"count": c.GetCount(),
+			"type":  c.GetType(),
+		})
+	}
+	drives := make([]map[string]interface{}, 0, len(specs.Drives))
+	for _, dr := range specs.Drives {
+		drives = append(drives, map[string]interface{}{
+			"count":    dr.GetCount(),
+			"type":     dr.GetType(),
+			"size":     dr.GetSize(),
+			"category": string(dr.GetCategory()),
+		})
+	}
+	nics := make([]map[string]interface{}, 0, len(specs.Nics))
+	for _, n := range specs.Nics {
+		nics = append(nics, map[string]interface{}{
+			"count": n.GetCount(),
+			"type":  n.GetType(),
+		})
+	}
+
+	return []map[string]interface{}{{
+		"raid":   features.GetRaid(),
+		"txt":    features.GetTxt(),
+		"uefi":   features.GetUefi(),
+		"memory": memory.GetTotal(),
+		"cpus":   cpus,
+		"drives": drives,
+		"nics":   nics,
+	}}
+}
+
 func hwReservationStateRefreshFunc(ctx context.Context, client *metalv1.APIClient, reservationId, instanceId string) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		r, _, err := client.HardwareReservationsApi.FindHardwareReservationById(ctx, reservationId).Include([]string{"device"}).Execute()
@@ -131,16 +350,80 @@ func hwReservationStateRefreshFunc(ctx context.Context, client *metalv1.APIClien
 	}
 }
 
+// getProvisionableHardwareReservations returns the hardware reservations in a
+// project that are free to deploy a device of the given plan. If metro is not
+// empty, only reservations located in that metro are returned.
+func getProvisionableHardwareReservations(ctx context.Context, client *metalv1.APIClient, projectID, plan, metro string) ([]metalv1.HardwareReservation, error) {
+	list, err := client.HardwareReservationsApi.FindProjectHardwareReservations(ctx, projectID).
+		Provisionable(metalv1.FINDPROJECTHARDWARERESERVATIONSPROVISIONABLEPARAMETER_ONLY).
+		Include([]string{"plan", "facility.metro"}).
+		ExecuteWithPagination()
+	if err != nil {
+		return nil, equinix_errors.FriendlyError(err)
+	}
+
+	ret := []metalv1.HardwareReservation{}
+	for _, r := range list.GetHardwareReservations() {
+		if !r.GetProvisionable() || r.Plan.GetSlug() != plan {
+			continue
+		}
+		if metro != "" {
+			facility := r.GetFacility()
+			reservationMetro := facility.GetMetro()
+			if !strings.EqualFold(reservationMetro.GetCode(), metro) {
+				continue
+			}
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
+
 func waitUntilReservationProvisionable(ctx context.Context, client *metalv1.APIClient, reservationId, instanceId string, delay, timeout, minTimeout time.Duration) error {
-	stateConf := &retry.StateChangeConf{
-		Pending:    []string{deprovisioning},
-		Target:     []string{provisionable, reprovisioned},
-		Refresh:    hwReservationStateRefreshFunc(ctx, client, reservationId, instanceId),
-		Timeout:    timeout,
-		Delay:      delay,
-		MinTimeout: minTimeout,
-	}
-	_, err := stateConf.WaitForStateContext(ctx)
+	_, err := wait.ForState(ctx,
+		hwReservationStateRefreshFunc(ctx, client, reservationId, instanceId),
+		[]string{deprovisioning},
+		[]string{provisionable, reprovisioned},
+		timeout,
+		wait.WithDelay(delay),
+		wait.WithMinTimeout(minTimeout),
+	)
+	return err
+}
+
+func deviceDeletionStateRefreshFunc(ctx context.Context, client *metalv1.APIClient, deviceId string) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		device, resp, err := client.DevicesApi.FindDeviceById(ctx, deviceId).Execute()
+		if err != nil {
+			err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+			if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+				// a non-nil result is required, nil is handled as "not found" by the waiter
+				return deviceId, deleted, nil
+			}
+			return nil, errstate, err
+		}
+
+		if device.GetState() == metalv1.DEVICESTATE_DELETED {
+			return device, deleted, nil
+		}
+
+		log.Printf("[DEBUG] Equinix Metal device instance %s is still %s", deviceId, device.GetState())
+		return device, deleting, nil
+	}
+}
+
+// waitUntilDeviceDeleted polls the device until the API no longer returns it,
+// so that dependent resources are not destroyed while the device is still
+// deleting or deprovisioning.
+func waitUntilDeviceDeleted(ctx context.Context, client *metalv1.APIClient, deviceId string, delay, timeout, minTimeout time.Duration) error {
+	_, err := wait.ForState(ctx,
+		deviceDeletionStateRefreshFunc(ctx, client, deviceId),
+		[]string{deleting},
+		[]string{deleted},
+		timeout,
+		wait.WithDelay(delay),
+		wait.WithMinTimeout(minTimeout),
+	)
 	return err
 }
 
@@ -182,15 +465,26 @@ func waitForDeviceAttribute(ctx context.Context, d *schema.ResourceData, stateCo
 	return "", err
 }
 
+// undecodedDeviceState returns the state of a device the API reported
+// successfully but the SDK could not decode, which happens when the device is
+// in a state that is not yet part of the API specification, like
+// firmware_updating.
+func undecodedDeviceState(resp *http.Response, err error) (string, bool) {
+	var apiErr *metalv1.GenericOpenAPIError
+	if resp == nil || resp.StatusCode != http.StatusOK || !errors.As(err, &apiErr) {
+		return "", false
+	}
+	device := struct {
+		State string `json:"state"`
+	}{}
+	if json.Unmarshal(apiErr.Body(), &device) != nil || device.State == "" {
+		return "", false
+	}
+	return device.State, true
+}
+
 func getDeviceMap(device metalv1.Device) map[string]interface{} {
 	networkInfo := getNetworkInfo(device.IpAddresses)
-	sort.SliceStable(networkInfo.Networks, func(i, j int) bool {
-		famI := int(networkInfo.Networks[i]["family"].(int32))
-		famJ := int(networkInfo.Networks[j]["family"].(int32))
-		pubI := networkInfo.Networks[i]["public"].(bool)
-		pubJ := networkInfo.Networks[j]["public"].(bool)
-		return getNetworkRank(famI, pubI) < getNetworkRank(famJ, pubJ)
-	})
 	keyIDs := []string{}
 	for _, k := range device.SshKeys {
 		keyIDs = append(keyIDs, path.Base(k.GetHref()))
@@ -219,5 +513,192 @@ func getDeviceMap(device metalv1.Device) map[string]interface{} {
 		"ssh_key_ids":         keyIDs,
 		"ports":               ports,
 		"sos_hostname":        device.GetSos(),
+		"image_url":           device.GetImageUrl(),
+		"inventory":           getDeviceInventory(&device),
+	}
+}
+
+// configuredIPReservationIDs returns the IP reservations listed in the
+// ip_address blocks of a device
+func configuredIPReservationIDs(d *schema.ResourceData) []string {
+	ids := []string{}
+	for _, a := range d.Get("ip_address").([]interface{}) {
+		address, ok := a.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, id := range address["reservation_ids"].([]interface{}) {
+			ids = append(ids, id.(string))
+		}
+	}
+	return ids
+}
+
+// dedicatedIPReservationIDs returns the IP reservations that were created for
+// the public IPv4 management addresses of a device, leaving out the ones that
+// were configured. Private IPv4 and IPv6 addresses come from blocks shared by
+// the project and addresses attached after the device was created are not
+// management addresses, so neither is included.
+func dedicatedIPReservationIDs(device *metalv1.Device, configured []string) []string {
+	ids := []string{}
+	for _, ip := range device.IpAddresses {
+		if !ip.GetManagement() || !ip.GetPublic() || ip.GetAddressFamily() != 4 {
+			continue
+		}
+		href := ip.ParentBlock.GetHref()
+		if href == "" {
+			continue
+		}
+		id := path.Base(href)
+		if !slices.Contains(configured, id) && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// releaseIPReservations deletes IP reservations that are left without any
+// assignment. Reservations that are gone already are skipped, as are the ones
+// still assigned to another device.
+func releaseIPReservations(ctx context.Context, client *metalv1.APIClient, ids []string) error {
+	for _, id := range ids {
+		ip, resp, err := client.IPAddressesApi.FindIPAddressById(ctx, id).Include([]string{"assignments"}).Execute()
+		gone := resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound)
+		if err != nil && !gone {
+			return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+		if err != nil {
+			continue
+		}
+		if ip.IPReservation == nil {
+			log.Printf("[WARN] IP address (%s) is not an IP reservation, not releasing it", id)
+			continue
+		}
+		if len(ip.IPReservation.GetAssignments()) > 0 {
+			log.Printf("[WARN] IP reservation (%s) is still assigned, not releasing it", id)
+			continue
+		}
+		log.Printf("[DEBUG] Releasing IP reservation (%s)", id)
+		resp, err = client.IPAddressesApi.DeleteIPAddress(ctx, id).Execute()
+		gone = resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound)
+		if err != nil && !gone {
+			return equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+	}
+	return nil
+}
+
+// capacityErrorMessages are fragments of the errors the API returns when there
+// are no servers of the plan left to deploy
+var capacityErrorMessages = []string{"enough capacity", "no capacity", "out of capacity"}
+
+// isCapacityError reports whether a failed device create was rejected because
+// the requested location has no capacity for the plan
+func isCapacityError(resp *http.Response, err error) bool {
+	if resp == nil || err == nil {
+		return false
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable && resp.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+
+	// only the messages of the API are matched, other errors of the create
+	// such as an operating system that is not available for the plan must
+	// not be retried elsewhere
+	for _, message := range equinix_errors.MetalErrorMessages(err) {
+		message = strings.ToLower(message)
+		for _, fragment := range capacityErrorMessages {
+			if strings.Contains(message, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isProjectNotFoundError reports whether a failed device create was rejected
+// because the API does not find the project of the device, as opposed to
+// another missing resource such as the plan, metro or hardware reservation
+func isProjectNotFoundError(resp *http.Response, err error) bool {
+	if resp == nil || err == nil || resp.StatusCode != http.StatusNotFound {
+		return false
+	}
+	for _, message := range equinix_errors.MetalErrorMessages(err) {
+		if message = strings.ToLower(message); strings.HasPrefix(message, "project") && strings.HasSuffix(message, "not found") {
+			return true
+		}
+	}
+	return false
+}
+
+// deviceErrorFields maps the field names the API starts its validation
+// messages with, e.g. "Hostname is invalid", to the device attributes
+var deviceErrorFields = map[string]string{
+	"hostname":                "hostname",
+	"plan":                    "plan",
+	"operating system":        "operating_system",
+	"operating_system":        "operating_system",
+	"userdata":                "user_data",
+	"user data":               "user_data",
+	"customdata":              "custom_data",
+	"custom data":             "custom_data",
+	"ipxe script url":         "ipxe_script_url",
+	"ipxe_script_url":         "ipxe_script_url",
+	"always pxe":              "always_pxe",
+	"billing cycle":           "billing_cycle",
+	"billing_cycle":           "billing_cycle",
+	"metro":                   "metro",
+	"facility":                "facilities",
+	"hardware reservation":    "hardware_reservation_id",
+	"hardware reservation id": "hardware_reservation_id",
+	"project":                 "project_id",
+	"termination time":        "termination_time",
+	"termination_time":        "termination_time",
+	"tags":                    "tags",
+	"description":             "description",
+	"storage":                 "storage",
+	"locked":                  "locked",
+	"ip addresses":            "ip_address",
+	"ip_addresses":            "ip_address",
+	"project ssh keys":        "project_ssh_key_ids",
+	"user ssh keys":           "user_ssh_key_ids",
+}
+
+// deviceErrorAttribute returns the device attribute a validation message is
+// about, or an empty string if the message does not start with a known field.
+// The longest matching field wins, so "Hardware reservation id ..." is not
+// mistaken for another field.
+func deviceErrorAttribute(message string) string {
+	message = strings.ToLower(message)
+	attribute, longest := "", 0
+	for field, attr := range deviceErrorFields {
+		if len(field) > longest && strings.HasPrefix(message, field+" ") {
+			attribute, longest = attr, len(field)
+		}
+	}
+	return attribute
+}
+
+// deviceErrorDiagnostics turns a failed device create or update into one
+// diagnostic per message of the API response, pointing at the offending
+// attribute when the message names one
+func deviceErrorDiagnostics(summary string, resp *http.Response, err error) diag.Diagnostics {
+	messages := equinix_errors.MetalErrorMessages(err)
+	if resp == nil || len(messages) == 0 {
+		return diag.Errorf("%s: %s", summary, equinix_errors.FriendlyError(err))
+	}
+
+	var diags diag.Diagnostics
+	for _, message := range messages {
+		diagnostic := diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s: HTTP %d", summary, resp.StatusCode),
+			Detail:   message,
+		}
+		if attribute := deviceErrorAttribute(message); attribute != "" {
+			diagnostic.AttributePath = cty.GetAttrPath(attribute)
+		}
+		diags = append(diags, diagnostic)
 	}
+	return diags
 }
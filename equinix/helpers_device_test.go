@@ -2,14 +2,20 @@ package equinix
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func Test_waitUntilReservationProvisionable(t *testing.T) {
@@ -170,3 +176,800 @@ func Test_waitUntilReservationProvisionable(t *testing.T) {
 		})
 	}
 }
+
+func Test_waitUntilDeviceDeleted(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+		wantErr bool
+	}{
+		{
+			name: "deleted",
+			handler: (func() func(w http.ResponseWriter, r *http.Request) {
+				invoked := new(int)
+
+				return func(w http.ResponseWriter, r *http.Request) {
+					*invoked++
+					w.Header().Add("Content-Type", "application/json")
+					w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+
+					// the first poll still finds the device, the second does not
+					if *invoked > 1 {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+
+					device := metalv1.Device{Id: metalv1.PtrString("deviceId"), State: metalv1.DEVICESTATE_DEPROVISIONING.Ptr()}
+					body, err := device.MarshalJSON()
+					if err != nil {
+						// This should never be reached and indicates a failure in the test itself
+						panic(err)
+					}
+					w.WriteHeader(http.StatusOK)
+					w.Write(body)
+				}
+			})(),
+			wantErr: false,
+		},
+		{
+			name: "error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			wantErr: true,
+		},
+		{
+			name: "foreverDeleting",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				device := metalv1.Device{Id: metalv1.PtrString("deviceId"), State: metalv1.DEVICESTATE_DEPROVISIONING.Ptr()}
+				body, err := device.MarshalJSON()
+				if err != nil {
+					// This should never be reached and indicates a failure in the test itself
+					panic(err)
+				}
+
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.WriteHeader(http.StatusOK)
+				w.Write(body)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(tt.handler))
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			client := meta.NewMetalClientForTesting()
+			if err := waitUntilDeviceDeleted(ctx, client, "deviceId", 50*time.Millisecond, 1*time.Second, 50*time.Millisecond); (err != nil) != tt.wantErr {
+				t.Errorf("waitUntilDeviceDeleted() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			mockAPI.Close()
+		})
+	}
+}
+
+func Test_restorePortVlanAssignments(t *testing.T) {
+	tests := []struct {
+		name       string
+		before     map[string][]string
+		after      map[string][]string
+		statusCode int
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "unchanged",
+			before:     map[string][]string{"port1": {"vlan1"}},
+			after:      map[string][]string{"port1": {"vlan1"}},
+			statusCode: http.StatusOK,
+			want:       []string{},
+		},
+		{
+			name:       "detached",
+			before:     map[string][]string{"port1": {"vlan1", "vlan2"}, "port2": {"vlan3"}},
+			after:      map[string][]string{"port1": {"vlan2"}},
+			statusCode: http.StatusOK,
+			want:       []string{"port1:vlan1", "port2:vlan3"},
+		},
+		{
+			name:       "error",
+			before:     map[string][]string{"port1": {"vlan1"}},
+			after:      map[string][]string{},
+			statusCode: http.StatusUnprocessableEntity,
+			want:       []string{},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte("{}"))
+			}))
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			client := meta.NewMetalClientForTesting()
+			got, err := restorePortVlanAssignments(ctx, client, tt.before, tt.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("restorePortVlanAssignments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("restorePortVlanAssignments() = %v, want %v", got, tt.want)
+			}
+
+			mockAPI.Close()
+		})
+	}
+}
+
+func Test_validateDeviceStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage string
+		wantErr string
+	}{
+		{
+			name:    "layout",
+			storage: `{"disks":[{"device":"/dev/sda","wipeTable":true,"partitions":[{"label":"BIOS","number":1,"size":"4096"}]}],"filesystems":[{"mount":{"device":"/dev/sda1","format":"ext4","point":"/"}}]}`,
+		},
+		{
+			name:    "not json",
+			storage: `{"disks":`,
+			wantErr: "contains an invalid JSON",
+		},
+		{
+			name:    "partition size as number",
+			storage: `{"disks":[{"device":"/dev/sda","partitions":[{"label":"BIOS","number":1,"size":4096}]}]}`,
+			wantErr: "Partition sizes must be strings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errs := validateDeviceStorage(tt.storage, "storage")
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Fatalf("validateDeviceStorage() errors = %v, want none", errs)
+				}
+				storage, err := expandDeviceStorage(tt.storage)
+				if err != nil {
+					t.Fatalf("expandDeviceStorage() error = %v", err)
+				}
+				if got := storage.Disks[0].Partitions[0].GetSize(); got != "4096" {
+					t.Errorf("expandDeviceStorage() partition size = %q, want %q", got, "4096")
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Errorf("validateDeviceStorage() errors = %v, want an error containing %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_getRaid(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage *metalv1.Storage
+		want    int
+	}{
+		{
+			name:    "nil storage",
+			storage: nil,
+			want:    0,
+		},
+		{
+			name:    "no raid",
+			storage: &metalv1.Storage{},
+			want:    0,
+		},
+		{
+			name: "single array",
+			storage: &metalv1.Storage{
+				Raid: []metalv1.Raid{
+					{
+						Name:    metalv1.PtrString("MD0"),
+						Level:   metalv1.PtrString("1"),
+						Devices: []string{"/dev/sda", "/dev/sdb"},
+					},
+				},
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getRaid(tt.storage)
+			if len(got) != tt.want {
+				t.Fatalf("getRaid() returned %d arrays, want %d", len(got), tt.want)
+			}
+			if tt.want > 0 {
+				if got[0]["name"] != "MD0" || got[0]["level"] != "1" {
+					t.Errorf("getRaid() = %v, unexpected name or level", got[0])
+				}
+				if devices := got[0]["devices"].([]string); len(devices) != 2 {
+					t.Errorf("getRaid() devices = %v, want 2 devices", devices)
+				}
+			}
+		})
+	}
+}
+
+func Test_getPorts(t *testing.T) {
+	ports := []metalv1.Port{
+		{
+			Id:   metalv1.PtrString("bond0-id"),
+			Name: metalv1.PtrString("bond0"),
+			Type: metalv1.PORTTYPE_NETWORK_BOND_PORT.Ptr(),
+			Data: &metalv1.PortData{Bonded: metalv1.PtrBool(true)},
+		},
+		{
+			Id:   metalv1.PtrString("eth0-id"),
+			Name: metalv1.PtrString("eth0"),
+			Type: metalv1.PORTTYPE_NETWORK_PORT.Ptr(),
+			Data: &metalv1.PortData{Bonded: metalv1.PtrBool(true), Mac: metalv1.PtrString("b8:ce:f6:00:00:01")},
+		},
+		{
+			Id:   metalv1.PtrString("eth1-id"),
+			Name: metalv1.PtrString("eth1"),
+			Type: metalv1.PORTTYPE_NETWORK_PORT.Ptr(),
+		},
+	}
+
+	want := []map[string]interface{}{
+		{"name": "bond0", "id": "bond0-id", "type": metalv1.PORTTYPE_NETWORK_BOND_PORT, "mac": "", "bonded": true},
+		{"name": "eth0", "id": "eth0-id", "type": metalv1.PORTTYPE_NETWORK_PORT, "mac": "b8:ce:f6:00:00:01", "bonded": true},
+		{"name": "eth1", "id": "eth1-id", "type": metalv1.PORTTYPE_NETWORK_PORT, "mac": "", "bonded": false},
+	}
+	if got := getPorts(ports); !reflect.DeepEqual(got, want) {
+		t.Errorf("getPorts() = %v, want %v", got, want)
+	}
+}
+
+func Test_getNetworkInfo_reservationID(t *testing.T) {
+	ips := []metalv1.IPAssignment{
+		{
+			Address:       metalv1.PtrString("147.75.0.2"),
+			AddressFamily: metalv1.PtrInt32(4),
+			Public:        metalv1.PtrBool(true),
+			Management:    metalv1.PtrBool(true),
+			ParentBlock: &metalv1.ParentBlock{
+				Href: metalv1.PtrString("/metal/v1/ips/a1b2c3d4-0000-0000-0000-000000000000"),
+			},
+		},
+		{
+			Address:       metalv1.PtrString("10.0.0.2"),
+			AddressFamily: metalv1.PtrInt32(4),
+			Public:        metalv1.PtrBool(false),
+		},
+	}
+
+	ni := getNetworkInfo(ips)
+	if got := ni.Networks[0]["reservation_id"]; got != "a1b2c3d4-0000-0000-0000-000000000000" {
+		t.Errorf("getNetworkInfo() reservation_id = %v, want the parent block ID", got)
+	}
+	if got := ni.Networks[1]["reservation_id"]; got != "" {
+		t.Errorf("getNetworkInfo() reservation_id = %v, want empty without a parent block", got)
+	}
+}
+
+func Test_getNetworkInfo_order(t *testing.T) {
+	ip := func(address string, family int32, public, management bool) metalv1.IPAssignment {
+		return metalv1.IPAssignment{
+			Address:       metalv1.PtrString(address),
+			AddressFamily: metalv1.PtrInt32(family),
+			Public:        metalv1.PtrBool(public),
+			Management:    metalv1.PtrBool(management),
+			Cidr:          metalv1.PtrInt32(31),
+		}
+	}
+	ips := []metalv1.IPAssignment{
+		ip("10.0.0.2", 4, false, true),
+		ip("2604:1380::1", 6, true, true),
+		ip("147.75.0.9", 4, true, false),
+		ip("147.75.0.2", 4, true, true),
+		ip("147.75.0.1", 4, true, false),
+	}
+
+	ni := getNetworkInfo(ips)
+	got := []string{}
+	for _, n := range ni.Networks {
+		got = append(got, n["address"].(string))
+	}
+	want := []string{"147.75.0.2", "147.75.0.1", "147.75.0.9", "2604:1380::1", "10.0.0.2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getNetworkInfo() addresses = %v, want %v", got, want)
+	}
+
+	state := []interface{}{}
+	for _, n := range ni.Networks {
+		state = append(state, map[string]interface{}{"address": n["address"], "cidr": int(n["cidr"].(int32))})
+	}
+	if networksChanged(state, getNetworkInfo(ips).Networks) {
+		t.Errorf("networksChanged() = true, want false for the same assignments")
+	}
+	if !networksChanged(state, getNetworkInfo(ips[:4]).Networks) {
+		t.Errorf("networksChanged() = false, want true after an address was detached")
+	}
+	ips[2].Cidr = metalv1.PtrInt32(30)
+	if !networksChanged(state, getNetworkInfo(ips).Networks) {
+		t.Errorf("networksChanged() = false, want true after an address changed")
+	}
+}
+
+func Test_getDeviceInventory(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	device := &metalv1.Device{
+		Id:         metalv1.PtrString("device"),
+		Hostname:   metalv1.PtrString("worker-0"),
+		Project:    &metalv1.Project{Id: metalv1.PtrString("project")},
+		Facility:   &metalv1.Facility{Code: metalv1.PtrString("SV15"), Metro: &metalv1.DeviceMetro{Code: metalv1.PtrString("SV")}},
+		Plan:       &metalv1.Plan{Slug: metalv1.PtrString("c3.small.x86")},
+		State:      metalv1.DEVICESTATE_ACTIVE.Ptr(),
+		Iqn:        metalv1.PtrString("iqn.2024-05.net.packet:device.abc"),
+		SwitchUuid: metalv1.PtrString("switch"),
+		CreatedAt:  &created,
+	}
+
+	got := getDeviceInventory(device)
+	want := []map[string]interface{}{{
+		"id":          "device",
+		"hostname":    "worker-0",
+		"project_id":  "project",
+		"metro":       "sv",
+		"facility":    "sv15",
+		"plan":        "c3.small.x86",
+		"state":       "active",
+		"iqn":         "iqn.2024-05.net.packet:device.abc",
+		"switch_uuid": "switch",
+		"created":     "2024-05-01T10:00:00Z",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getDeviceInventory() = %v, want %v", got, want)
+	}
+
+	if got := getDeviceInventory(&metalv1.Device{}); got[0]["metro"] != "" || got[0]["created"] != "" {
+		t.Errorf("getDeviceInventory() of an empty device = %v, want empty values", got)
+	}
+}
+
+func Test_getHardwareDetails(t *testing.T) {
+	if got := getHardwareDetails(nil); len(got) != 0 {
+		t.Errorf("getHardwareDetails(nil) = %v, want empty", got)
+	}
+	if got := getHardwareDetails(&metalv1.Plan{}); len(got) != 0 {
+		t.Errorf("getHardwareDetails() without specs = %v, want empty", got)
+	}
+
+	plan := &metalv1.Plan{
+		Specs: &metalv1.PlanSpecs{
+			Cpus:   []metalv1.PlanSpecsCpusInner{{Count: metalv1.PtrInt32(1), Type: metalv1.PtrString("Intel Xeon E-2278G")}},
+			Memory: &metalv1.PlanSpecsMemory{Total: metalv1.PtrString("32GB")},
+			Drives: []metalv1.PlanSpecsDrivesInner{{
+				Count:    metalv1.PtrInt32(2),
+				Type:     metalv1.PtrString("SSD"),
+				Size:     metalv1.PtrString("480GB"),
+				Category: metalv1.PLANSPECSDRIVESINNERCATEGORY_BOOT.Ptr(),
+			}},
+			Nics:     []metalv1.PlanSpecsNicsInner{{Count: metalv1.PtrInt32(2), Type: metalv1.PtrString("10Gbps")}},
+			Features: &metalv1.PlanSpecsFeatures{Raid: metalv1.PtrBool(true), Txt: metalv1.PtrBool(true)},
+		},
+	}
+
+	got := getHardwareDetails(plan)
+	if len(got) != 1 {
+		t.Fatalf("getHardwareDetails() returned %d elements, want 1", len(got))
+	}
+	hw := got[0]
+	if hw["raid"] != true || hw["txt"] != true || hw["uefi"] != false {
+		t.Errorf("getHardwareDetails() features = %v, unexpected values", hw)
+	}
+	if hw["memory"] != "32GB" {
+		t.Errorf("getHardwareDetails() memory = %v, want 32GB", hw["memory"])
+	}
+	drives := hw["drives"].([]map[string]interface{})
+	if len(drives) != 1 || drives[0]["category"] != "boot" || drives[0]["size"] != "480GB" {
+		t.Errorf("getHardwareDetails() drives = %v, unexpected values", drives)
+	}
+	if cpus := hw["cpus"].([]map[string]interface{}); len(cpus) != 1 {
+		t.Errorf("getHardwareDetails() cpus = %v, want 1 element", cpus)
+	}
+
+	for name, r := range map[string]*schema.Resource{
+		"resource":    resourceMetalDevice(),
+		"data source": dataSourceMetalDevice(),
+	} {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+		if err := d.Set("hardware_details", got); err != nil {
+			t.Errorf("setting %s hardware_details failed: %v", name, err)
+		}
+		if d.Get("hardware_details.0.drives.0.count").(int) != 2 {
+			t.Errorf("%s hardware_details.0.drives.0.count = %v, want 2", name, d.Get("hardware_details.0.drives.0.count"))
+		}
+		if d.Get("hardware_details.0.drives.0.size").(string) != "480GB" {
+			t.Errorf("%s hardware_details.0.drives.0.size = %v, want 480GB", name, d.Get("hardware_details.0.drives.0.size"))
+		}
+	}
+}
+
+func Test_getProvisionableHardwareReservations(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		list := metalv1.HardwareReservationList{
+			HardwareReservations: []metalv1.HardwareReservation{
+				{
+					Id:            metalv1.PtrString("match"),
+					Provisionable: metalv1.PtrBool(true),
+					Plan:          &metalv1.Plan{Slug: metalv1.PtrString("c3.small.x86")},
+					Facility:      &metalv1.Facility{Metro: &metalv1.DeviceMetro{Code: metalv1.PtrString("SV")}},
+				},
+				{
+					Id:            metalv1.PtrString("other-plan"),
+					Provisionable: metalv1.PtrBool(true),
+					Plan:          &metalv1.Plan{Slug: metalv1.PtrString("m3.large.x86")},
+					Facility:      &metalv1.Facility{Metro: &metalv1.DeviceMetro{Code: metalv1.PtrString("SV")}},
+				},
+				{
+					Id:            metalv1.PtrString("other-metro"),
+					Provisionable: metalv1.PtrBool(true),
+					Plan:          &metalv1.Plan{Slug: metalv1.PtrString("c3.small.x86")},
+					Facility:      &metalv1.Facility{Metro: &metalv1.DeviceMetro{Code: metalv1.PtrString("DA")}},
+				},
+			},
+		}
+
+		body, err := list.MarshalJSON()
+		if err != nil {
+			// This should never be reached and indicates a failure in the test itself
+			panic(err)
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+
+	tests := []struct {
+		name  string
+		plan  string
+		metro string
+		want  []string
+	}{
+		{
+			name: "any metro",
+			plan: "c3.small.x86",
+			want: []string{"match", "other-metro"},
+		},
+		{
+			name:  "metro filter",
+			plan:  "c3.small.x86",
+			metro: "sv",
+			want:  []string{"match"},
+		},
+		{
+			name:  "no match",
+			plan:  "n3.xlarge.x86",
+			metro: "sv",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(handler))
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			client := meta.NewMetalClientForTesting()
+			got, err := getProvisionableHardwareReservations(ctx, client, "projectId", tt.plan, tt.metro)
+			if err != nil {
+				t.Fatalf("getProvisionableHardwareReservations() error = %v", err)
+			}
+			ids := []string{}
+			for _, r := range got {
+				ids = append(ids, r.GetId())
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("getProvisionableHardwareReservations() = %v, want %v", ids, tt.want)
+			}
+
+			mockAPI.Close()
+		})
+	}
+}
+
+func TestUndecodedDeviceState(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "firmware updating",
+			status: http.StatusOK,
+			body:   `{"id": "deviceId", "state": "firmware_updating"}`,
+			want:   firmwareUpdating,
+			wantOk: true,
+		},
+		{
+			name:   "known state",
+			status: http.StatusOK,
+			body:   `{"id": "deviceId", "state": "active"}`,
+		},
+		{
+			name:   "not found",
+			status: http.StatusNotFound,
+			body:   `{"errors": ["Not found"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			client := meta.NewMetalClientForTesting()
+			_, resp, err := client.DevicesApi.FindDeviceById(ctx, "deviceId").Execute()
+			got, ok := undecodedDeviceState(resp, err)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("undecodedDeviceState() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_dedicatedIPReservationIDs(t *testing.T) {
+	ip := func(reservationID string, family int32, public, management bool) metalv1.IPAssignment {
+		return metalv1.IPAssignment{
+			AddressFamily: metalv1.PtrInt32(family),
+			Public:        metalv1.PtrBool(public),
+			Management:    metalv1.PtrBool(management),
+			ParentBlock:   &metalv1.ParentBlock{Href: metalv1.PtrString("/metal/v1/ips/" + reservationID)},
+		}
+	}
+	device := &metalv1.Device{IpAddresses: []metalv1.IPAssignment{
+		ip("dedicated", 4, true, true),
+		ip("dedicated", 4, true, true),
+		ip("configured", 4, true, true),
+		ip("attached", 4, true, false),
+		ip("projectPrivate", 4, false, true),
+		ip("projectIPv6", 6, true, true),
+	}}
+
+	got := dedicatedIPReservationIDs(device, []string{"configured"})
+	if want := []string{"dedicated"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dedicatedIPReservationIDs() = %v, want %v", got, want)
+	}
+}
+
+func Test_releaseIPReservations(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		unassignedID = "a1b2c3d4-0000-0000-0000-000000000001"
+		assignedID   = "a1b2c3d4-0000-0000-0000-000000000002"
+		goneID       = "a1b2c3d4-0000-0000-0000-000000000003"
+	)
+
+	removed := []string{}
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		switch {
+		case r.Method == http.MethodGet && id == unassignedID:
+			w.Write([]byte(`{"id": "` + unassignedID + `", "type": "public_ipv4", "assignments": []}`))
+		case r.Method == http.MethodGet && id == assignedID:
+			w.Write([]byte(`{"id": "` + assignedID + `", "type": "public_ipv4", "assignments": [{"address": "147.75.0.2"}]}`))
+		case r.Method == http.MethodGet && id == goneID:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": ["not found"]}`))
+		case r.Method == http.MethodDelete:
+			removed = append(removed, id)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	if err := releaseIPReservations(ctx, meta.NewMetalClientForTesting(), []string{unassignedID, assignedID, goneID}); err != nil {
+		t.Fatalf("releaseIPReservations() error = %v", err)
+	}
+	if want := []string{unassignedID}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("releaseIPReservations() removed %v, want %v", removed, want)
+	}
+}
+
+func Test_drainDevice(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		status   int
+		wantTags []string
+		wantErr  bool
+	}{
+		{
+			name:     "tagged",
+			tags:     []string{"worker"},
+			status:   http.StatusOK,
+			wantTags: []string{"worker", "drain"},
+		},
+		{
+			name:   "alreadyTagged",
+			tags:   []string{"drain"},
+			status: http.StatusOK,
+		},
+		{
+			name:   "gone",
+			status: http.StatusNotFound,
+		},
+		{
+			name:    "error",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			var gotTags []string
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+
+				if r.Method == http.MethodPut {
+					input := metalv1.DeviceUpdateInput{}
+					if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+						t.Fatal(err)
+					}
+					gotTags = input.Tags
+				} else if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					return
+				}
+
+				device := metalv1.Device{Id: metalv1.PtrString("deviceId"), Tags: tt.tags}
+				body, err := device.MarshalJSON()
+				if err != nil {
+					// This should never be reached and indicates a failure in the test itself
+					panic(err)
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write(body)
+			}))
+			defer mockAPI.Close()
+
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			client := meta.NewMetalClientForTesting()
+			if err := drainDevice(ctx, client, "deviceId", "drain", 0); (err != nil) != tt.wantErr {
+				t.Errorf("drainDevice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(gotTags, tt.wantTags) {
+				t.Errorf("drainDevice() tags = %v, want %v", gotTags, tt.wantTags)
+			}
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Content-Type", "application/json")
+			w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "deviceId", "tags": ["drain"]}`))
+		}))
+		defer mockAPI.Close()
+
+		meta := &config.Config{
+			BaseURL: mockAPI.URL,
+			Token:   "fakeTokenForMock",
+		}
+		meta.Load(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		if err := drainDevice(ctx, meta.NewMetalClientForTesting(), "deviceId", "drain", time.Hour); err == nil {
+			t.Error("drainDevice() expected an error when the context is done")
+		}
+	})
+}
+
+func Test_deviceErrorDiagnostics(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"errors": ["Hostname is invalid", "Operating system is not supported on this plan", "Hardware reservation id is not provisionable", "Something went wrong"]}`))
+	}))
+	defer mockAPI.Close()
+
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	client := meta.NewMetalClientForTesting()
+	_, resp, err := client.DevicesApi.UpdateDevice(ctx, "deviceId").DeviceUpdateInput(metalv1.DeviceUpdateInput{}).Execute()
+	if err == nil {
+		t.Fatal("UpdateDevice() expected an error")
+	}
+
+	diags := deviceErrorDiagnostics("Error updating device", resp, err)
+	want := []string{"hostname", "operating_system", "hardware_reservation_id", ""}
+	if len(diags) != len(want) {
+		t.Fatalf("deviceErrorDiagnostics() = %v, want %d diagnostics", diags, len(want))
+	}
+	for i, attribute := range want {
+		if diags[i].Summary != "Error updating device: HTTP 422" {
+			t.Errorf("deviceErrorDiagnostics()[%d] summary = %q", i, diags[i].Summary)
+		}
+		wantPath := cty.Path(nil)
+		if attribute != "" {
+			wantPath = cty.GetAttrPath(attribute)
+		}
+		if !diags[i].AttributePath.Equals(wantPath) {
+			t.Errorf("deviceErrorDiagnostics()[%d] path = %#v, want %s", i, diags[i].AttributePath, attribute)
+		}
+	}
+
+	if diags := deviceErrorDiagnostics("Error updating device", nil, errors.New("connection refused")); len(diags) != 1 || diags[0].AttributePath != nil {
+		t.Errorf("deviceErrorDiagnostics() = %v, want a single diagnostic without attribute", diags)
+	}
+}
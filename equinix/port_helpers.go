@@ -4,14 +4,15 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/packethost/packngo"
 	"github.com/pkg/errors"
@@ -110,10 +111,26 @@ func getCurrentNative(p *packngo.Port) string {
 	return currentNative
 }
 
-func attachedVlanIds(p *packngo.Port) []string {
+// usesVxlanIds reports whether the VLANs of the port are compared by VXLAN.
+// Both vlan_ids and vxlan_ids are read back from the port, and a change of
+// one makes the other unknown, so vlan_ids is only left unset when the
+// configured vxlan_ids changed.
+func usesVxlanIds(d *schema.ResourceData) bool {
+	_, vlanIdsOk := d.GetOk("vlan_ids")
+	return !vlanIdsOk
+}
+
+// attachedVlanIds lists the VLANs attached to the port, as VXLAN IDs if the
+// resource uses vxlan_ids and as UUIDs otherwise, so that they compare with
+// the ones from specifiedVlanIds
+func attachedVlanIds(p *packngo.Port, d *schema.ResourceData) []string {
 	attached := []string{}
 	for _, v := range p.AttachedVirtualNetworks {
-		attached = append(attached, v.ID)
+		if usesVxlanIds(d) {
+			attached = append(attached, strconv.Itoa(v.VXLAN))
+		} else {
+			attached = append(attached, v.ID)
+		}
 	}
 	return attached
 }
@@ -137,7 +154,7 @@ func batchVlans(ctx context.Context, start time.Time, removeOnly bool) func(*Cli
 		var vlansToAssign []string
 		var currentNative string
 		vlansToRemove := converters.Difference(
-			attachedVlanIds(cpr.Port),
+			attachedVlanIds(cpr.Port, cpr.Resource),
 			specifiedVlanIds(cpr.Resource),
 		)
 		if !removeOnly {
@@ -145,7 +162,7 @@ func batchVlans(ctx context.Context, start time.Time, removeOnly bool) func(*Cli
 
 			vlansToAssign = converters.Difference(
 				specifiedVlanIds(cpr.Resource),
-				attachedVlanIds(cpr.Port),
+				attachedVlanIds(cpr.Port, cpr.Resource),
 			)
 		}
 		vacr := &packngo.VLANAssignmentBatchCreateRequest{}
@@ -185,31 +202,39 @@ func createAndWaitForBatch(ctx context.Context, start time.Time, cpr *ClientPort
 	// originally set timeout in ctx by TF
 	ctxTimeout := deadline.Sub(start)
 
-	stateChangeConf := &retry.StateChangeConf{
-		Delay:      5 * time.Second,
-		Pending:    []string{string(packngo.VLANAssignmentBatchQueued), string(packngo.VLANAssignmentBatchInProgress)},
-		Target:     []string{string(packngo.VLANAssignmentBatchCompleted)},
-		MinTimeout: 5 * time.Second,
-		Timeout:    ctxTimeout - time.Since(start) - 30*time.Second,
-		Refresh: func() (result interface{}, state string, err error) {
-			b, _, err := c.VLANAssignments.GetBatch(portID, b.ID, nil)
-			switch b.State {
-			case packngo.VLANAssignmentBatchFailed:
-				return b, string(packngo.VLANAssignmentBatchFailed),
-					fmt.Errorf("vlan assignment batch %s provisioning failed: %s", b.ID, strings.Join(b.ErrorMessages, "; "))
-			case packngo.VLANAssignmentBatchCompleted:
-				return b, string(packngo.VLANAssignmentBatchCompleted), nil
-			default:
-				if err != nil {
-					return b, "", fmt.Errorf("vlan assignment batch %s could not be polled: %w", b.ID, err)
-				}
-				return b, string(b.State), err
+	refresh := func() (result interface{}, state string, err error) {
+		b, _, err := c.VLANAssignments.GetBatch(portID, b.ID, nil)
+		switch b.State {
+		case packngo.VLANAssignmentBatchFailed:
+			return b, string(packngo.VLANAssignmentBatchFailed),
+				fmt.Errorf("vlan assignment batch %s provisioning failed: %s", b.ID, strings.Join(b.ErrorMessages, "; "))
+		case packngo.VLANAssignmentBatchCompleted:
+			return b, string(packngo.VLANAssignmentBatchCompleted), nil
+		default:
+			if err != nil {
+				return b, "", fmt.Errorf("vlan assignment batch %s could not be polled: %w", b.ID, err)
 			}
-		},
+			return b, string(b.State), err
+		}
 	}
-	if _, err = stateChangeConf.WaitForStateContext(ctx); err != nil {
+	pending := []string{string(packngo.VLANAssignmentBatchQueued), string(packngo.VLANAssignmentBatchInProgress)}
+	target := []string{string(packngo.VLANAssignmentBatchCompleted)}
+	if _, err = wait.ForState(ctx, refresh, pending, target, wait.Remaining(ctxTimeout, start),
+		wait.WithDelay(5*time.Second),
+		wait.WithMinTimeout(5*time.Second),
+	); err != nil {
 		return errors.Wrapf(err, "vlan assignment batch %s is not complete after timeout", b.ID)
 	}
+
+	// later steps compare against the VLANs attached to the port
+	port, _, err := c.Ports.Get(portID, &packngo.GetOptions{Includes: []string{
+		"native_virtual_network",
+		"virtual_networks",
+	}})
+	if err != nil {
+		return err
+	}
+	*(cpr.Port) = *port
 	return nil
 }
 
@@ -308,13 +333,13 @@ func convertToL3(cpr *ClientPortResource) error {
 func portSanityChecks(cpr *ClientPortResource) error {
 	isBondPort := cpr.Port.Type == "NetworkBondPort"
 
-	// Constraint: Only bond ports have layer2 mode
-	l2Raw, l2Ok := cpr.Resource.GetOkExists("layer2")
-	if !isBondPort && l2Ok {
+	// Constraint: Only bond ports have layer2 mode. layer2 is computed, so
+	// look at the configuration rather than the value kept from state
+	if !isBondPort && layer2Configured(cpr.Resource) {
 		return fmt.Errorf("layer2 flag can be set only for bond ports")
 	}
 
-	l2 := l2Raw.(bool)
+	l2 := cpr.Resource.Get("layer2").(bool)
 
 	bonded := cpr.Resource.Get("bonded").(bool)
 
@@ -341,6 +366,14 @@ func portSanityChecks(cpr *ClientPortResource) error {
 	return nil
 }
 
+func layer2Configured(d *schema.ResourceData) bool {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return false
+	}
+	return !raw.GetAttr("layer2").IsNull()
+}
+
 func portProperlyDestroyed(port *packngo.Port) error {
 	var errs []string
 	if !port.Data.Bonded {
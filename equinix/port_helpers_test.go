@@ -0,0 +1,74 @@
+package equinix
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+)
+
+func TestPortVlanChanges(t *testing.T) {
+	port := &packngo.Port{AttachedVirtualNetworks: []packngo.VirtualNetwork{
+		{ID: "vlan-1000", VXLAN: 1000},
+		{ID: "vlan-1001", VXLAN: 1001},
+	}}
+
+	tests := []struct {
+		name       string
+		raw        map[string]interface{}
+		wantRemove []string
+		wantAssign []string
+	}{
+		{
+			name:       "same vlan_ids",
+			raw:        map[string]interface{}{"vlan_ids": []interface{}{"vlan-1000", "vlan-1001"}},
+			wantRemove: []string{},
+			wantAssign: []string{},
+		},
+		{
+			name:       "same vxlan_ids",
+			raw:        map[string]interface{}{"vxlan_ids": []interface{}{1000, 1001}},
+			wantRemove: []string{},
+			wantAssign: []string{},
+		},
+		{
+			name:       "vlan attached out of band",
+			raw:        map[string]interface{}{"vlan_ids": []interface{}{"vlan-1000"}},
+			wantRemove: []string{"vlan-1001"},
+			wantAssign: []string{},
+		},
+		{
+			name:       "vxlan attached out of band and another one added",
+			raw:        map[string]interface{}{"vxlan_ids": []interface{}{1000, 1002}},
+			wantRemove: []string{"1001"},
+			wantAssign: []string{"1002"},
+		},
+		{
+			name:       "all removed",
+			raw:        map[string]interface{}{},
+			wantRemove: []string{"1000", "1001"},
+			wantAssign: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{"port_id": "portId", "bonded": true}
+			for k, v := range tt.raw {
+				raw[k] = v
+			}
+			d := schema.TestResourceDataRaw(t, resourceMetalPort().Schema, raw)
+
+			remove := converters.Difference(attachedVlanIds(port, d), specifiedVlanIds(d))
+			assign := converters.Difference(specifiedVlanIds(d), attachedVlanIds(port, d))
+			if !slices.Equal(remove, tt.wantRemove) {
+				t.Errorf("VLANs to remove = %v, want %v", remove, tt.wantRemove)
+			}
+			if !slices.Equal(assign, tt.wantAssign) {
+				t.Errorf("VLANs to assign = %v, want %v", assign, tt.wantAssign)
+			}
+		})
+	}
+}
@@ -70,51 +70,54 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Default:     10,
-				Description: "Maximum number of retries.",
+				Description: "Maximum number of retries after network failures, rate limited (429) responses, and server errors (5xx) of idempotent requests.",
 			},
 			"max_retry_wait_seconds": {
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Default:     30,
-				Description: "Maximum number of seconds to wait before retrying a request.",
+				Description: "Maximum number of seconds to wait before retrying a request, including waits requested by a Retry-After header.",
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"equinix_ecx_port":                   dataSourceECXPort(),
-			"equinix_ecx_l2_sellerprofile":       dataSourceECXL2SellerProfile(),
-			"equinix_ecx_l2_sellerprofiles":      dataSourceECXL2SellerProfiles(),
-			"equinix_fabric_routing_protocol":    dataSourceRoutingProtocol(),
-			"equinix_fabric_connection":          fabric_connection.DataSource(),
-			"equinix_fabric_connections":         fabric_connection.DataSourceSearch(),
-			"equinix_fabric_cloud_router":        dataSourceFabricCloudRouter(),
-			"equinix_fabric_cloud_routers":       dataSourceFabricGetCloudRouters(),
-			"equinix_fabric_network":             fabric_network.DataSource(),
-			"equinix_fabric_networks":            fabric_network.DataSourceSearch(),
-			"equinix_fabric_port":                dataSourceFabricPort(),
-			"equinix_fabric_ports":               dataSourceFabricGetPortsByName(),
-			"equinix_fabric_service_profile":     dataSourceFabricServiceProfileReadByUuid(),
-			"equinix_fabric_service_profiles":    dataSourceFabricSearchServiceProfilesByName(),
-			"equinix_network_account":            dataSourceNetworkAccount(),
-			"equinix_network_device":             dataSourceNetworkDevice(),
-			"equinix_network_device_type":        dataSourceNetworkDeviceType(),
-			"equinix_network_device_software":    dataSourceNetworkDeviceSoftware(),
-			"equinix_network_device_platform":    dataSourceNetworkDevicePlatform(),
-			"equinix_metal_hardware_reservation": dataSourceMetalHardwareReservation(),
-			"equinix_metal_metro":                dataSourceMetalMetro(),
-			"equinix_metal_facility":             dataSourceMetalFacility(),
-			"equinix_metal_ip_block_ranges":      dataSourceMetalIPBlockRanges(),
-			"equinix_metal_precreated_ip_block":  dataSourceMetalPreCreatedIPBlock(),
-			"equinix_metal_operating_system":     dataSourceOperatingSystem(),
-			"equinix_metal_spot_market_price":    dataSourceSpotMarketPrice(),
-			"equinix_metal_device":               dataSourceMetalDevice(),
-			"equinix_metal_devices":              dataSourceMetalDevices(),
-			"equinix_metal_device_bgp_neighbors": dataSourceMetalDeviceBGPNeighbors(),
-			"equinix_metal_plans":                dataSourceMetalPlans(),
-			"equinix_metal_port":                 dataSourceMetalPort(),
-			"equinix_metal_reserved_ip_block":    dataSourceMetalReservedIPBlock(),
-			"equinix_metal_spot_market_request":  dataSourceMetalSpotMarketRequest(),
-			"equinix_metal_virtual_circuit":      virtual_circuit.DataSource(),
-			"equinix_metal_vrf":                  vrf.DataSource(),
+			"equinix_ecx_port":                     dataSourceECXPort(),
+			"equinix_ecx_l2_sellerprofile":         dataSourceECXL2SellerProfile(),
+			"equinix_ecx_l2_sellerprofiles":        dataSourceECXL2SellerProfiles(),
+			"equinix_fabric_routing_protocol":      dataSourceRoutingProtocol(),
+			"equinix_fabric_connection":            fabric_connection.DataSource(),
+			"equinix_fabric_connections":           fabric_connection.DataSourceSearch(),
+			"equinix_fabric_connection_statistics": fabric_connection.DataSourceStatistics(),
+			"equinix_fabric_cloud_router":          dataSourceFabricCloudRouter(),
+			"equinix_fabric_cloud_routers":         dataSourceFabricGetCloudRouters(),
+			"equinix_fabric_network":               fabric_network.DataSource(),
+			"equinix_fabric_networks":              fabric_network.DataSourceSearch(),
+			"equinix_fabric_port":                  dataSourceFabricPort(),
+			"equinix_fabric_ports":                 dataSourceFabricGetPortsByName(),
+			"equinix_fabric_service_profile":       dataSourceFabricServiceProfileReadByUuid(),
+			"equinix_fabric_service_profiles":      dataSourceFabricSearchServiceProfilesByName(),
+			"equinix_network_account":              dataSourceNetworkAccount(),
+			"equinix_network_device":               dataSourceNetworkDevice(),
+			"equinix_network_device_type":          dataSourceNetworkDeviceType(),
+			"equinix_network_device_software":      dataSourceNetworkDeviceSoftware(),
+			"equinix_network_device_platform":      dataSourceNetworkDevicePlatform(),
+			"equinix_metal_hardware_reservation":   dataSourceMetalHardwareReservation(),
+			"equinix_metal_metro":                  dataSourceMetalMetro(),
+			"equinix_metal_metros":                 dataSourceMetalMetros(),
+			"equinix_metal_facility":               dataSourceMetalFacility(),
+			"equinix_metal_ip_block_ranges":        dataSourceMetalIPBlockRanges(),
+			"equinix_metal_ip_attachment":          dataSourceMetalIPAttachment(),
+			"equinix_metal_precreated_ip_block":    dataSourceMetalPreCreatedIPBlock(),
+			"equinix_metal_operating_system":       dataSourceOperatingSystem(),
+			"equinix_metal_spot_market_price":      dataSourceSpotMarketPrice(),
+			"equinix_metal_device":                 dataSourceMetalDevice(),
+			"equinix_metal_devices":                dataSourceMetalDevices(),
+			"equinix_metal_device_bgp_neighbors":   dataSourceMetalDeviceBGPNeighbors(),
+			"equinix_metal_plans":                  dataSourceMetalPlans(),
+			"equinix_metal_port":                   dataSourceMetalPort(),
+			"equinix_metal_reserved_ip_block":      dataSourceMetalReservedIPBlock(),
+			"equinix_metal_spot_market_request":    dataSourceMetalSpotMarketRequest(),
+			"equinix_metal_virtual_circuit":        virtual_circuit.DataSource(),
+			"equinix_metal_vrf":                    vrf.DataSource(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"equinix_ecx_l2_connection":          resourceECXL2Connection(),
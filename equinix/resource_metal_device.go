@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math/bits"
+	"net/http"
 	"path"
 	"reflect"
 	"regexp"
@@ -16,13 +18,16 @@ import (
 	"slices"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	"github.com/equinix/terraform-provider-equinix/internal/mutexkv"
 	"github.com/equinix/terraform-provider-equinix/internal/network"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
@@ -34,10 +39,26 @@ import (
 var (
 	matchIPXEScript = regexp.MustCompile(`(?i)^#![i]?pxe`)
 	ipAddressTypes  = []string{"public_ipv4", "private_ipv4", "public_ipv6"}
+
+	// publicIPv4SubnetSizes are the sizes of the public IPv4 subnet a device
+	// can be deployed with
+	publicIPv4SubnetSizes = []int{2, 4, 8, 16}
+)
+
+// projectNotFoundTimeout bounds how long a device create is retried while the
+// API reports its project as not found, which happens for a few seconds after
+// the project was created
+var (
+	projectNotFoundTimeout  = 10 * time.Second
+	projectNotFoundInterval = 2 * time.Second
 )
 
+// maxDeviceMetadataSize is the largest user_data or custom_data, in bytes,
+// the Metal API accepts for a device
+const maxDeviceMetadataSize = 64 * 1024
+
 var (
-	deviceCommonIncludes = []string{"project", "metro", "facility", "hardware_reservation"}
+	deviceCommonIncludes = []string{"project", "metro", "facility", "hardware_reservation", "plan"}
 )
 
 func resourceMetalDevice() *schema.Resource {
@@ -52,12 +73,12 @@ func resourceMetalDevice() *schema.Resource {
 		UpdateContext:      resourceMetalDeviceUpdate,
 		DeleteContext:      resourceMetalDeviceDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceMetalDeviceImportState,
 		},
 		Schema: map[string]*schema.Schema{
 			"project_id": {
 				Type:        schema.TypeString,
-				Description: "The ID of the project in which to create the device",
+				Description: "The ID of the project in which to create the device. Changing this re-creates the device, as the Metal API cannot move devices between projects",
 				Required:    true,
 				ForceNew:    true,
 			},
@@ -90,11 +111,11 @@ func resourceMetalDevice() *schema.Resource {
 			},
 
 			"metro": {
-				Type:          schema.TypeString,
-				Description:   "Metro area for the new device. Conflicts with facilities",
-				Optional:      true,
-				ForceNew:      true,
-				ConflictsWith: []string{"facilities"},
+				Type:         schema.TypeString,
+				Description:  "Metro area for the new device. Exactly one of metro and facilities must be set",
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"metro", "facilities"},
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
 					if len(old) > 0 && new == "" {
 						// here it would be good to also test if the "old" metro
@@ -107,9 +128,47 @@ func resourceMetalDevice() *schema.Resource {
 				},
 				StateFunc: converters.ToLowerIf,
 			},
+			"metro_fallback": {
+				Type:         schema.TypeList,
+				Description:  "Ordered list of metros to try when metro has no capacity for the plan. The metro the device was deployed in is reported in deployed_metro",
+				Optional:     true,
+				RequiredWith: []string{"metro"},
+				Elem: &schema.Schema{
+					Type:      schema.TypeString,
+					StateFunc: converters.ToLowerIf,
+				},
+			},
+			"deployed_metro": {
+				Type:        schema.TypeString,
+				Description: "The metro where the device is deployed",
+				Computed:    true,
+			},
+			"preferred_facility": {
+				Type:         schema.TypeString,
+				Description:  "Facility within the metro where the device should be deployed. If the facility has no capacity for the plan, the create fails unless preferred_facility_fallback is enabled",
+				Optional:     true,
+				ForceNew:     true,
+				RequiredWith: []string{"metro"},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					// not known after an import, keep the device when it is
+					// deployed in the configured facility
+					if old == "" && d.Id() != "" {
+						return strings.EqualFold(new, d.Get("deployed_facility").(string))
+					}
+					return strings.EqualFold(old, new)
+				},
+				StateFunc: converters.ToLowerIf,
+			},
+			"preferred_facility_fallback": {
+				Type:         schema.TypeBool,
+				Description:  "Whether the device should be deployed anywhere in the metro if preferred_facility has no capacity for the plan",
+				Optional:     true,
+				Default:      false,
+				RequiredWith: []string{"preferred_facility"},
+			},
 			"facilities": {
 				Type:        schema.TypeList,
-				Description: "List of facility codes with deployment preferences. Equinix Metal API will go through the list and will deploy your device to first facility with free capacity. List items must be facility codes or any (a wildcard). To find the facility code, visit [Facilities API docs](https://metal.equinix.com/developers/api/facilities/), set your API auth token in the top of the page and see JSON from the API response. Conflicts with metro",
+				Description: "List of facility codes with deployment preferences. Equinix Metal API will go through the list and will deploy your device to first facility with free capacity. List items must be facility codes or any (a wildcard). To find the facility code, visit [Facilities API docs](https://metal.equinix.com/developers/api/facilities/), set your API auth token in the top of the page and see JSON from the API response. Exactly one of metro and facilities must be set",
 				Deprecated:  "Use metro instead of facilities.  For more information, read the migration guide: https://registry.terraform.io/providers/equinix/equinix/latest/docs/guides/migration_guide_facilities_to_metros_devices",
 				Optional:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
@@ -127,7 +186,7 @@ func resourceMetalDevice() *schema.Resource {
 					}
 					return false
 				},
-				ConflictsWith: []string{"metro"},
+				ExactlyOneOf: []string{"metro", "facilities"},
 			},
 			"ip_address": {
 				Type:        schema.TypeList,
@@ -160,11 +219,21 @@ func resourceMetalDevice() *schema.Resource {
 				},
 				MinItems: 1,
 			},
+			"public_ipv4_subnet_size": {
+				Type:          schema.TypeInt,
+				Description:   fmt.Sprintf("Number of addresses in the public IPv4 subnet of the device, one of %v. Conflicts with ip_address", publicIPv4SubnetSizes),
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ValidateFunc:  validation.IntInSlice(publicIPv4SubnetSizes),
+				ConflictsWith: []string{"ip_address"},
+			},
 			"plan": {
-				Type:        schema.TypeString,
-				Description: "The device plan slug. To find the plan slug, visit the [bare-metal server](https://deploy.equinix.com/product/bare-metal/servers/) and [plan documentation](https://deploy.equinix.com/developers/docs/metal/hardware/standard-servers/)",
-				Required:    true,
-				ForceNew:    true,
+				Type:             schema.TypeString,
+				Description:      "The device plan slug. To find the plan slug, visit the [bare-metal server](https://deploy.equinix.com/product/bare-metal/servers/) and [plan documentation](https://deploy.equinix.com/developers/docs/metal/hardware/standard-servers/). The plan of a device deployed on a hardware reservation follows the plan of the reservation, and is not changed by recreating the device",
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressReservationPlanDiff,
 			},
 			"billing_cycle": {
 				Type:        schema.TypeString,
@@ -229,7 +298,7 @@ func resourceMetalDevice() *schema.Resource {
 						},
 						"type": {
 							Type:        schema.TypeString,
-							Description: "One of [private_ipv4, public_ipv4, public_ipv6]",
+							Description: "Type of the port (e.g. NetworkPort or NetworkBondPort)",
 							Computed:    true,
 						},
 						"mac": {
@@ -276,6 +345,11 @@ func resourceMetalDevice() *schema.Resource {
 							Description: "Whether the address is routable from the Internet",
 							Computed:    true,
 						},
+						"reservation_id": {
+							Type:        schema.TypeString,
+							Description: "ID of the IP reservation block the address was allocated from",
+							Computed:    true,
+						},
 					},
 				},
 			},
@@ -290,18 +364,28 @@ func resourceMetalDevice() *schema.Resource {
 				Computed:    true,
 			},
 			"user_data": {
-				Type:        schema.TypeString,
-				Description: "A string of the desired User Data for the device.  By default, changing this attribute will cause the provider to destroy and recreate your device.  If `reinstall` is specified or `behavior.allow_changes` includes `\"user_data\"`, the device will be updated in-place instead of recreated.",
+				Type:             schema.TypeString,
+				Description:      "A string of the desired User Data for the device.  By default, changing this attribute will cause the provider to destroy and recreate your device.  If `reinstall` is specified or `behavior.allow_changes` includes `\"user_data\"`, the device will be updated in-place instead of recreated.",
+				Optional:         true,
+				Sensitive:        true,
+				ForceNew:         false, // Computed; see CustomizeDiff below
+				ValidateFunc:     validateMetadataSize(maxDeviceMetadataSize),
+				DiffSuppressFunc: suppressTrailingNewlineDiff,
+			},
+			"reboot_on_user_data_change": {
+				Type:        schema.TypeBool,
+				Description: "Whether the device should be rebooted when `user_data` changes so that cloud-init runs again with the new data. When enabled, changing `user_data` updates the device in-place and reboots it instead of recreating it. Ignored when `reinstall` is enabled, since a reinstall already applies the new `user_data`",
 				Optional:    true,
-				Sensitive:   true,
-				ForceNew:    false, // Computed; see CustomizeDiff below
+				Default:     false,
 			},
 			"custom_data": {
-				Type:        schema.TypeString,
-				Description: "A string of the desired Custom Data for the device.  By default, changing this attribute will cause the provider to destroy and recreate your device.  If `reinstall` is specified or `behavior.allow_changes` includes `\"custom_data\"`, the device will be updated in-place instead of recreated.",
-				Optional:    true,
-				Sensitive:   true,
-				ForceNew:    false, // Computed; see CustomizeDiff below
+				Type:             schema.TypeString,
+				Description:      "A string of the desired Custom Data for the device, as a JSON object.  By default, changing this attribute will cause the provider to destroy and recreate your device.  If `reinstall` is specified or `behavior.allow_changes` includes `\"custom_data\"`, the device will be updated in-place instead of recreated.",
+				Optional:         true,
+				Sensitive:        true,
+				ForceNew:         false, // Computed; see CustomizeDiff below
+				ValidateFunc:     validation.All(validation.StringIsJSON, validateMetadataSize(maxDeviceMetadataSize)),
+				DiffSuppressFunc: suppressEquivalentCustomDataDiff,
 			},
 			"ipxe_script_url": {
 				Type:        schema.TypeString,
@@ -320,14 +404,27 @@ func resourceMetalDevice() *schema.Resource {
 				Computed:    true,
 			},
 			"hardware_reservation_id": {
-				Type:        schema.TypeString,
-				Description: "The UUID of the hardware reservation where you want this device deployed, or next-available if you want to pick your next available reservation automatically",
+				Type:             schema.TypeString,
+				Description:      "The UUID of the hardware reservation where you want this device deployed, or next-available if you want to pick your next available reservation automatically",
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressDeployedHardwareReservationDiff,
+				ConflictsWith:    []string{"hardware_reservation_pool"},
+			},
+			"hardware_reservation_pool": {
+				Type:          schema.TypeSet,
+				Description:   "UUIDs of hardware reservations the device may be deployed on. The first one that is provisionable for the plan (and metro) is used; the reservation that was drawn is exported as deployed_hardware_reservation_id",
+				Optional:      true,
+				ForceNew:      true,
+				MinItems:      1,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"hardware_reservation_id"},
+			},
+			"require_reservation": {
+				Type:        schema.TypeBool,
+				Description: "Only create the device on a hardware reservation. If `hardware_reservation_id` is `next-available` and the project has no free reservation matching the plan (and metro), the create fails instead of falling back to on-demand billing",
 				Optional:    true,
-				ForceNew:    true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					dhwr, ok := d.GetOk("deployed_hardware_reservation_id")
-					return ok && dhwr == new
-				},
+				Default:     false,
 			},
 			"tags": {
 				Type:        schema.TypeList,
@@ -344,8 +441,11 @@ func resourceMetalDevice() *schema.Resource {
 					s, _ := structure.NormalizeJsonString(v)
 					return s
 				},
-				ValidateFunc: validation.StringIsJSON,
+				ValidateFunc: validateDeviceStorage,
 			},
+			"raid":             deviceRaidSchema(),
+			"hardware_details": deviceHardwareDetailsSchema(),
+			"inventory":        deviceInventorySchema(),
 			"project_ssh_key_ids": {
 				Type:        schema.TypeList,
 				Description: "Array of IDs of the project SSH keys which should be added to the device. If you specify this array, only the listed project SSH keys (and any SSH keys for the users specified in user_ssh_key_ids) will be added. If no SSH keys are specified (both user_ssh_keys_ids and project_ssh_key_ids are empty lists or omitted), all parent project keys, parent project members keys and organization members keys will be included.  Project SSH keys can be created with the [equinix_metal_project_ssh_key](equinix_metal_project_ssh_key.md) resource",
@@ -366,6 +466,18 @@ func resourceMetalDevice() *schema.Resource {
 				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"release_dedicated_ips_on_destroy": {
+				Type:        schema.TypeBool,
+				Description: "Whether to release, after the device is deleted, the public IPv4 blocks listed in `dedicated_ip_reservation_ids`. Blocks listed in `ip_address.reservation_ids` and addresses attached later are never released",
+				Optional:    true,
+				Default:     false,
+			},
+			"dedicated_ip_reservation_ids": {
+				Type:        schema.TypeList,
+				Description: "IDs of the public IPv4 blocks that were reserved for the management addresses of the device when it was created. Empty for imported devices and for devices that had no addresses yet when the create returned",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 			"wait_for_reservation_deprovision": {
 				Type:        schema.TypeBool,
 				Description: "Only used for devices in reserved hardware. If set, the deletion of this device will block until the hardware reservation is marked provisionable (about 4 minutes in August 2019)",
@@ -373,6 +485,25 @@ func resourceMetalDevice() *schema.Resource {
 				Default:     false,
 				ForceNew:    false,
 			},
+			"wait_for_active": {
+				Type:        schema.TypeBool,
+				Description: "Whether the create should wait for the device to become active. If set to false, the create returns as soon as the device record exists; network attributes such as `access_public_ipv4` may be empty until a later refresh",
+				Optional:    true,
+				Default:     true,
+			},
+			"bgp_neighbors": {
+				Type:        schema.TypeList,
+				Description: "BGP neighbors of the device, once BGP is enabled on the project and a BGP session exists for the device. Refreshed on every read of a device whose project has BGP enabled",
+				Computed:    true,
+				Elem:        bgpNeighborSchema(),
+			},
+			"wait_for_percentage": {
+				Type:         schema.TypeInt,
+				Description:  "When waiting for the device to become active, also wait until its provisioning_percentage reaches this value. Ignored, with a warning, if the API does not report a provisioning percentage for the device",
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 100),
+			},
 			"force_detach_volumes": {
 				Type:        schema.TypeBool,
 				Description: "Delete device even if it has volumes attached. Only applies for destroy action",
@@ -384,6 +515,7 @@ func resourceMetalDevice() *schema.Resource {
 				Type:        schema.TypeString,
 				Description: "Timestamp for device termination. For example \"2021-09-03T16:32:00+03:00\". If you don't supply timezone info, timestamp is assumed to be in UTC.",
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    false,
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					_, err := time.ParseInLocation(time.RFC3339, val.(string), time.UTC)
@@ -392,6 +524,42 @@ func resourceMetalDevice() *schema.Resource {
 					}
 					return
 				},
+				DiffSuppressFunc: suppressEquivalentTimeDiff,
+			},
+			"termination_time_auto_extend": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Type:        schema.TypeBool,
+							Description: "Whether termination_time should be extended each time the device is planned",
+							Optional:    true,
+							Default:     false,
+						},
+						"duration": {
+							Type:        schema.TypeString,
+							Description: "How far from now termination_time is extended, as a duration such as `72h`",
+							Required:    true,
+							ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
+								duration, err := time.ParseDuration(val.(string))
+								if err != nil {
+									errs = []error{err}
+								} else if duration <= 0 {
+									errs = []error{fmt.Errorf("%s must be a positive duration, got %s", key, val)}
+								}
+								return
+							},
+						},
+					},
+				},
+			},
+			"prefer_reinstall_over_recreate": {
+				Type:        schema.TypeBool,
+				Description: "Whether changes to `operating_system`, `user_data` or `custom_data` should reinstall the device instead of recreating it, as if `reinstall` was enabled. The `preserve_data` and `deprovision_fast` options of a `reinstall` block are still used. Cannot be combined with `reinstall.enabled = false`",
+				Optional:    true,
+				Default:     false,
 			},
 			"reinstall": {
 				Type:     schema.TypeList,
@@ -420,6 +588,49 @@ func resourceMetalDevice() *schema.Resource {
 					},
 				},
 			},
+			"reboot": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"triggers": {
+							Type:        schema.TypeMap,
+							Description: "Arbitrary map of values that, when changed, cause the device to be rebooted in-place",
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"provisioning_timeout_action": {
+				Type:         schema.TypeString,
+				Description:  "What to do with a device that is not active within the create timeout, delete it or keep it for inspection. One of delete, keep",
+				Optional:     true,
+				Default:      provisioningTimeoutDelete,
+				ValidateFunc: validation.StringInSlice([]string{provisioningTimeoutDelete, provisioningTimeoutKeep}, false),
+			},
+			"drain_before_destroy": {
+				Type:        schema.TypeList,
+				Description: "Tag the device and wait before deleting it, so that external controllers can drain it",
+				MaxItems:    1,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tag": {
+							Type:        schema.TypeString,
+							Description: "Tag added to the device before it is deleted",
+							Required:    true,
+						},
+						"drain_timeout": {
+							Type:         schema.TypeInt,
+							Description:  "Number of seconds to wait after tagging the device before deleting it. The wait counts against the delete timeout",
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+					},
+				},
+			},
 			"behavior": {
 				Type:     schema.TypeList,
 				MaxItems: 1,
@@ -450,18 +661,435 @@ func resourceMetalDevice() *schema.Resource {
 				Description: "The hostname to use for [Serial over SSH](https://deploy.equinix.com/developers/docs/metal/resilience-recovery/serial-over-ssh/) access to the device",
 				Computed:    true,
 			},
+			"image_url": {
+				Type:        schema.TypeString,
+				Description: "The URL of the image the device was provisioned from, when the operating system reports one",
+				Computed:    true,
+			},
 		},
 		CustomizeDiff: customdiff.Sequence(
 			customdiff.ForceNewIf("custom_data", reinstallDisabledAndNoChangesAllowed("custom_data")),
 			customdiff.ForceNewIf("operating_system", reinstallDisabled),
-			customdiff.ForceNewIf("user_data", reinstallDisabledAndNoChangesAllowed("user_data")),
+			customdiff.ForceNewIf("user_data", rebootDisabledAndNoChangesAllowed("user_data")),
+			customizeDiffDeployedLocation,
+			customizeDiffOperatingSystemPlan,
+			customizeDiffPreferReinstall,
+			customizeDiffTerminationTimeRemoved,
+			customizeDiffTerminationTimeAutoExtend,
 		),
 	}
 }
 
+// deviceRaidSchema is shared by the device resource and data source
+func deviceRaidSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "RAID arrays reported by the device after provisioning with a custom `storage` layout",
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Description: "Name of the RAID array (e.g. MD0)",
+					Computed:    true,
+				},
+				"level": {
+					Type:        schema.TypeString,
+					Description: "RAID level of the array",
+					Computed:    true,
+				},
+				"devices": {
+					Type:        schema.TypeList,
+					Description: "Block devices that are members of the array",
+					Computed:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+// deviceInventorySchema is shared by the device resource and data source
+func deviceInventorySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Normalized summary of the device for inventory systems such as a CMDB",
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Type:        schema.TypeString,
+					Description: "The ID of the device",
+					Computed:    true,
+				},
+				"hostname": {
+					Type:        schema.TypeString,
+					Description: "The hostname of the device",
+					Computed:    true,
+				},
+				"project_id": {
+					Type:        schema.TypeString,
+					Description: "The ID of the project the device belongs to",
+					Computed:    true,
+				},
+				"metro": {
+					Type:        schema.TypeString,
+					Description: "The lowercase code of the metro the device is deployed in",
+					Computed:    true,
+				},
+				"facility": {
+					Type:        schema.TypeString,
+					Description: "The lowercase code of the facility the device is deployed in",
+					Computed:    true,
+				},
+				"plan": {
+					Type:        schema.TypeString,
+					Description: "The slug of the device plan",
+					Computed:    true,
+				},
+				"state": {
+					Type:        schema.TypeString,
+					Description: "The state of the device",
+					Computed:    true,
+				},
+				"iqn": {
+					Type:        schema.TypeString,
+					Description: "The iSCSI Qualified Name of the device",
+					Computed:    true,
+				},
+				"switch_uuid": {
+					Type:        schema.TypeString,
+					Description: "The UUID of the top-of-rack switch the device is connected to",
+					Computed:    true,
+				},
+				"created": {
+					Type:        schema.TypeString,
+					Description: "The RFC3339 timestamp of the device creation",
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// deviceHardwareDetailsSchema is shared by the device resource and data source
+func deviceHardwareDetailsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Description: "Hardware specification of the device plan, useful for compliance reporting",
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"raid": {
+					Type:        schema.TypeBool,
+					Description: "Whether the hardware has a RAID controller",
+					Computed:    true,
+				},
+				"txt": {
+					Type:        schema.TypeBool,
+					Description: "Whether the hardware supports Intel TXT (Trusted Execution Technology)",
+					Computed:    true,
+				},
+				"uefi": {
+					Type:        schema.TypeBool,
+					Description: "Whether the hardware boots in UEFI mode",
+					Computed:    true,
+				},
+				"memory": {
+					Type:        schema.TypeString,
+					Description: "Total memory of the hardware",
+					Computed:    true,
+				},
+				"cpus": {
+					Type:        schema.TypeList,
+					Description: "CPUs of the hardware",
+					Computed:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"count": {
+								Type:        schema.TypeInt,
+								Description: "Number of CPUs of this type",
+								Computed:    true,
+							},
+							"type": {
+								Type:        schema.TypeString,
+								Description: "CPU model",
+								Computed:    true,
+							},
+						},
+					},
+				},
+				"drives": {
+					Type:        schema.TypeList,
+					Description: "Drives of the hardware",
+					Computed:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"count": {
+								Type:        schema.TypeInt,
+								Description: "Number of drives of this type",
+								Computed:    true,
+							},
+							"type": {
+								Type:        schema.TypeString,
+								Description: "Drive type, e.g. SSD or NVMe",
+								Computed:    true,
+							},
+							"size": {
+								Type:        schema.TypeString,
+								Description: "Size of each drive",
+								Computed:    true,
+							},
+							"category": {
+								Type:        schema.TypeString,
+								Description: "Drive category, one of boot, cache or storage",
+								Computed:    true,
+							},
+						},
+					},
+				},
+				"nics": {
+					Type:        schema.TypeList,
+					Description: "Network interfaces of the hardware",
+					Computed:    true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"count": {
+								Type:        schema.TypeInt,
+								Description: "Number of network interfaces of this type",
+								Computed:    true,
+							},
+							"type": {
+								Type:        schema.TypeString,
+								Description: "Network interface type",
+								Computed:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceMetalDeviceImportState sets the defaults of the attributes that only
+// tune provider behavior. They have no API representation, so without this the
+// first plan after an import would show them as changes. The reinstall, reboot,
+// drain_before_destroy and behavior blocks are left empty for the same reason,
+// any configured values are recorded in state by the first apply without
+// touching the device.
+func resourceMetalDeviceImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	defaults := map[string]interface{}{
+		"wait_for_active":                  true,
+		"wait_for_percentage":              0,
+		"wait_for_reservation_deprovision": false,
+		"force_detach_volumes":             false,
+		"require_reservation":              false,
+		"reboot_on_user_data_change":       false,
+		"prefer_reinstall_over_recreate":   false,
+		"preferred_facility_fallback":      false,
+		"release_dedicated_ips_on_destroy": false,
+		"provisioning_timeout_action":      provisioningTimeoutDelete,
+	}
+	for k, v := range defaults {
+		if err := d.Set(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	// Read only keeps the tags that are already managed, all tags of an
+	// imported device are managed from now on
+	client := meta.(*config.Config).NewMetalClientForSDK(d)
+	device, resp, err := client.DevicesApi.FindDeviceById(ctx, d.Id()).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	if err := d.Set("tags", device.GetTags()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// validateMetadataSize rejects user_data and custom_data larger than max
+// bytes, which the API would only report once the device is provisioned.
+// The value itself is sensitive, so only its size is reported.
+func validateMetadataSize(max int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) ([]string, []error) {
+		value, ok := v.(string)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+		}
+		if len(value) > max {
+			return nil, []error{fmt.Errorf("%s is %d bytes, which exceeds the maximum of %d bytes", k, len(value), max)}
+		}
+		return nil, nil
+	}
+}
+
+// trimTrailingNewlines returns user_data without its trailing "\n" and "\r\n"
+// line endings, which is how user_data values are compared
+func trimTrailingNewlines(userData string) string {
+	for {
+		switch {
+		case strings.HasSuffix(userData, "\r\n"):
+			userData = strings.TrimSuffix(userData, "\r\n")
+		case strings.HasSuffix(userData, "\n"):
+			userData = strings.TrimSuffix(userData, "\n")
+		default:
+			return userData
+		}
+	}
+}
+
+// suppressTrailingNewlineDiff ignores differences that only consist of
+// trailing newlines, as commonly introduced by heredocs and file() reads.
+// Any other whitespace is significant and is sent to the API unmodified.
+func suppressTrailingNewlineDiff(k, old, new string, d *schema.ResourceData) bool {
+	return trimTrailingNewlines(old) == trimTrailingNewlines(new)
+}
+
+// suppressReservationPlanDiff ignores a plan change of a device deployed on the
+// hardware reservation that hardware_reservation_id pins. Such a device has the
+// plan of its reservation, which changes when the reservation is upgraded.
+// Recreating the device would land it on the same reservation, and plan, but
+// lose the operating system that is installed on it. With next-available or a
+// pool another reservation could be drawn, so a plan change there is only
+// ignored when it names the plan of the deployed reservation, which the state
+// reports.
+func suppressReservationPlanDiff(k, old, new string, d *schema.ResourceData) bool {
+	if old == "" || d.Id() == "" {
+		return false
+	}
+	deployed, ok := d.GetOk("deployed_hardware_reservation_id")
+	if !ok {
+		return false
+	}
+	if hwr := d.Get("hardware_reservation_id").(string); hwr != "" && hwr != "next-available" {
+		return hwr == deployed
+	}
+	return strings.EqualFold(old, new)
+}
+
+// suppressEquivalentCustomDataDiff ignores differences between custom_data
+// documents that are semantically equal, such as key order and whitespace
+func suppressEquivalentCustomDataDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeCustomData(old) == normalizeCustomData(new)
+}
+
+// normalizeCustomData returns the compact form of a custom_data JSON document.
+// The API reports a device without custom data as an empty object, which is
+// normalized to an empty string so that it matches an unset attribute.
+func normalizeCustomData(customData string) string {
+	normalized, err := structure.NormalizeJsonString(customData)
+	if err != nil {
+		return customData
+	}
+	if normalized == "{}" || normalized == "null" {
+		return ""
+	}
+	return normalized
+}
+
+// suppressDeployedHardwareReservationDiff ignores a change of
+// hardware_reservation_id to the reservation the device is deployed on, so that
+// a device created with next-available can be pinned to the reservation that
+// was picked without being recreated
+func suppressDeployedHardwareReservationDiff(k, old, new string, d *schema.ResourceData) bool {
+	dhwr, ok := d.GetOk("deployed_hardware_reservation_id")
+	return ok && dhwr == new
+}
+
+// suppressEquivalentTimeDiff ignores differences between RFC3339 timestamps
+// that denote the same instant, such as the same time in another time zone
+func suppressEquivalentTimeDiff(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := time.ParseInLocation(time.RFC3339, old, time.UTC)
+	if err != nil {
+		return false
+	}
+	newTime, err := time.ParseInLocation(time.RFC3339, new, time.UTC)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
+
+// customizeDiffDeployedLocation plans deployed_metro and deployed_facility of a
+// new device when the configuration leaves the API no choice, so that other
+// resources can use them in the same plan
+func customizeDiffDeployedLocation(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" {
+		return nil
+	}
+	if d.NewValueKnown("metro") && d.NewValueKnown("metro_fallback") {
+		metro := d.Get("metro").(string)
+		if metro != "" && len(d.Get("metro_fallback").([]interface{})) == 0 {
+			if err := d.SetNew("deployed_metro", strings.ToLower(metro)); err != nil {
+				return err
+			}
+		}
+	}
+	if d.NewValueKnown("preferred_facility") && d.NewValueKnown("preferred_facility_fallback") {
+		facility := d.Get("preferred_facility").(string)
+		if facility != "" && !d.Get("preferred_facility_fallback").(bool) {
+			if err := d.SetNew("deployed_facility", strings.ToLower(facility)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// customizeDiffOperatingSystemPlan fails the plan of a device whose
+// operating_system is not provisionable on its plan, which would otherwise
+// only be reported by the API when the device is created or reinstalled
+func customizeDiffOperatingSystemPlan(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("operating_system") && !d.HasChange("plan") {
+		return nil
+	}
+	if !d.NewValueKnown("operating_system") || !d.NewValueKnown("plan") {
+		return nil
+	}
+	osSlug := d.Get("operating_system").(string)
+	plan := d.Get("plan").(string)
+	cfg, ok := meta.(*config.Config)
+	if !ok || osSlug == "" || plan == "" {
+		return nil
+	}
+
+	client := cfg.NewMetalClientForSDKDiff()
+	list, resp, err := client.OperatingSystemsApi.FindOperatingSystems(ctx).Execute()
+	if err != nil {
+		// the create or reinstall reports an incompatible operating system anyway
+		log.Printf("[WARN] Could not check that operating system %s is provisionable on plan %s: %s", osSlug, plan, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		return nil
+	}
+	return checkOperatingSystemPlan(list.GetOperatingSystems(), osSlug, plan)
+}
+
+// checkOperatingSystemPlan returns an error when the operating system is known
+// to not be provisionable on the plan. Unknown operating systems and those that
+// do not list the plans they are provisionable on are left to the API.
+func checkOperatingSystemPlan(operatingSystems []metalv1.OperatingSystem, osSlug, plan string) error {
+	for _, operatingSystem := range operatingSystems {
+		if operatingSystem.GetSlug() != osSlug {
+			continue
+		}
+		provisionableOn := operatingSystem.GetProvisionableOn()
+		if len(provisionableOn) == 0 || slices.Contains(provisionableOn, plan) {
+			return nil
+		}
+		return fmt.Errorf("operating_system %q is not provisionable on plan %q, it is provisionable on: %s", osSlug, plan, strings.Join(provisionableOn, ", "))
+	}
+	return nil
+}
+
 // This method returns true if reinstall is disabled, and false if it is enabled.
 // This is used to set ForceNew to true when reinstall is disabled
 func reinstallDisabled(_ context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+	if d.Get("prefer_reinstall_over_recreate").(bool) {
+		return false
+	}
+
 	reinstall, ok := d.GetOk("reinstall")
 
 	if !ok {
@@ -477,6 +1105,81 @@ func reinstallDisabled(_ context.Context, d *schema.ResourceDiff, meta interface
 	return !reinstall_config["enabled"].(bool)
 }
 
+// customizeDiffPreferReinstall rejects prefer_reinstall_over_recreate when the
+// configuration also disables reinstalls explicitly with reinstall.enabled = false
+func customizeDiffPreferReinstall(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.Get("prefer_reinstall_over_recreate").(bool) {
+		return nil
+	}
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+	reinstall := raw.GetAttr("reinstall")
+	if reinstall.IsNull() || !reinstall.IsKnown() || reinstall.LengthInt() == 0 {
+		return nil
+	}
+	enabled := reinstall.Index(cty.NumberIntVal(0)).GetAttr("enabled")
+	if enabled.IsKnown() && !enabled.IsNull() && enabled.False() {
+		return fmt.Errorf("prefer_reinstall_over_recreate cannot be combined with reinstall.enabled = false, remove one of them")
+	}
+	return nil
+}
+
+// customizeDiffTerminationTimeRemoved plans clearing the termination_time of an
+// existing device when it is removed from the configuration. termination_time
+// is computed so that auto extension can plan it, which would otherwise keep
+// the value of the state.
+func customizeDiffTerminationTimeRemoved(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() || !raw.GetAttr("termination_time").IsNull() {
+		return nil
+	}
+	if old, _ := d.GetChange("termination_time"); old.(string) == "" {
+		return nil
+	}
+	return d.SetNew("termination_time", "")
+}
+
+// customizeDiffTerminationTimeAutoExtend plans the termination_time of an existing
+// device termination_time_auto_extend.duration from now when the extension is
+// enabled. A termination_time that is already later is kept as it is.
+func customizeDiffTerminationTimeAutoExtend(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.Get("termination_time_auto_extend.0.enabled").(bool) {
+		return nil
+	}
+	if !d.NewValueKnown("termination_time") || !d.NewValueKnown("termination_time_auto_extend.0.duration") {
+		return nil
+	}
+	duration, err := time.ParseDuration(d.Get("termination_time_auto_extend.0.duration").(string))
+	if err != nil {
+		return fmt.Errorf("error parsing termination_time_auto_extend.duration: %w", err)
+	}
+	extended, ok := extendedTerminationTime(d.Get("termination_time").(string), duration, time.Now())
+	if !ok {
+		return nil
+	}
+	log.Printf("[DEBUG] Extending termination time of device %s to %s", d.Id(), extended)
+	return d.SetNew("termination_time", extended)
+}
+
+// extendedTerminationTime returns the termination time duration from now, and
+// whether it is later than the current termination time. A device without a
+// termination time is not given one.
+func extendedTerminationTime(current string, duration time.Duration, now time.Time) (string, bool) {
+	if current == "" {
+		return "", false
+	}
+	extended := now.Add(duration).UTC().Truncate(time.Second)
+	if currentTime, err := time.ParseInLocation(time.RFC3339, current, time.UTC); err == nil && !currentTime.Before(extended) {
+		return "", false
+	}
+	return extended.Format(time.RFC3339), true
+}
+
 func reinstallDisabledAndNoChangesAllowed(attribute string) customdiff.ResourceConditionFunc {
 	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
 		if reinstallDisabled(ctx, d, meta) {
@@ -508,6 +1211,17 @@ func reinstallDisabledAndNoChangesAllowed(attribute string) customdiff.ResourceC
 	}
 }
 
+// rebootDisabledAndNoChangesAllowed extends reinstallDisabledAndNoChangesAllowed
+// so that changes are also allowed when reboot_on_user_data_change is enabled
+func rebootDisabledAndNoChangesAllowed(attribute string) customdiff.ResourceConditionFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+		if d.Get("reboot_on_user_data_change").(bool) {
+			return false
+		}
+		return reinstallDisabledAndNoChangesAllowed(attribute)(ctx, d, meta)
+	}
+}
+
 func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
@@ -517,7 +1231,7 @@ func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta
 	metroRaw, metroOk := d.GetOk("metro")
 
 	if !facsOk && !metroOk {
-		return diag.Errorf("one of facilies and metro must be configured")
+		return diag.Errorf("one of facilities and metro must be configured")
 	}
 
 	if facsOk {
@@ -533,6 +1247,26 @@ func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta
 		createRequest.DeviceCreateInFacilityInput = facilityRequest
 	}
 
+	if facility, ok := d.GetOk("preferred_facility"); ok && metroOk {
+		useFacility, diagErr := usePreferredFacility(ctx, client, d, metroRaw.(string), facility.(string))
+		if diagErr != nil {
+			return diagErr
+		}
+		if useFacility {
+			facilityRequest := &metalv1.DeviceCreateInFacilityInput{
+				Facility: []string{facility.(string)},
+			}
+
+			diagErr := setupDeviceCreateRequest(d, facilityRequest)
+			if diagErr != nil {
+				return diagErr
+			}
+
+			createRequest.DeviceCreateInFacilityInput = facilityRequest
+			metroOk = false
+		}
+	}
+
 	if metroOk {
 		metroRequest := &metalv1.DeviceCreateInMetroInput{
 			Metro: metroRaw.(string),
@@ -548,25 +1282,276 @@ func resourceMetalDeviceCreate(ctx context.Context, d *schema.ResourceData, meta
 
 	start := time.Now()
 	projectID := d.Get("project_id").(string)
-	newDevice, _, err := client.DevicesApi.CreateDevice(ctx, projectID).CreateDeviceRequest(createRequest).Execute()
+	if diagErr := checkHardwareReservationProject(ctx, client, d); diagErr != nil {
+		return diagErr
+	}
+	if d.Get("require_reservation").(bool) {
+		if diagErr := checkRequiredReservation(ctx, client, d); diagErr != nil {
+			return diagErr
+		}
+	}
+	unlockPool := func() {}
+	if pool, ok := d.GetOk("hardware_reservation_pool"); ok {
+		// Devices drawing from the same pool would otherwise pick the same
+		// reservation, the lock is held until the reservation is taken
+		lockID := "hardware_reservation_pool:" + projectID
+		mutexkv.Metal.Lock(lockID)
+		unlockPool = func() { mutexkv.Metal.Unlock(lockID) }
+
+		reservationID, diagErr := drawHardwareReservation(ctx, client, d, converters.IfArrToStringArr(pool.(*schema.Set).List()))
+		if diagErr != nil {
+			unlockPool()
+			return diagErr
+		}
+		if createRequest.DeviceCreateInMetroInput != nil {
+			createRequest.DeviceCreateInMetroInput.SetHardwareReservationId(reservationID)
+		}
+		if createRequest.DeviceCreateInFacilityInput != nil {
+			createRequest.DeviceCreateInFacilityInput.SetHardwareReservationId(reservationID)
+		}
+	}
+	fallbackMetros := converters.IfArrToStringArr(d.Get("metro_fallback").([]interface{}))
+	newDevice, resp, err := createDeviceWithMetroFallback(ctx, client, projectID, createRequest, fallbackMetros)
+	unlockPool()
 	if err != nil {
-		retErr := equinix_errors.FriendlyError(err)
-		if equinix_errors.IsNotFound(retErr) {
-			retErr = fmt.Errorf("%s, make sure project \"%s\" exists", retErr, projectID)
+		summary := "Error creating device"
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			summary = fmt.Sprintf("%s, make sure project %q exists", summary, projectID)
 		}
-		return diag.FromErr(retErr)
+		return deviceErrorDiagnostics(summary, resp, err)
 	}
 
 	d.SetId(newDevice.GetId())
 
-	createTimeout := d.Timeout(schema.TimeoutCreate) - 30*time.Second - time.Since(start)
+	if !d.Get("wait_for_active").(bool) {
+		log.Printf("[DEBUG] Not waiting for device (%s) to become active", d.Id())
+		return resourceMetalDeviceRead(ctx, d, meta)
+	}
+
+	createTimeout := wait.Remaining(d.Timeout(schema.TimeoutCreate), start)
 	if err = waitForActiveDevice(ctx, d, meta, createTimeout); err != nil {
+		var timeoutErr *retry.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return diag.FromErr(handleProvisioningTimeout(ctx, client, d, newDevice.GetId(), err))
+		}
 		return diag.FromErr(err)
 	}
 
 	return resourceMetalDeviceRead(ctx, d, meta)
 }
 
+// handleProvisioningTimeout applies provisioning_timeout_action to a device
+// that did not become active within the create timeout. A kept device stays
+// in state, where Terraform marks it as tainted so that it is replaced by the
+// next apply.
+func handleProvisioningTimeout(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData, id string, err error) error {
+	if d.Get("provisioning_timeout_action").(string) == provisioningTimeoutKeep {
+		d.SetId(id)
+		return fmt.Errorf("device %s was not active within the create timeout and is kept for inspection: %w", id, err)
+	}
+
+	resp, deleteErr := client.DevicesApi.DeleteDevice(ctx, id).ForceDelete(d.Get("force_detach_volumes").(bool)).Execute()
+	gone := resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound)
+	if deleteErr != nil && !gone {
+		// keep the device in state, the failed create taints it so that the
+		// next apply destroys or replaces it
+		d.SetId(id)
+		return fmt.Errorf("device %s was not active within the create timeout and could not be deleted, it is kept in state to be destroyed by the next apply: %s: %w", id, equinix_errors.FriendlyErrorForMetalGo(deleteErr, resp), err)
+	}
+	d.SetId("")
+	return fmt.Errorf("device %s was not active within the create timeout and was deleted: %w", id, err)
+}
+
+// isFallbackMetro reports whether metro is one of the metro_fallback metros
+func isFallbackMetro(d *schema.ResourceData, metro string) bool {
+	for _, m := range d.Get("metro_fallback").([]interface{}) {
+		if fallback, ok := m.(string); ok && strings.EqualFold(metro, fallback) {
+			return true
+		}
+	}
+	return false
+}
+
+// createDeviceWithMetroFallback creates the device and, while the API reports
+// that the requested metro has no capacity for the plan, retries the create in
+// each of the fallback metros in order. Devices requested in a facility are
+// created only once.
+func createDeviceWithMetroFallback(ctx context.Context, client *metalv1.APIClient, projectID string, createRequest metalv1.CreateDeviceRequest, fallbackMetros []string) (*metalv1.Device, *http.Response, error) {
+	for {
+		device, resp, err := createDevice(ctx, client, projectID, createRequest)
+		if err == nil || createRequest.DeviceCreateInMetroInput == nil || len(fallbackMetros) == 0 || !isCapacityError(resp, err) {
+			return device, resp, err
+		}
+		log.Printf("[WARN] No capacity in metro %s, creating device in metro %s: %s", createRequest.DeviceCreateInMetroInput.Metro, fallbackMetros[0], err)
+		createRequest.DeviceCreateInMetroInput.Metro = fallbackMetros[0]
+		fallbackMetros = fallbackMetros[1:]
+	}
+}
+
+// createDevice creates the device, retrying for up to projectNotFoundTimeout
+// while the API does not find the project yet. Other errors, including other
+// not found errors, are returned at once.
+func createDevice(ctx context.Context, client *metalv1.APIClient, projectID string, createRequest metalv1.CreateDeviceRequest) (*metalv1.Device, *http.Response, error) {
+	deadline := time.Now().Add(projectNotFoundTimeout)
+	for {
+		device, resp, err := client.DevicesApi.CreateDevice(ctx, projectID).CreateDeviceRequest(createRequest).Execute()
+		if !isProjectNotFoundError(resp, err) || time.Now().Add(projectNotFoundInterval).After(deadline) {
+			return device, resp, err
+		}
+		log.Printf("[DEBUG] Project %s not found, retrying the device create: %s", projectID, err)
+		select {
+		case <-ctx.Done():
+			return device, resp, err
+		case <-time.After(projectNotFoundInterval):
+		}
+	}
+}
+
+// usePreferredFacility reports whether a device with preferred_facility set
+// should be deployed in that facility rather than anywhere in its metro. The
+// facility has to be part of the metro. Without capacity for the plan it is
+// skipped if preferred_facility_fallback is enabled, otherwise the create fails.
+// Devices on a hardware reservation are always deployed in the facility, since
+// the reservation rather than the public capacity decides the placement.
+func usePreferredFacility(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData, metro, facility string) (bool, diag.Diagnostics) {
+	facilities, resp, err := client.FacilitiesApi.FindFacilities(ctx).Execute()
+	if err != nil {
+		return false, diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	found := false
+	for _, f := range facilities.GetFacilities() {
+		if !strings.EqualFold(f.GetCode(), facility) {
+			continue
+		}
+		found = true
+		fMetro := f.GetMetro()
+		if !strings.EqualFold(fMetro.GetCode(), metro) {
+			return false, diag.Errorf("preferred_facility %q is in metro %q, not in metro %q", facility, fMetro.GetCode(), metro)
+		}
+	}
+	if !found {
+		return false, diag.Errorf("preferred_facility %q was not found", facility)
+	}
+
+	_, hasReservationID := d.GetOk("hardware_reservation_id")
+	_, hasReservationPool := d.GetOk("hardware_reservation_pool")
+	if hasReservationID || hasReservationPool {
+		return true, nil
+	}
+
+	plan := d.Get("plan").(string)
+	input := metalv1.CapacityInput{
+		Servers: []metalv1.ServerInfo{{
+			Facility: metalv1.PtrString(facility),
+			Plan:     metalv1.PtrString(plan),
+			Quantity: metalv1.PtrString("1"),
+		}},
+	}
+	capacity, resp, err := client.CapacityApi.CheckCapacityForFacility(ctx).CapacityInput(input).Execute()
+	if err != nil {
+		return false, diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	for _, s := range capacity.GetServers() {
+		if s.GetAvailable() {
+			return true, nil
+		}
+	}
+
+	if d.Get("preferred_facility_fallback").(bool) {
+		log.Printf("[DEBUG] No capacity for plan %s in facility %s, deploying device in metro %s", plan, facility, metro)
+		return false, nil
+	}
+	return false, diag.Errorf("no capacity for plan %q in preferred_facility %q; enable preferred_facility_fallback to deploy the device anywhere in metro %q", plan, facility, metro)
+}
+
+// checkHardwareReservationProject makes sure the hardware_reservation_id of a
+// device belongs to its project. The API rejects a reservation of another
+// project with an error that does not name the reservation.
+func checkHardwareReservationProject(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData) diag.Diagnostics {
+	hwReservationID := d.Get("hardware_reservation_id").(string)
+	if hwReservationID == "" || hwReservationID == "next-available" {
+		return nil
+	}
+
+	projectID := d.Get("project_id").(string)
+	reservation, resp, err := client.HardwareReservationsApi.FindHardwareReservationById(ctx, hwReservationID).Include([]string{"project"}).Execute()
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return diag.Errorf("hardware reservation %q was not found, make sure it belongs to project %q", hwReservationID, projectID)
+		}
+		return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+	}
+	project := reservation.GetProject()
+	if reservationProjectID := project.GetId(); reservationProjectID != "" && reservationProjectID != projectID {
+		return diag.Errorf("hardware reservation %q belongs to project %q, not to project %q of the device", hwReservationID, reservationProjectID, projectID)
+	}
+	return nil
+}
+
+// checkRequiredReservation makes sure a device with require_reservation set
+// will be deployed on a hardware reservation rather than on-demand
+func checkRequiredReservation(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData) diag.Diagnostics {
+	if _, ok := d.GetOk("hardware_reservation_pool"); ok {
+		// a reservation is drawn from the pool or the create fails
+		return nil
+	}
+	hwReservationID := d.Get("hardware_reservation_id").(string)
+	if hwReservationID == "" {
+		return diag.Errorf("\"hardware_reservation_id\" must be set when \"require_reservation\" is enabled")
+	}
+	if hwReservationID != "next-available" {
+		return nil
+	}
+
+	projectID := d.Get("project_id").(string)
+	plan := d.Get("plan").(string)
+	metro := d.Get("metro").(string)
+	reservations, err := getProvisionableHardwareReservations(ctx, client, projectID, plan, metro)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(reservations) == 0 {
+		return diag.Errorf("no provisionable hardware reservation for plan %q found in project %q; "+
+			"not creating an on-demand device because \"require_reservation\" is enabled", plan, projectID)
+	}
+	return nil
+}
+
+// drawHardwareReservation returns the first reservation of the pool that is
+// provisionable for the plan (and metro) of the device
+func drawHardwareReservation(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData, pool []string) (string, diag.Diagnostics) {
+	projectID := d.Get("project_id").(string)
+	plan := d.Get("plan").(string)
+	metro := d.Get("metro").(string)
+	reservations, err := getProvisionableHardwareReservations(ctx, client, projectID, plan, metro)
+	if err != nil {
+		return "", diag.FromErr(err)
+	}
+
+	reservationID := selectPoolReservation(reservations, pool)
+	if reservationID == "" {
+		return "", diag.Errorf("none of the %d reservations in \"hardware_reservation_pool\" is provisionable for plan %q in project %q", len(pool), plan, projectID)
+	}
+	log.Printf("[DEBUG] Deploying device on hardware reservation %s from hardware_reservation_pool", reservationID)
+	return reservationID, nil
+}
+
+// selectPoolReservation returns the lowest ID of the pool that is available, so
+// that the draw does not depend on the order of the set, or an empty string if
+// no reservation of the pool is available
+func selectPoolReservation(available []metalv1.HardwareReservation, pool []string) string {
+	sorted := slices.Clone(pool)
+	sort.Strings(sorted)
+	for _, id := range sorted {
+		for _, r := range available {
+			if r.GetId() == id {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
 func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
@@ -588,10 +1573,16 @@ func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta i
 
 	d.Set("hostname", device.GetHostname())
 	d.Set("plan", device.Plan.GetSlug())
-	d.Set("deployed_facility", device.Facility.GetCode())
+	d.Set("deployed_facility", strings.ToLower(device.Facility.GetCode()))
 	d.Set("facilities", []string{device.Facility.GetCode()})
 	if device.Metro != nil {
-		d.Set("metro", device.Metro.GetCode())
+		deployedMetro := strings.ToLower(device.Metro.GetCode())
+		// a device deployed in one of the fallback metros keeps the requested
+		// metro in state, so that changing metro still recreates it
+		if requested := d.Get("metro").(string); requested == "" || !isFallbackMetro(d, deployedMetro) {
+			d.Set("metro", device.Metro.GetCode())
+		}
+		d.Set("deployed_metro", deployedMetro)
 	}
 	d.Set("operating_system", device.OperatingSystem.GetSlug())
 	d.Set("state", device.GetState())
@@ -601,9 +1592,29 @@ func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta i
 	d.Set("updated", device.GetUpdatedAt().Format(time.RFC3339))
 	d.Set("ipxe_script_url", device.GetIpxeScriptUrl())
 	d.Set("always_pxe", device.GetAlwaysPxe())
-	d.Set("root_password", device.GetRootPassword())
+	if device.RootPassword != nil {
+		// the API stops returning the password 24 hours after provisioning,
+		// keep the one in state, if any, rather than clearing it
+		d.Set("root_password", device.GetRootPassword())
+	}
 	d.Set("project_id", device.Project.GetId())
 	d.Set("sos_hostname", device.GetSos())
+	d.Set("image_url", device.GetImageUrl())
+	if device.Userdata != nil {
+		// The API may drop the trailing newlines of user_data. Changing
+		// user_data can recreate the device, so the value in state is only
+		// replaced when it differs in more than trailing newlines.
+		if userData := device.GetUserdata(); trimTrailingNewlines(userData) != trimTrailingNewlines(d.Get("user_data").(string)) {
+			d.Set("user_data", userData)
+		}
+	}
+	if device.Customdata != nil {
+		rawCustomDataBytes, err := json.Marshal(device.Customdata)
+		if err != nil {
+			return diag.Errorf("[ERR] Error getting custom_data JSON string for device (%s): %s", d.Id(), err)
+		}
+		d.Set("custom_data", normalizeCustomData(string(rawCustomDataBytes)))
+	}
 	if device.Storage != nil {
 		rawStorageBytes, err := json.Marshal(device.Storage)
 		if err != nil {
@@ -616,6 +1627,9 @@ func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta i
 		}
 		d.Set("storage", storageString)
 	}
+	d.Set("raid", getRaid(device.Storage))
+	d.Set("hardware_details", getHardwareDetails(device.Plan))
+	d.Set("inventory", getDeviceInventory(device))
 	if device.HardwareReservation != nil {
 		d.Set("deployed_hardware_reservation_id", device.HardwareReservation.GetId())
 	}
@@ -637,28 +1651,40 @@ func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta i
 	tt := "termination_time"
 	if _, ok := d.GetOk(tt); !ok {
 		d.Set(tt, nil)
+	} else if device.TerminationTime != nil {
+		// only reflected when configured, the API may return it in another
+		// time zone, which suppressEquivalentTimeDiff ignores
+		d.Set(tt, device.GetTerminationTime().Format(time.RFC3339))
 	}
 
-	d.Set("tags", device.Tags)
+	// tags added outside of this configuration are left out of state, so
+	// that the next apply does not remove them
+	d.Set("tags", managedTags(device.Tags, converters.IfArrToStringArr(d.Get("tags").([]interface{}))))
 	keyIDs := []string{}
 	for _, k := range device.SshKeys {
 		keyIDs = append(keyIDs, path.Base(k.Href))
 	}
 	d.Set("ssh_key_ids", keyIDs)
 	networkInfo := getNetworkInfo(device.IpAddresses)
-
-	sort.SliceStable(networkInfo.Networks, func(i, j int) bool {
-		famI := networkInfo.Networks[i]["family"].(int32)
-		famJ := networkInfo.Networks[j]["family"].(int32)
-		pubI := networkInfo.Networks[i]["public"].(bool)
-		pubJ := networkInfo.Networks[j]["public"].(bool)
-		return getNetworkRank(int(famI), pubI) < getNetworkRank(int(famJ), pubJ)
-	})
+	if !d.IsNewResource() && networksChanged(d.Get("network").([]interface{}), networkInfo.Networks) {
+		log.Printf("[WARN] IP assignments of device (%s) changed outside of Terraform", d.Id())
+	}
 
 	d.Set("network", networkInfo.Networks)
 	d.Set("access_public_ipv4", networkInfo.PublicIPv4)
 	d.Set("access_private_ipv4", networkInfo.PrivateIPv4)
 	d.Set("access_public_ipv6", networkInfo.PublicIPv6)
+	if networkInfo.IPv4SubnetSize > 0 {
+		// the addresses are not known yet when not waiting for the device
+		d.Set("public_ipv4_subnet_size", 1<<(32-networkInfo.IPv4SubnetSize))
+	}
+	if d.IsNewResource() {
+		// the blocks are only recorded by the create, a block found on a later
+		// refresh may have been attached from an existing reservation
+		d.Set("dedicated_ip_reservation_ids", dedicatedIPReservationIDs(device, configuredIPReservationIDs(d)))
+	}
+
+	diags := readDeviceBgpNeighbors(ctx, client, d, device)
 
 	ports := getPorts(device.NetworkPorts)
 	d.Set("ports", ports)
@@ -670,6 +1696,36 @@ func resourceMetalDeviceRead(ctx context.Context, d *schema.ResourceData, meta i
 		})
 	}
 
+	return diags
+}
+
+// readDeviceBgpNeighbors sets the BGP neighbors of a device. They are only
+// listed when the project of the device has a BGP config, or the device had
+// neighbors before. The neighbors are informational, so failing to list them
+// is reported as a warning and keeps the neighbors in state rather than
+// failing the refresh.
+func readDeviceBgpNeighbors(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData, device *metalv1.Device) diag.Diagnostics {
+	project := device.GetProject()
+	if !project.HasBgpConfig() && len(d.Get("bgp_neighbors").([]interface{})) == 0 {
+		d.Set("bgp_neighbors", []map[string]interface{}{})
+		return nil
+	}
+
+	bgpNeighbors, resp, err := client.DevicesApi.GetBgpNeighborData(ctx, d.Id()).Execute()
+	if err != nil {
+		if resp != nil && slices.Contains([]int{http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity}, resp.StatusCode) {
+			// devices without BGP sessions have no neighbors to list
+			d.Set("bgp_neighbors", []map[string]interface{}{})
+			return nil
+		}
+		return diag.Diagnostics{{
+			Severity:      diag.Warning,
+			Summary:       "Failed to read the BGP neighbors of the device",
+			Detail:        fmt.Sprintf("BGP neighbors of device (%s) are kept as last read: %s", d.Id(), equinix_errors.FriendlyErrorForMetalGo(err, resp)),
+			AttributePath: cty.GetAttrPath("bgp_neighbors"),
+		}}
+	}
+	d.Set("bgp_neighbors", getBgpNeighbors(bgpNeighbors))
 	return nil
 }
 
@@ -703,18 +1759,30 @@ func resourceMetalDeviceUpdate(ctx context.Context, d *schema.ResourceData, meta
 		ur.Hostname = &dHostname
 	}
 	if d.HasChange("tags") {
-		ts := d.Get("tags")
-		sts := []string{}
+		oldTags, newTags := d.GetChange("tags")
+		oldList, oldOk := oldTags.([]interface{})
+		newList, newOk := newTags.([]interface{})
+		if !oldOk || !newOk {
+			return diag.Errorf("garbage in tags: %s", newTags)
+		}
 
-		switch ts.(type) {
-		case []interface{}:
-			for _, v := range ts.([]interface{}) {
-				sts = append(sts, v.(string))
-			}
-			ur.Tags = sts
-		default:
-			return diag.Errorf("garbage in tags: %s", ts)
+		// Re-read the tags right before writing them so that tags added or
+		// removed concurrently by someone else are not clobbered; only the
+		// changes made in this configuration are applied on top.
+		current, resp, err := client.DevicesApi.FindDeviceById(ctx, d.Id()).Execute()
+		if err != nil {
+			return diag.FromErr(equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		}
+		ur.Tags = mergeTags(current.GetTags(), converters.IfArrToStringArr(oldList), converters.IfArrToStringArr(newList))
+	}
+	if d.HasChange("termination_time") {
+		// termination_time is not part of the DeviceUpdateInput model, a
+		// removed termination_time is cleared with null
+		var tt interface{}
+		if v := d.Get("termination_time").(string); v != "" {
+			tt = v
 		}
+		ur.AdditionalProperties = map[string]interface{}{"termination_time": tt}
 	}
 	if d.HasChange("ipxe_script_url") {
 		dUrl := d.Get("ipxe_script_url").(string)
@@ -727,34 +1795,78 @@ func resourceMetalDeviceUpdate(ctx context.Context, d *schema.ResourceData, meta
 
 	start := time.Now()
 	if !reflect.DeepEqual(ur, metalv1.DeviceUpdateInput{}) {
-		if _, _, err := client.DevicesApi.UpdateDevice(ctx, d.Id()).DeviceUpdateInput(ur).Execute(); err != nil {
-			return diag.FromErr(equinix_errors.FriendlyError(err))
+		if _, resp, err := client.DevicesApi.UpdateDevice(ctx, d.Id()).DeviceUpdateInput(ur).Execute(); err != nil {
+			return deviceErrorDiagnostics(fmt.Sprintf("Error updating device (%s)", d.Id()), resp, err)
 		}
 	}
 
-	if err := doReinstall(ctx, client, d, meta, start); err != nil {
+	reinstalled, err := doReinstall(ctx, client, d, meta, start)
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if !reinstalled {
+		if err := doReboot(ctx, client, d, meta, start); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return resourceMetalDeviceRead(ctx, d, meta)
 }
 
-func doReinstall(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData, meta interface{}, start time.Time) error {
+// managedTags returns the managed tags that are still present on a device, in
+// their managed order
+func managedTags(current, managed []string) []string {
+	tags := make([]string, 0, len(managed))
+	for _, t := range managed {
+		if slices.Contains(current, t) {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// mergeTags applies the difference between the old and new configured tags
+// to the current tags of a device. Tags that are present on the device but
+// were never managed by the configuration are preserved, and the configured
+// order is kept for managed tags.
+func mergeTags(current, old, new []string) []string {
+	merged := make([]string, 0, len(new)+len(current))
+	merged = append(merged, new...)
+
+	for _, t := range current {
+		if slices.Contains(old, t) || slices.Contains(merged, t) {
+			// removed from or still present in the configuration
+			continue
+		}
+		merged = append(merged, t)
+	}
+	return merged
+}
+
+func doReinstall(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData, meta interface{}, start time.Time) (bool, error) {
 	if d.HasChange("operating_system") || d.HasChange("user_data") || d.HasChange("custom_data") {
+		preferReinstall := d.Get("prefer_reinstall_over_recreate").(bool)
 		reinstall, ok := d.GetOk("reinstall")
 
-		if !ok {
+		if !ok && !preferReinstall {
 			// Assume we're here because behavior.allow_changes was set (not an error)
-			return nil
+			return false, nil
 		}
 
-		reinstall_list := reinstall.([]interface{})
-		reinstall_config := reinstall_list[0].(map[string]interface{})
+		reinstall_config := map[string]interface{}{
+			"enabled":          false,
+			"preserve_data":    false,
+			"deprovision_fast": false,
+		}
+		if ok {
+			reinstall_config = reinstall.([]interface{})[0].(map[string]interface{})
+		}
 
-		if !reinstall_config["enabled"].(bool) {
+		if !reinstall_config["enabled"].(bool) && !preferReinstall {
 			// This means a reinstall block was provided, but reinstall was explicitly
 			// disabled.  Assume we're here because behavior.allow_changes was set (not an error)
-			return nil
+			return false, nil
 		}
 
 		reinstallOptions := metalv1.DeviceActionInput{
@@ -764,17 +1876,113 @@ func doReinstall(ctx context.Context, client *metalv1.APIClient, d *schema.Resou
 			DeprovisionFast: metalv1.PtrBool(reinstall_config["deprovision_fast"].(bool)),
 		}
 
+		// Reinstalling a device is expected to keep its port configuration, but
+		// VLAN attachments have been observed to be dropped. They are recorded
+		// here and reapplied once the device is active again, since the
+		// equinix_metal_port_vlan_attachment resources would not notice until
+		// the next refresh
+		vlanAssignments, err := getPortVlanAssignments(ctx, client, d.Id())
+		if err != nil {
+			return false, err
+		}
+
 		if _, err := client.DevicesApi.PerformAction(ctx, d.Id()).DeviceActionInput(reinstallOptions).Execute(); err != nil {
-			return equinix_errors.FriendlyError(err)
+			return false, equinix_errors.FriendlyError(err)
 		}
 
-		updateTimeout := d.Timeout(schema.TimeoutUpdate) - 30*time.Second - time.Since(start)
+		updateTimeout := wait.Remaining(d.Timeout(schema.TimeoutUpdate), start)
 		if err := waitForActiveDevice(ctx, d, meta, updateTimeout); err != nil {
-			return err
+			return false, err
 		}
+
+		if len(vlanAssignments) > 0 {
+			current, err := getPortVlanAssignments(ctx, client, d.Id())
+			if err != nil {
+				return false, err
+			}
+			if _, err := restorePortVlanAssignments(ctx, client, vlanAssignments, current); err != nil {
+				return false, fmt.Errorf("device %s was reinstalled but its VLAN attachments could not be restored: %w", d.Id(), err)
+			}
+		}
+		return true, nil
 	}
 
-	return nil
+	return false, nil
+}
+
+// doReboot reboots the device after its user_data has been updated, if
+// reboot_on_user_data_change is enabled, so that cloud-init runs again with the
+// new data, or when the reboot triggers have changed. The device is rebooted
+// at most once per update.
+func doReboot(ctx context.Context, client *metalv1.APIClient, d *schema.ResourceData, meta interface{}, start time.Time) error {
+	userDataChanged := d.HasChange("user_data") && d.Get("reboot_on_user_data_change").(bool)
+	if !userDataChanged && !rebootTriggersChanged(d.GetChange("reboot")) {
+		return nil
+	}
+
+	rebootOptions := metalv1.DeviceActionInput{
+		Type: metalv1.DEVICEACTIONINPUTTYPE_REBOOT,
+	}
+	if _, err := client.DevicesApi.PerformAction(ctx, d.Id()).DeviceActionInput(rebootOptions).Execute(); err != nil {
+		return equinix_errors.FriendlyError(err)
+	}
+
+	updateTimeout := wait.Remaining(d.Timeout(schema.TimeoutUpdate), start)
+	return waitForActiveDevice(ctx, d, meta, updateTimeout)
+}
+
+// rebootTriggersChanged reports whether the reboot triggers differ between the
+// old and new reboot block. Adding the block to a device that had none only
+// records the triggers, so that configuring it on an existing or imported
+// device does not reboot it, and removing the block only forgets them.
+func rebootTriggersChanged(old, new interface{}) bool {
+	oldList, _ := old.([]interface{})
+	newList, _ := new.([]interface{})
+	if len(oldList) == 0 || len(newList) == 0 {
+		return false
+	}
+	return !reflect.DeepEqual(rebootTriggers(oldList), rebootTriggers(newList))
+}
+
+func rebootTriggers(reboot []interface{}) map[string]interface{} {
+	triggers := map[string]interface{}{}
+	if len(reboot) == 0 || reboot[0] == nil {
+		return triggers
+	}
+	if t, ok := reboot[0].(map[string]interface{})["triggers"].(map[string]interface{}); ok {
+		triggers = t
+	}
+	return triggers
+}
+
+// drainDevice adds tag to a device and waits for gracePeriod, or until ctx is
+// done, so that controllers watching the tag can evacuate the device before it
+// is deleted. A device that is gone already is not waited for.
+func drainDevice(ctx context.Context, client *metalv1.APIClient, id, tag string, gracePeriod time.Duration) error {
+	device, resp, err := client.DevicesApi.FindDeviceById(ctx, id).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		if equinix_errors.IsNotFound(err) || equinix_errors.IsForbidden(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading device (%s) to drain it: %w", id, err)
+	}
+
+	if !slices.Contains(device.GetTags(), tag) {
+		tags := append(device.GetTags(), tag)
+		_, resp, err = client.DevicesApi.UpdateDevice(ctx, id).DeviceUpdateInput(metalv1.DeviceUpdateInput{Tags: tags}).Execute()
+		if err != nil {
+			return fmt.Errorf("error tagging device (%s) to drain it: %w", id, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		}
+	}
+
+	log.Printf("[DEBUG] Waiting %s for device (%s) to be drained", gracePeriod, id)
+	select {
+	case <-time.After(gracePeriod):
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting for device (%s) to be drained: %w", id, ctx.Err())
+	}
 }
 
 func resourceMetalDeviceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -788,48 +1996,93 @@ func resourceMetalDeviceDelete(ctx context.Context, d *schema.ResourceData, meta
 
 	start := time.Now()
 
+	if drain := d.Get("drain_before_destroy").([]interface{}); len(drain) > 0 && drain[0] != nil {
+		drainConf := drain[0].(map[string]interface{})
+		gracePeriod := time.Duration(drainConf["drain_timeout"].(int)) * time.Second
+		if err := drainDevice(ctx, client, d.Id(), drainConf["tag"].(string), gracePeriod); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	resp, err := client.DevicesApi.DeleteDevice(ctx, d.Id()).ForceDelete(fdv).Execute()
 	if equinix_errors.IgnoreHttpResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
 		return diag.FromErr(equinix_errors.FriendlyError(err))
 	}
 
+	if err == nil {
+		// avoid "context: deadline exceeded"
+		timeout := wait.Remaining(d.Timeout(schema.TimeoutDelete), start)
+
+		if err := waitUntilDeviceDeleted(ctx, client, d.Id(), wait.DefaultDelay, timeout, wait.DefaultMinTimeout); err != nil {
+			return diag.Errorf("error waiting for device (%s) to be deleted: %s", d.Id(), err)
+		}
+	}
+
 	resId, resIdOk := d.GetOk("deployed_hardware_reservation_id")
 	if resIdOk {
 		wfrd, wfrdOK := d.GetOk("wait_for_reservation_deprovision")
 		if wfrdOK && wfrd.(bool) {
 			// avoid "context: deadline exceeded"
-			timeout := d.Timeout(schema.TimeoutDelete) - 30*time.Second - time.Since(start)
+			timeout := wait.Remaining(d.Timeout(schema.TimeoutDelete), start)
 
-			err := waitUntilReservationProvisionable(ctx, client, resId.(string), d.Id(), 10*time.Second, timeout, 3*time.Second)
+			err := waitUntilReservationProvisionable(ctx, client, resId.(string), d.Id(), wait.DefaultDelay, timeout, wait.DefaultMinTimeout)
 			if err != nil {
 				return diag.FromErr(err)
 			}
 		}
 	}
+
+	if d.Get("release_dedicated_ips_on_destroy").(bool) {
+		ids := converters.IfArrToStringArr(d.Get("dedicated_ip_reservation_ids").([]interface{}))
+		if err := releaseIPReservations(ctx, client, ids); err != nil {
+			return diag.Errorf("error releasing IP reservations of device (%s): %s", d.Id(), err)
+		}
+	}
 	return nil
 }
 
+// activeDeviceState returns the state of the device as seen by
+// waitForActiveDevice. An active device is reported as still pending until its
+// provisioning percentage reaches threshold, unless the API does not report one.
+func activeDeviceState(device *metalv1.Device, threshold int) string {
+	state := fmt.Sprint(device.GetState())
+	if state != string(metalv1.DEVICESTATE_ACTIVE) || threshold <= 0 {
+		return state
+	}
+
+	percentage, ok := device.GetProvisioningPercentageOk()
+	if !ok {
+		log.Printf("[WARN] Device (%s) does not report a provisioning percentage, ignoring wait_for_percentage", device.GetId())
+		return state
+	}
+	if *percentage < float32(threshold) {
+		log.Printf("[DEBUG] Device (%s) is active but only %.0f%% provisioned, waiting for %d%%", device.GetId(), *percentage, threshold)
+		return provisioningPercentage
+	}
+	return state
+}
+
 func waitForActiveDevice(ctx context.Context, d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
 	targets := []string{"active", "failed"}
-	pending := []string{"queued", "provisioning", "reinstalling"}
-
-	stateConf := &retry.StateChangeConf{
-		Pending: pending,
-		Target:  targets,
-		Refresh: func() (interface{}, string, error) {
-			client := meta.(*config.Config).NewMetalClientForSDK(d)
-
-			device, _, err := client.DevicesApi.FindDeviceById(ctx, d.Id()).Include([]string{"project"}).Execute()
-			if err == nil {
-				retAttrVal := fmt.Sprint(device.GetState())
-				return retAttrVal, retAttrVal, nil
+	pending := []string{"queued", "provisioning", "reinstalling", "powering_off", "powering_on", firmwareUpdating, provisioningPercentage}
+	threshold := d.Get("wait_for_percentage").(int)
+
+	stateConf := wait.StateConf(func() (interface{}, string, error) {
+		client := meta.(*config.Config).NewMetalClientForSDK(d)
+
+		device, resp, err := client.DevicesApi.FindDeviceById(ctx, d.Id()).Include([]string{"project"}).Execute()
+		if err == nil {
+			retAttrVal := activeDeviceState(device, threshold)
+			return retAttrVal, retAttrVal, nil
+		}
+		if state, ok := undecodedDeviceState(resp, err); ok {
+			if state == firmwareUpdating {
+				log.Printf("[DEBUG] Device (%s) is updating its firmware, waiting for it to become active", d.Id())
 			}
-			return "error", "error", err
-		},
-		Timeout:    timeout,
-		Delay:      10 * time.Second,
-		MinTimeout: 3 * time.Second,
-	}
+			return state, state, nil
+		}
+		return "error", "error", err
+	}, pending, targets, timeout, wait.WithDelay(wait.DefaultDelay), wait.WithMinTimeout(wait.DefaultMinTimeout))
 
 	// Wait for the device so we can get the networking attributes that show up after a while.
 	state, err := waitForDeviceAttribute(ctx, d, stateConf)
@@ -882,6 +2135,9 @@ func setupDeviceCreateRequest(d *schema.ResourceData, createRequest deviceCreate
 
 		addressTypesSlice = getNewIPAddressSlice(arr)
 	}
+	if size, ok := d.GetOk("public_ipv4_subnet_size"); ok {
+		addressTypesSlice = publicIPv4SubnetAddresses(size.(int))
+	}
 
 	if hostname, ok := d.GetOk("hostname"); ok {
 		createRequest.SetHostname(hostname.(string))
@@ -927,7 +2183,7 @@ func setupDeviceCreateRequest(d *schema.ResourceData, createRequest deviceCreate
 
 	if attr, ok := d.GetOk("hardware_reservation_id"); ok {
 		createRequest.SetHardwareReservationId(attr.(string))
-	} else {
+	} else if _, ok := d.GetOk("hardware_reservation_pool"); !ok {
 		wfrd := "wait_for_reservation_deprovision"
 		if d.Get(wfrd).(bool) {
 			return diag.Errorf("You can't set %s when not using a hardware reservation", wfrd)
@@ -983,17 +2239,38 @@ func setupDeviceCreateRequest(d *schema.ResourceData, createRequest deviceCreate
 		if err != nil {
 			return diag.Errorf("storage param contains invalid JSON: %s", err)
 		}
-		var storage metalv1.Storage
-		err = json.Unmarshal([]byte(s), &storage)
+		storage, err := expandDeviceStorage(s)
 		if err != nil {
 			return diag.Errorf("error parsing Storage string: %s", err)
 		}
-		createRequest.SetStorage(storage)
+		createRequest.SetStorage(*storage)
 	}
 
 	return nil
 }
 
+// publicIPv4SubnetAddresses returns the addresses a device is deployed with by
+// default, with a public IPv4 subnet of the given number of addresses. Once
+// any address is requested the API does not add the others, so the private
+// IPv4 and public IPv6 addresses are requested as well.
+func publicIPv4SubnetAddresses(size int) []metalv1.IPAddress {
+	cidr := 32 - bits.Len(uint(size)) + 1
+	public := metalv1.IPAddress{}
+	public.SetAddressFamily(4)
+	public.SetPublic(true)
+	public.SetCidr(int32(cidr))
+
+	private := metalv1.IPAddress{}
+	private.SetAddressFamily(4)
+	private.SetPublic(false)
+
+	ipv6 := metalv1.IPAddress{}
+	ipv6.SetAddressFamily(6)
+	ipv6.SetPublic(true)
+
+	return []metalv1.IPAddress{public, private, ipv6}
+}
+
 func getNewIPAddressSlice(arr []interface{}) []metalv1.IPAddress {
 	addressTypesSlice := make([]metalv1.IPAddress, len(arr))
 
@@ -200,6 +200,57 @@ func TestAccMetalDevice_sshConfig(t *testing.T) {
 	})
 }
 
+func TestAccMetalDevice_sshKeyChange(t *testing.T) {
+	var d1, d2 metalv1.Device
+	rs := acctest.RandString(10)
+	r := "equinix_metal_device.test"
+	userSSHKey, _, err := acctest.RandSSHKeyPair("")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	projSSHKey, _, err := acctest.RandSSHKeyPair("")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	rotatedSSHKey, _, err := acctest.RandSSHKeyPair("")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		ExternalProviders:        testExternalProviders,
+		CheckDestroy:             testAccMetalDeviceCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalDeviceConfig_ssh_key(rs, userSSHKey, projSSHKey),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalDeviceExists(r, &d1),
+					resource.TestCheckTypeSetElemAttrPair(
+						r,
+						"ssh_key_ids.*",
+						"equinix_metal_project_ssh_key.test",
+						"id",
+					),
+				),
+			},
+			{
+				Config: testAccMetalDeviceConfig_ssh_key_rotated(rs, userSSHKey, projSSHKey, rotatedSSHKey),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalDeviceExists(r, &d2),
+					testAccMetalDeviceRecreated(t, &d1, &d2),
+					resource.TestCheckTypeSetElemAttrPair(
+						r,
+						"ssh_key_ids.*",
+						"equinix_metal_project_ssh_key.rotated",
+						"id",
+					),
+				),
+			},
+		},
+	})
+}
+
 func TestAccMetalDevice_basic(t *testing.T) {
 	var device metalv1.Device
 	rs := acctest.RandString(10)
@@ -418,6 +469,41 @@ func TestAccMetalDevice_allowUserdataChanges(t *testing.T) {
 	})
 }
 
+func TestAccMetalDevice_rebootOnUserdataChange(t *testing.T) {
+	var d1, d2 metalv1.Device
+	rs := acctest.RandString(10)
+	rInt := acctest.RandInt()
+	r := "equinix_metal_device.test"
+
+	userdata1 := fmt.Sprintf("#!/usr/bin/env sh\necho 'Reboot on userdata change %d'\n", rInt)
+	userdata2 := fmt.Sprintf("#!/usr/bin/env sh\necho 'Reboot on userdata change %d'\n", rInt+1)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ExternalProviders:        testExternalProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccMetalDeviceCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalDeviceConfig_rebootOnUserdataChange(rInt, rs, userdata1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalDeviceExists(r, &d1),
+					resource.TestCheckResourceAttr(r, "user_data", userdata1),
+				),
+			},
+			{
+				Config: testAccMetalDeviceConfig_rebootOnUserdataChange(rInt, rs, userdata2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalDeviceExists(r, &d2),
+					resource.TestCheckResourceAttr(r, "user_data", userdata2),
+					resource.TestCheckResourceAttr(r, "state", "active"),
+					testAccMetalSameDevice(t, &d1, &d2),
+				),
+			},
+		},
+	})
+}
+
 func TestAccMetalDevice_allowCustomdataChanges(t *testing.T) {
 	var d1, d2 metalv1.Device
 	rs := acctest.RandString(10)
@@ -530,6 +616,15 @@ func testAccMetalSameDevice(t *testing.T, before, after *metalv1.Device) resourc
 	}
 }
 
+func testAccMetalDeviceRecreated(t *testing.T, before, after *metalv1.Device) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if before.GetId() == after.GetId() {
+			t.Fatalf("Expected device to be recreated, but it was kept: %s", before.GetId())
+		}
+		return nil
+	}
+}
+
 func testAccMetalDeviceNetworkOrder(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -691,6 +786,28 @@ resource "equinix_metal_device" "test" {
 `, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), projSuffix, rInt, rInt, userdata, customdata, testDeviceTerminationTime(), attributeName)
 }
 
+func testAccMetalDeviceConfig_rebootOnUserdataChange(rInt int, projSuffix string, userdata string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "equinix_metal_project" "test" {
+    name = "tfacc-device-%s"
+}
+
+resource "equinix_metal_device" "test" {
+  hostname                   = "tfacc-test-device-%d"
+  plan                       = local.plan
+  metro                      = local.metro
+  operating_system           = local.os
+  billing_cycle              = "hourly"
+  project_id                 = "${equinix_metal_project.test.id}"
+  user_data                  = %q
+  reboot_on_user_data_change = true
+  termination_time           = "%s"
+}
+`, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), projSuffix, rInt, userdata, testDeviceTerminationTime())
+}
+
 func testAccMetalDeviceConfig_varname(rInt int, projSuffix string) string {
 	return fmt.Sprintf(`
 %s
@@ -781,6 +898,44 @@ resource "equinix_metal_device" "test" {
 `, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), projSuffix, projSuffix, userSSHKey, projSSHKey, projSSHKey)
 }
 
+func testAccMetalDeviceConfig_ssh_key_rotated(projSuffix, userSSHKey, projSSHKey, rotatedSSHKey string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "equinix_metal_project" "test" {
+    name = "tfacc-device-%s"
+}
+
+resource "equinix_metal_ssh_key" "test" {
+	name = "tfacc-ssh-key-%s"
+	public_key = "%s"
+}
+
+resource "equinix_metal_project_ssh_key" "test" {
+	project_id = equinix_metal_project.test.id
+	name = "tfacc-project-key-%s"
+	public_key = "%s"
+}
+
+resource "equinix_metal_project_ssh_key" "rotated" {
+	project_id = equinix_metal_project.test.id
+	name = "tfacc-project-key-rotated-%s"
+	public_key = "%s"
+}
+
+resource "equinix_metal_device" "test" {
+	hostname         = "tfacc-test-device"
+	plan             = local.plan
+	metro            = local.metro
+	operating_system = local.os
+	billing_cycle    = "hourly"
+	project_id       = equinix_metal_project.test.id
+	user_ssh_key_ids = [equinix_metal_ssh_key.test.owner_id]
+	project_ssh_key_ids = [equinix_metal_project_ssh_key.rotated.id]
+  }
+`, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), projSuffix, projSuffix, userSSHKey, projSSHKey, projSSHKey, projSuffix, rotatedSSHKey)
+}
+
 func testAccMetalDeviceConfig_facility_list(projSuffix string) string {
 	return fmt.Sprintf(`
 %s
@@ -1155,6 +1310,55 @@ func TestAccMetalDevice_LockingAndUnlocking(t *testing.T) {
 	})
 }
 
+func TestAccMetalDevice_lockedDrift(t *testing.T) {
+	var d1, d2 metalv1.Device
+	rs := acctest.RandString(10)
+	r := "equinix_metal_device.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ExternalProviders:        testExternalProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccMetalDeviceCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalDeviceConfig_lockable(rs, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalDeviceExists(r, &d1),
+					resource.TestCheckResourceAttr(r, "locked", "false"),
+				),
+			},
+			{
+				// Lock the device out of band, Terraform must detect the
+				// drift and unlock it again
+				PreConfig: testAccMetalDeviceSetLocked(t, &d1, true),
+				Config:    testAccMetalDeviceConfig_lockable(rs, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalDeviceExists(r, &d2),
+					testAccMetalSameDevice(t, &d1, &d2),
+					resource.TestCheckResourceAttr(r, "locked", "false"),
+					func(s *terraform.State) error {
+						if d2.GetLocked() {
+							return fmt.Errorf("expected device %s to be unlocked", d2.GetId())
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccMetalDeviceSetLocked(t *testing.T, device *metalv1.Device, locked bool) func() {
+	return func() {
+		client := testAccProvider.Meta().(*config.Config).NewMetalClientForTesting()
+		ur := metalv1.DeviceUpdateInput{Locked: metalv1.PtrBool(locked)}
+		if _, _, err := client.DevicesApi.UpdateDevice(context.TODO(), device.GetId()).DeviceUpdateInput(ur).Execute(); err != nil {
+			t.Fatalf("failed to set locked=%v on device %s: %v", locked, device.GetId(), err)
+		}
+	}
+}
+
 func testAccMetalDeviceConfig_lockable(projSuffix string, locked bool) string {
 	return fmt.Sprintf(`
 %s
@@ -1,26 +1,47 @@
 package equinix
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"time"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	"github.com/equinix/terraform-provider-equinix/internal/network"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/packethost/packngo"
 )
 
+const (
+	deviceNetworkingConverging = "converging"
+	deviceNetworkingConverged  = "converged"
+)
+
+// deviceNetworkingWaitOpts tune the waiter for the ports and addresses of a
+// device to match its new network type
+var deviceNetworkingWaitOpts = []wait.Option{
+	wait.WithDelay(5 * time.Second),
+	wait.WithMinTimeout(wait.DefaultMinTimeout),
+}
+
 func resourceMetalDeviceNetworkType() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceMetalDeviceNetworkTypeCreate,
-		Read:   resourceMetalDeviceNetworkTypeRead,
-		Delete: resourceMetalDeviceNetworkTypeDelete,
-		Update: resourceMetalDeviceNetworkTypeUpdate,
+		CreateContext: resourceMetalDeviceNetworkTypeCreate,
+		ReadContext:   resourceMetalDeviceNetworkTypeRead,
+		DeleteContext: resourceMetalDeviceNetworkTypeDelete,
+		UpdateContext: resourceMetalDeviceNetworkTypeUpdate,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceMetalDeviceNetworkTypeImportState,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -36,10 +57,23 @@ func resourceMetalDeviceNetworkType() *schema.Resource {
 				Required:     true,
 				ValidateFunc: validation.StringInSlice(network.DeviceNetworkTypesHB, false),
 			},
+			"wait_for_device_networking": {
+				Type:        schema.TypeBool,
+				Description: "Whether to wait, up to the create or update timeout, until the ports and management addresses of the device match the network type after it is changed",
+				Optional:    true,
+				Default:     false,
+			},
 		},
 	}
 }
 
+func resourceMetalDeviceNetworkTypeImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("wait_for_device_networking", false); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func getDevIDandNetworkType(d *schema.ResourceData, c *packngo.Client) (string, string, error) {
 	deviceID := d.Id()
 	if len(deviceID) == 0 {
@@ -75,20 +109,53 @@ func getAndPossiblySetNetworkType(d *schema.ResourceData, c *packngo.Client, tar
 	return nil
 }
 
-func resourceMetalDeviceNetworkTypeCreate(d *schema.ResourceData, meta interface{}) error {
+// waitForDeviceNetworking waits until the network type reported for a device
+// is the target type, and the device has management addresses only if the type
+// is a layer 3 one. Converting the ports of a device is asynchronous, so they
+// and the addresses may still be moving right after the conversion.
+func waitForDeviceNetworking(ctx context.Context, c *packngo.Client, deviceID, targetType string, timeout time.Duration) error {
+	if targetType == "hybrid-bonded" {
+		targetType = packngo.NetworkTypeL3
+	}
+	layer3 := targetType == packngo.NetworkTypeL3 || targetType == packngo.NetworkTypeHybrid
+
+	refresh := func() (interface{}, string, error) {
+		dev, _, err := c.Devices.Get(deviceID, nil)
+		if err != nil {
+			return nil, "", equinix_errors.FriendlyError(err)
+		}
+		if dev.GetNetworkType() != targetType || dev.HasManagementIPs() != layer3 {
+			return dev, deviceNetworkingConverging, nil
+		}
+		return dev, deviceNetworkingConverged, nil
+	}
+	_, err := wait.ForState(ctx, refresh, []string{deviceNetworkingConverging}, []string{deviceNetworkingConverged}, timeout, deviceNetworkingWaitOpts...)
+	if err != nil {
+		return fmt.Errorf("error waiting for the networking of device %s to match network type %s: %w", deviceID, targetType, err)
+	}
+	return nil
+}
+
+func resourceMetalDeviceNetworkTypeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	start := time.Now()
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
 
 	ntype := d.Get("type").(string)
 	err := getAndPossiblySetNetworkType(d, client, ntype)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(d.Get("device_id").(string))
-	return resourceMetalDeviceNetworkTypeRead(d, meta)
+	if d.Get("wait_for_device_networking").(bool) {
+		if err := waitForDeviceNetworking(ctx, client, d.Id(), ntype, wait.Remaining(d.Timeout(schema.TimeoutCreate), start)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceMetalDeviceNetworkTypeRead(ctx, d, meta)
 }
 
-func resourceMetalDeviceNetworkTypeRead(d *schema.ResourceData, meta interface{}) error {
+func resourceMetalDeviceNetworkTypeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
 
@@ -102,7 +169,7 @@ func resourceMetalDeviceNetworkTypeRead(d *schema.ResourceData, meta interface{}
 			return nil
 		}
 
-		return err
+		return diag.FromErr(err)
 	}
 
 	// if "hybrid-bonded" is set as desired state and current state is "layer3",
@@ -117,7 +184,8 @@ func resourceMetalDeviceNetworkTypeRead(d *schema.ResourceData, meta interface{}
 	return nil
 }
 
-func resourceMetalDeviceNetworkTypeUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceMetalDeviceNetworkTypeUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	start := time.Now()
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
 
@@ -125,12 +193,17 @@ func resourceMetalDeviceNetworkTypeUpdate(d *schema.ResourceData, meta interface
 	if d.HasChange("type") {
 		err := getAndPossiblySetNetworkType(d, client, ntype)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
+		}
+		if d.Get("wait_for_device_networking").(bool) {
+			if err := waitForDeviceNetworking(ctx, client, d.Id(), ntype, wait.Remaining(d.Timeout(schema.TimeoutUpdate), start)); err != nil {
+				return diag.FromErr(err)
+			}
 		}
 	}
-	return resourceMetalDeviceNetworkTypeRead(d, meta)
+	return resourceMetalDeviceNetworkTypeRead(ctx, d, meta)
 }
 
-func resourceMetalDeviceNetworkTypeDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceMetalDeviceNetworkTypeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	return nil
 }
@@ -0,0 +1,92 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
+)
+
+func TestWaitForDeviceNetworking(t *testing.T) {
+	waitOpts := deviceNetworkingWaitOpts
+	t.Cleanup(func() { deviceNetworkingWaitOpts = waitOpts })
+	deviceNetworkingWaitOpts = []wait.Option{wait.WithDelay(0), wait.WithMinTimeout(10 * time.Millisecond)}
+
+	const (
+		layer3  = `{"id": "a1b2c3d4-0000-0000-0000-000000000000", "network_ports": [{"type": "NetworkBondPort", "name": "bond0", "data": {"bonded": true}}, {"type": "NetworkPort", "name": "eth0", "data": {"bonded": true}}], "ip_addresses": [{"management": true, "public": true, "address_family": 4}]}`
+		noIPs   = `{"id": "a1b2c3d4-0000-0000-0000-000000000000", "network_ports": [{"type": "NetworkBondPort", "name": "bond0", "data": {"bonded": true}}, {"type": "NetworkPort", "name": "eth0", "data": {"bonded": true}}]}`
+		unbound = `{"id": "a1b2c3d4-0000-0000-0000-000000000000", "network_ports": [{"type": "NetworkBondPort", "name": "bond0", "data": {"bonded": false}}, {"type": "NetworkPort", "name": "eth0", "data": {"bonded": false}}]}`
+	)
+
+	tests := []struct {
+		name       string
+		targetType string
+		devices    []string
+		wantErr    bool
+	}{
+		{
+			name:       "layer3 addresses assigned later",
+			targetType: "layer3",
+			devices:    []string{noIPs, noIPs, layer3},
+		},
+		{
+			name:       "hybrid-bonded",
+			targetType: "hybrid-bonded",
+			devices:    []string{layer3},
+		},
+		{
+			name:       "layer2-bonded addresses removed later",
+			targetType: "layer2-bonded",
+			devices:    []string{layer3, noIPs},
+		},
+		{
+			name:       "layer2-individual",
+			targetType: "layer2-individual",
+			devices:    []string{unbound},
+		},
+		{
+			name:       "never converges",
+			targetType: "layer2-individual",
+			devices:    []string{layer3},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			reads := 0
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				if !strings.HasSuffix(r.URL.Path, "/devices/a1b2c3d4-0000-0000-0000-000000000000") {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(tt.devices[min(reads, len(tt.devices)-1)]))
+				reads++
+			}))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			err := waitForDeviceNetworking(ctx, meta.Metal, "a1b2c3d4-0000-0000-0000-000000000000", tt.targetType, time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("waitForDeviceNetworking() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && reads != len(tt.devices) {
+				t.Errorf("waitForDeviceNetworking() read the device %d times, want %d", reads, len(tt.devices))
+			}
+		})
+	}
+}
@@ -0,0 +1,1915 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/converters"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSuppressTrailingNewlineDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     bool
+	}{
+		{name: "equal", old: "#!/bin/sh\necho hi", new: "#!/bin/sh\necho hi", want: true},
+		{name: "trailing newline", old: "#!/bin/sh\necho hi", new: "#!/bin/sh\necho hi\n", want: true},
+		{name: "trailing crlf", old: "#!/bin/sh\r\necho hi\r\n", new: "#!/bin/sh\r\necho hi", want: true},
+		{name: "trailing carriage return", old: "echo hi\r", new: "echo hi", want: false},
+		{name: "inner crlf", old: "#!/bin/sh\r\necho hi", new: "#!/bin/sh\necho hi", want: false},
+		{name: "leading whitespace", old: "echo hi", new: " echo hi", want: false},
+		{name: "trailing space", old: "echo hi", new: "echo hi ", want: false},
+		{name: "inner newline", old: "echo hi\necho bye", new: "echo hi\n\necho bye", want: false},
+		{name: "unset", old: "", new: "echo hi", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressTrailingNewlineDiff("user_data", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressTrailingNewlineDiff(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMetadataSize(t *testing.T) {
+	validate := validateMetadataSize(8)
+
+	if _, errs := validate("12345678", "user_data"); len(errs) != 0 {
+		t.Errorf("validateMetadataSize() unexpected errors %v", errs)
+	}
+
+	_, errs := validate("123456789", "user_data")
+	if len(errs) != 1 {
+		t.Fatalf("validateMetadataSize() errors = %v, want 1 error", errs)
+	}
+	if got, want := errs[0].Error(), "user_data is 9 bytes, which exceeds the maximum of 8 bytes"; got != want {
+		t.Errorf("validateMetadataSize() error = %q, want %q", got, want)
+	}
+}
+
+func TestSuppressEquivalentTimeDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     bool
+	}{
+		{name: "equal", old: "2021-09-03T13:32:00Z", new: "2021-09-03T13:32:00Z", want: true},
+		{name: "other time zone", old: "2021-09-03T13:32:00Z", new: "2021-09-03T16:32:00+03:00", want: true},
+		{name: "fractional seconds", old: "2021-09-03T13:32:00Z", new: "2021-09-03T13:32:00.000Z", want: true},
+		{name: "different instant", old: "2021-09-03T13:32:00Z", new: "2021-09-03T16:32:00Z", want: false},
+		{name: "removed", old: "2021-09-03T13:32:00Z", new: "", want: false},
+		{name: "added", old: "", new: "2021-09-03T13:32:00Z", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressEquivalentTimeDiff("termination_time", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressEquivalentTimeDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuppressEquivalentCustomDataDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     bool
+	}{
+		{name: "equal", old: `{"foo":"bar"}`, new: `{"foo":"bar"}`, want: true},
+		{name: "key order and whitespace", old: `{"a":1,"b":[1,2]}`, new: "{\n  \"b\": [1, 2],\n  \"a\": 1\n}\n", want: true},
+		{name: "empty object", old: "", new: "{}", want: true},
+		{name: "changed value", old: `{"foo":"bar"}`, new: `{"foo":"baz"}`, want: false},
+		{name: "array order", old: `{"a":[1,2]}`, new: `{"a":[2,1]}`, want: false},
+		{name: "removed", old: `{"foo":"bar"}`, new: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suppressEquivalentCustomDataDiff("custom_data", tt.old, tt.new, nil); got != tt.want {
+				t.Errorf("suppressEquivalentCustomDataDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuppressDeployedHardwareReservationDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		deployed string
+		old, new string
+		want     bool
+	}{
+		{name: "pinned to deployed reservation", deployed: "reservation1", old: "next-available", new: "reservation1", want: true},
+		{name: "back to next-available", deployed: "reservation1", old: "reservation1", new: "next-available", want: false},
+		{name: "other reservation", deployed: "reservation1", old: "next-available", new: "reservation2", want: false},
+		{name: "not deployed on a reservation", deployed: "", old: "", new: "reservation1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{})
+			if tt.deployed != "" {
+				if err := d.Set("deployed_hardware_reservation_id", tt.deployed); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := suppressDeployedHardwareReservationDiff("hardware_reservation_id", tt.old, tt.new, d); got != tt.want {
+				t.Errorf("suppressDeployedHardwareReservationDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuppressReservationPlanDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      map[string]interface{}
+		deployed string
+		id       string
+		new      string
+		want     bool
+	}{
+		{name: "pinned reservation", raw: map[string]interface{}{"hardware_reservation_id": "reservation1"}, deployed: "reservation1", id: "device", want: true},
+		{name: "next-available", raw: map[string]interface{}{"hardware_reservation_id": "next-available"}, deployed: "reservation1", id: "device", want: false},
+		{name: "next-available with the deployed plan", raw: map[string]interface{}{"hardware_reservation_id": "next-available"}, deployed: "reservation1", id: "device", new: "M3.LARGE.X86", want: true},
+		{name: "pool", raw: map[string]interface{}{"hardware_reservation_pool": []interface{}{"reservation1", "reservation2"}}, deployed: "reservation1", id: "device", want: false},
+		{name: "pool with the deployed plan", raw: map[string]interface{}{"hardware_reservation_pool": []interface{}{"reservation1", "reservation2"}}, deployed: "reservation1", id: "device", new: "M3.LARGE.X86", want: true},
+		{name: "moved to other reservation", raw: map[string]interface{}{"hardware_reservation_id": "reservation2"}, deployed: "reservation1", id: "device", want: false},
+		{name: "reservation removed", raw: map[string]interface{}{}, deployed: "reservation1", id: "device", want: false},
+		{name: "on demand", raw: map[string]interface{}{}, id: "device", want: false},
+		{name: "not created", raw: map[string]interface{}{"hardware_reservation_id": "next-available"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, tt.raw)
+			d.SetId(tt.id)
+			if tt.deployed != "" {
+				if err := d.Set("deployed_hardware_reservation_id", tt.deployed); err != nil {
+					t.Fatal(err)
+				}
+			}
+			planned := "c3.small.x86"
+			if tt.new != "" {
+				planned = tt.new
+			}
+			if got := suppressReservationPlanDiff("plan", "m3.large.x86", planned, d); got != tt.want {
+				t.Errorf("suppressReservationPlanDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	tests := []struct {
+		name              string
+		current, old, new []string
+		want              []string
+	}{
+		{
+			name:    "add tag",
+			current: []string{"a"},
+			old:     []string{"a"},
+			new:     []string{"a", "b"},
+			want:    []string{"a", "b"},
+		},
+		{
+			name:    "remove tag",
+			current: []string{"a", "b"},
+			old:     []string{"a", "b"},
+			new:     []string{"a"},
+			want:    []string{"a"},
+		},
+		{
+			name:    "preserve concurrent addition",
+			current: []string{"a", "external"},
+			old:     []string{"a"},
+			new:     []string{"a", "b"},
+			want:    []string{"a", "b", "external"},
+		},
+		{
+			name:    "concurrent removal of unmanaged tag",
+			current: []string{"a"},
+			old:     []string{"a", "b"},
+			new:     []string{"a", "b", "c"},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "clear managed tags",
+			current: []string{"a", "external"},
+			old:     []string{"a"},
+			new:     []string{},
+			want:    []string{"external"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeTags(tt.current, tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRebootTriggersChanged(t *testing.T) {
+	block := func(triggers map[string]interface{}) []interface{} {
+		return []interface{}{map[string]interface{}{"triggers": triggers}}
+	}
+
+	tests := []struct {
+		name     string
+		old, new interface{}
+		want     bool
+	}{
+		{
+			name: "no block",
+			old:  []interface{}{},
+			new:  []interface{}{},
+		},
+		{
+			name: "block added",
+			old:  []interface{}{},
+			new:  block(map[string]interface{}{"kernel": "1"}),
+		},
+		{
+			name: "triggers unchanged",
+			old:  block(map[string]interface{}{"kernel": "1"}),
+			new:  block(map[string]interface{}{"kernel": "1"}),
+		},
+		{
+			name: "trigger changed",
+			old:  block(map[string]interface{}{"kernel": "1"}),
+			new:  block(map[string]interface{}{"kernel": "2"}),
+			want: true,
+		},
+		{
+			name: "trigger added to empty block",
+			old:  []interface{}{nil},
+			new:  block(map[string]interface{}{"kernel": "1"}),
+			want: true,
+		},
+		{
+			name: "block removed",
+			old:  block(map[string]interface{}{"kernel": "1"}),
+			new:  []interface{}{},
+		},
+		{
+			name: "triggers removed from block",
+			old:  block(map[string]interface{}{"kernel": "1"}),
+			new:  []interface{}{nil},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rebootTriggersChanged(tt.old, tt.new); got != tt.want {
+				t.Errorf("rebootTriggersChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDeviceImportState(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		if !strings.HasSuffix(r.URL.Path, "/devices/device-id") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id": "device-id", "tags": ["a", "external"]}`))
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{})
+	d.SetId("device-id")
+
+	got, err := resourceMetalDeviceImportState(ctx, d, meta)
+	if err != nil {
+		t.Fatalf("resourceMetalDeviceImportState() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Id() != "device-id" {
+		t.Fatalf("resourceMetalDeviceImportState() = %v, want the imported device", got)
+	}
+	if !got[0].Get("wait_for_active").(bool) {
+		t.Errorf("wait_for_active = false, want true")
+	}
+	if got[0].Get("force_detach_volumes").(bool) {
+		t.Errorf("force_detach_volumes = true, want false")
+	}
+	if action := got[0].Get("provisioning_timeout_action").(string); action != provisioningTimeoutDelete {
+		t.Errorf("provisioning_timeout_action = %q, want %q", action, provisioningTimeoutDelete)
+	}
+	if n := len(got[0].Get("reinstall").([]interface{})); n != 0 {
+		t.Errorf("reinstall has %d blocks, want none", n)
+	}
+	if tags := got[0].Get("tags").([]interface{}); !reflect.DeepEqual(tags, []interface{}{"a", "external"}) {
+		t.Errorf("tags = %v, want all tags of the device", tags)
+	}
+}
+
+func TestManagedTags(t *testing.T) {
+	tests := []struct {
+		name             string
+		current, managed []string
+		want             []string
+	}{
+		{
+			name:    "unmanaged tag",
+			current: []string{"external", "a"},
+			managed: []string{"a"},
+			want:    []string{"a"},
+		},
+		{
+			name:    "managed tag removed outside of terraform",
+			current: []string{"a"},
+			managed: []string{"a", "b"},
+			want:    []string{"a"},
+		},
+		{
+			name:    "managed order",
+			current: []string{"b", "a"},
+			managed: []string{"a", "b"},
+			want:    []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := managedTags(tt.current, tt.managed)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("managedTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveDeviceState(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      metalv1.DeviceState
+		percentage *float32
+		threshold  int
+		want       string
+	}{
+		{name: "provisioning", state: metalv1.DEVICESTATE_PROVISIONING, percentage: metalv1.PtrFloat32(50), threshold: 80, want: "provisioning"},
+		{name: "active without threshold", state: metalv1.DEVICESTATE_ACTIVE, percentage: metalv1.PtrFloat32(50), want: "active"},
+		{name: "active below threshold", state: metalv1.DEVICESTATE_ACTIVE, percentage: metalv1.PtrFloat32(50), threshold: 80, want: provisioningPercentage},
+		{name: "active above threshold", state: metalv1.DEVICESTATE_ACTIVE, percentage: metalv1.PtrFloat32(90), threshold: 80, want: "active"},
+		{name: "active without percentage", state: metalv1.DEVICESTATE_ACTIVE, threshold: 80, want: "active"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := &metalv1.Device{Id: metalv1.PtrString("deviceId"), State: tt.state.Ptr(), ProvisioningPercentage: tt.percentage}
+			if got := activeDeviceState(device, tt.threshold); got != tt.want {
+				t.Errorf("activeDeviceState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectPoolReservation(t *testing.T) {
+	available := []metalv1.HardwareReservation{
+		{Id: metalv1.PtrString("c")},
+		{Id: metalv1.PtrString("b")},
+		{Id: metalv1.PtrString("x")},
+	}
+
+	tests := []struct {
+		name string
+		pool []string
+		want string
+	}{
+		{name: "lowest available", pool: []string{"c", "a", "b"}, want: "b"},
+		{name: "single", pool: []string{"x"}, want: "x"},
+		{name: "none available", pool: []string{"a", "d"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectPoolReservation(available, tt.pool); got != tt.want {
+				t.Errorf("selectPoolReservation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsePreferredFacility(t *testing.T) {
+	handler := func(available bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Content-Type", "application/json")
+			w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/facilities"):
+				w.Write([]byte(`{"facilities": [{"code": "sv15", "metro": {"code": "sv"}}, {"code": "da11", "metro": {"code": "da"}}]}`))
+			case strings.HasSuffix(r.URL.Path, "/capacity"):
+				if available {
+					w.Write([]byte(`{"servers": [{"facility": "sv15", "plan": "c3.small.x86", "available": true}]}`))
+				} else {
+					w.Write([]byte(`{"servers": [{"facility": "sv15", "plan": "c3.small.x86", "available": false}]}`))
+				}
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		facility  string
+		available bool
+		raw       map[string]interface{}
+		want      bool
+		wantErr   string
+	}{
+		{
+			name:      "available",
+			facility:  "sv15",
+			available: true,
+			want:      true,
+		},
+		{
+			name:     "no capacity",
+			facility: "sv15",
+			wantErr:  "no capacity",
+		},
+		{
+			name:     "no capacity with fallback",
+			facility: "sv15",
+			raw:      map[string]interface{}{"preferred_facility_fallback": true},
+		},
+		{
+			name:     "hardware reservation",
+			facility: "sv15",
+			raw:      map[string]interface{}{"hardware_reservation_id": "next-available"},
+			want:     true,
+		},
+		{
+			name:      "other metro",
+			facility:  "da11",
+			available: true,
+			wantErr:   "is in metro",
+		},
+		{
+			name:      "unknown facility",
+			facility:  "xx1",
+			available: true,
+			wantErr:   "was not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(handler(tt.available))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			raw := map[string]interface{}{"plan": "c3.small.x86", "metro": "sv", "preferred_facility": tt.facility}
+			for k, v := range tt.raw {
+				raw[k] = v
+			}
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, raw)
+
+			got, diags := usePreferredFacility(ctx, meta.NewMetalClientForTesting(), d, "sv", tt.facility)
+			if tt.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.wantErr) {
+					t.Fatalf("usePreferredFacility() = %v, want error %q", diags, tt.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("usePreferredFacility() error = %v", diags)
+			}
+			if got != tt.want {
+				t.Errorf("usePreferredFacility() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHardwareReservationProject(t *testing.T) {
+	tests := []struct {
+		name          string
+		reservationID string
+		status        int
+		body          string
+		wantErr       string
+	}{
+		{
+			name:          "same project",
+			reservationID: "reservation",
+			status:        http.StatusOK,
+			body:          `{"id": "reservation", "project": {"id": "project"}}`,
+		},
+		{
+			name:          "other project",
+			reservationID: "reservation",
+			status:        http.StatusOK,
+			body:          `{"id": "reservation", "project": {"id": "other"}}`,
+			wantErr:       `belongs to project "other"`,
+		},
+		{
+			name:          "not found",
+			reservationID: "reservation",
+			status:        http.StatusNotFound,
+			body:          `{"errors": ["Not found"]}`,
+			wantErr:       "was not found",
+		},
+		{
+			name:          "next-available",
+			reservationID: "next-available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status == 0 {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{
+				"project_id":              "project",
+				"hardware_reservation_id": tt.reservationID,
+			})
+
+			diags := checkHardwareReservationProject(ctx, meta.NewMetalClientForTesting(), d)
+			if tt.wantErr != "" {
+				if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.wantErr) {
+					t.Fatalf("checkHardwareReservationProject() = %v, want error %q", diags, tt.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("checkHardwareReservationProject() error = %v", diags)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDeviceRead_rootPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		device   string
+		previous string
+		want     string
+	}{
+		{
+			name:   "returned",
+			device: `{"id": "deviceId", "root_password": "fromAPI"}`,
+			want:   "fromAPI",
+		},
+		{
+			name:     "expired",
+			device:   `{"id": "deviceId"}`,
+			previous: "fromCreate",
+			want:     "fromCreate",
+		},
+		{
+			name:   "expired on import",
+			device: `{"id": "deviceId", "ssh_keys": [{"href": "/metal/v1/ssh-keys/keyId"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+					w.Write([]byte(tt.device))
+				case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+					w.Write([]byte(`{"bgp_neighbors": []}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{})
+			d.SetId("deviceId")
+			if tt.previous != "" {
+				d.Set("root_password", tt.previous)
+			}
+
+			if diags := resourceMetalDeviceRead(ctx, d, meta); diags.HasError() {
+				t.Fatalf("resourceMetalDeviceRead() error = %v", diags)
+			}
+			if got := d.Get("root_password"); got != tt.want {
+				t.Errorf("root_password = %q, want %q", got, tt.want)
+			}
+			if strings.Contains(tt.device, "ssh_keys") {
+				if got := d.Get("ssh_key_ids").([]interface{}); len(got) != 1 || got[0] != "keyId" {
+					t.Errorf("ssh_key_ids = %v, want [keyId]", got)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceMetalDeviceRead_fallbackMetro(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+			w.Write([]byte(`{"id": "deviceId", "metro": {"code": "DA"}}`))
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+			w.Write([]byte(`{"bgp_neighbors": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "deployed in a fallback metro",
+			config: map[string]interface{}{"metro": "sv", "metro_fallback": []interface{}{"da"}},
+			want:   "sv",
+		},
+		{
+			name:   "deployed outside of the fallback metros",
+			config: map[string]interface{}{"metro": "sv", "metro_fallback": []interface{}{"ny"}},
+			want:   "DA",
+		},
+		{
+			name: "imported",
+			want: "DA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, tt.config)
+			d.SetId("deviceId")
+
+			if diags := resourceMetalDeviceRead(ctx, d, meta); diags.HasError() {
+				t.Fatalf("resourceMetalDeviceRead() error = %v", diags)
+			}
+			if got := d.Get("metro"); got != tt.want {
+				t.Errorf("metro = %q, want %q", got, tt.want)
+			}
+			if got := d.Get("deployed_metro"); got != "da" {
+				t.Errorf("deployed_metro = %q, want da", got)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDeviceRead_userData(t *testing.T) {
+	ctx := context.Background()
+
+	var userData string
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+			body, _ := json.Marshal(map[string]interface{}{"id": "deviceId", "userdata": userData})
+			w.Write(body)
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+			w.Write([]byte(`{"bgp_neighbors": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	tests := []struct {
+		name     string
+		state    string
+		returned string
+		want     string
+	}{
+		{name: "unchanged", state: "#cloud-config\n", returned: "#cloud-config\n", want: "#cloud-config\n"},
+		{name: "trailing newlines dropped", state: "#!/bin/sh\r\necho hi\r\n", returned: "#!/bin/sh\r\necho hi", want: "#!/bin/sh\r\necho hi\r\n"},
+		{name: "whitespace changed", state: "#!/bin/sh\r\necho hi \n", returned: "#!/bin/sh\necho hi", want: "#!/bin/sh\necho hi"},
+		{name: "changed", state: "#cloud-config\n", returned: "#cloud-config\npackages: [jq]\n", want: "#cloud-config\npackages: [jq]\n"},
+		{name: "imported", returned: "#cloud-config\n", want: "#cloud-config\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userData = tt.returned
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{"user_data": tt.state})
+			d.SetId("deviceId")
+
+			if diags := resourceMetalDeviceRead(ctx, d, meta); diags.HasError() {
+				t.Fatalf("resourceMetalDeviceRead() error = %v", diags)
+			}
+			if got := d.Get("user_data"); got != tt.want {
+				t.Errorf("user_data = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDeviceRead_outOfBandTag(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+			// "external" was added to the device outside of terraform
+			w.Write([]byte(`{"id": "deviceId", "tags": ["external", "web", "prod"]}`))
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+			w.Write([]byte(`{"bgp_neighbors": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{
+		"tags": []interface{}{"prod", "web"},
+	})
+	d.SetId("deviceId")
+
+	if diags := resourceMetalDeviceRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("resourceMetalDeviceRead() error = %v", diags)
+	}
+	if got, want := converters.IfArrToStringArr(d.Get("tags").([]interface{})), []string{"prod", "web"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+}
+
+func TestResourceMetalDeviceRead_dedicatedIPReservationIDs(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+			w.Write([]byte(`{"id": "deviceId", "ip_addresses": [
+				{"address_family": 4, "public": true, "management": true, "parent_block": {"href": "/metal/v1/ips/dedicated"}},
+				{"address_family": 4, "public": true, "management": true, "parent_block": {"href": "/metal/v1/ips/existing"}}
+			]}`))
+		case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+			w.Write([]byte(`{"bgp_neighbors": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	tests := []struct {
+		name     string
+		created  bool
+		recorded []interface{}
+		want     []interface{}
+	}{
+		{name: "created", created: true, want: []interface{}{"dedicated", "existing"}},
+		{name: "refreshed", recorded: []interface{}{"dedicated"}, want: []interface{}{"dedicated"}},
+		{name: "imported", want: []interface{}{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{})
+			d.SetId("deviceId")
+			d.Set("dedicated_ip_reservation_ids", tt.recorded)
+			if tt.created {
+				d.MarkNewResource()
+			}
+
+			if diags := resourceMetalDeviceRead(ctx, d, meta); diags.HasError() {
+				t.Fatalf("resourceMetalDeviceRead() error = %v", diags)
+			}
+			if got := d.Get("dedicated_ip_reservation_ids"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dedicated_ip_reservation_ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDeviceRead_bgpNeighbors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		bgpConfig  bool
+		previous   []interface{}
+		status     int
+		wantCalled bool
+		wantWarn   bool
+		want       int
+	}{
+		{name: "project without BGP", status: http.StatusOK},
+		{name: "project with BGP", bgpConfig: true, status: http.StatusOK, wantCalled: true, want: 1},
+		{name: "no BGP session", bgpConfig: true, status: http.StatusUnprocessableEntity, wantCalled: true},
+		{name: "BGP disabled since the last refresh", previous: []interface{}{map[string]interface{}{"customer_as": 65000}}, status: http.StatusNotFound, wantCalled: true},
+		{name: "listing failed", bgpConfig: true, previous: []interface{}{map[string]interface{}{"customer_as": 65000}}, status: http.StatusBadRequest, wantCalled: true, wantWarn: true, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+					project := `{"id": "projectId"}`
+					if tt.bgpConfig {
+						project = `{"id": "projectId", "bgp_config": {"href": "/metal/v1/projects/projectId/bgp-config"}}`
+					}
+					w.Write([]byte(`{"id": "deviceId", "project": ` + project + `}`))
+				case strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+					called = true
+					w.WriteHeader(tt.status)
+					if tt.status == http.StatusOK {
+						w.Write([]byte(`{"bgp_neighbors": [{"address_family": 4, "customer_as": 65000}]}`))
+					} else {
+						w.Write([]byte(`{"errors": ["no neighbors"]}`))
+					}
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{})
+			d.SetId("deviceId")
+			if tt.previous != nil {
+				d.Set("bgp_neighbors", tt.previous)
+			}
+
+			diags := resourceMetalDeviceRead(ctx, d, meta)
+			if diags.HasError() {
+				t.Fatalf("resourceMetalDeviceRead() error = %v", diags)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("BGP neighbors listed = %v, want %v", called, tt.wantCalled)
+			}
+			if gotWarn := len(diags) > 0; gotWarn != tt.wantWarn {
+				t.Errorf("resourceMetalDeviceRead() diagnostics = %v, want a warning %v", diags, tt.wantWarn)
+			}
+			if got := len(d.Get("bgp_neighbors").([]interface{})); got != tt.want {
+				t.Errorf("bgp_neighbors = %d neighbors, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDevice_fallbackMetroChange(t *testing.T) {
+	// state of a device requested in sv that was deployed in its fallback metro da
+	state := &terraform.InstanceState{
+		ID: "deviceId",
+		Attributes: map[string]string{
+			"id":               "deviceId",
+			"hostname":         "tf-device",
+			"plan":             "c3.small.x86",
+			"metro":            "sv",
+			"metro_fallback.#": "1",
+			"metro_fallback.0": "da",
+			"deployed_metro":   "da",
+			"operating_system": "ubuntu_22_04",
+			"billing_cycle":    "hourly",
+			"project_id":       "projectId",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		metro        string
+		wantReplaced bool
+	}{
+		{name: "requested metro", metro: "sv"},
+		{name: "unrelated metro", metro: "ny", wantReplaced: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"hostname":         "tf-device",
+				"plan":             "c3.small.x86",
+				"metro":            tt.metro,
+				"metro_fallback":   []interface{}{"da"},
+				"operating_system": "ubuntu_22_04",
+				"billing_cycle":    "hourly",
+				"project_id":       "projectId",
+			}
+			diff, err := resourceMetalDevice().Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if got := diff != nil && diff.RequiresNew(); got != tt.wantReplaced {
+				t.Errorf("Diff().RequiresNew() = %v, want %v (diff %v)", got, tt.wantReplaced, diff)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDevice_locationValidation(t *testing.T) {
+	base := map[string]interface{}{
+		"hostname":         "tf-device",
+		"plan":             "c3.small.x86",
+		"operating_system": "ubuntu_20_04",
+		"billing_cycle":    "hourly",
+		"project_id":       "projectId",
+	}
+
+	tests := []struct {
+		name     string
+		location map[string]interface{}
+		wantErr  string
+	}{
+		{
+			name:     "metro",
+			location: map[string]interface{}{"metro": "sv"},
+		},
+		{
+			name:     "facilities",
+			location: map[string]interface{}{"facilities": []interface{}{"sv15"}},
+		},
+		{
+			name:     "both",
+			location: map[string]interface{}{"metro": "sv", "facilities": []interface{}{"sv15"}},
+			wantErr:  "only one of",
+		},
+		{
+			name:    "neither",
+			wantErr: "one of `facilities,metro` must be specified",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{}
+			for k, v := range base {
+				raw[k] = v
+			}
+			for k, v := range tt.location {
+				raw[k] = v
+			}
+
+			diags := resourceMetalDevice().Validate(terraform.NewResourceConfigRaw(raw))
+			if tt.wantErr == "" {
+				if diags.HasError() {
+					t.Fatalf("Validate() = %v, want no error", diags)
+				}
+				return
+			}
+			found := false
+			for _, d := range diags {
+				if strings.Contains(d.Detail, tt.wantErr) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Validate() = %v, want an error containing %q", diags, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublicIPv4SubnetAddresses(t *testing.T) {
+	for size, cidr := range map[int]int32{2: 31, 4: 30, 8: 29, 16: 28} {
+		got := publicIPv4SubnetAddresses(size)
+		if len(got) != 3 {
+			t.Fatalf("publicIPv4SubnetAddresses(%d) returned %d addresses, want 3", size, len(got))
+		}
+		if !got[0].GetPublic() || got[0].GetAddressFamily() != 4 || got[0].GetCidr() != cidr {
+			t.Errorf("publicIPv4SubnetAddresses(%d) public IPv4 = %+v, want /%d", size, got[0], cidr)
+		}
+		if got[1].GetPublic() || got[1].GetAddressFamily() != 4 {
+			t.Errorf("publicIPv4SubnetAddresses(%d) private IPv4 = %+v", size, got[1])
+		}
+		if !got[2].GetPublic() || got[2].GetAddressFamily() != 6 {
+			t.Errorf("publicIPv4SubnetAddresses(%d) public IPv6 = %+v", size, got[2])
+		}
+	}
+}
+
+func TestResourceMetalDevice_behaviorAllowChanges(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "deviceId",
+		Attributes: map[string]string{
+			"id":               "deviceId",
+			"hostname":         "tf-device",
+			"plan":             "c3.small.x86",
+			"metro":            "sv",
+			"operating_system": "ubuntu_20_04",
+			"billing_cycle":    "hourly",
+			"project_id":       "projectId",
+			"user_data":        "#cloud-config\n",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		extra        map[string]interface{}
+		wantReplaced bool
+	}{
+		{
+			name:         "default",
+			wantReplaced: true,
+		},
+		{
+			name:  "allowed",
+			extra: map[string]interface{}{"behavior": []interface{}{map[string]interface{}{"allow_changes": []interface{}{"user_data"}}}},
+		},
+		{
+			name:         "other attribute allowed",
+			extra:        map[string]interface{}{"behavior": []interface{}{map[string]interface{}{"allow_changes": []interface{}{"custom_data"}}}},
+			wantReplaced: true,
+		},
+		{
+			name:  "reinstall",
+			extra: map[string]interface{}{"reinstall": []interface{}{map[string]interface{}{"enabled": true}}},
+		},
+		{
+			name:  "prefer reinstall",
+			extra: map[string]interface{}{"prefer_reinstall_over_recreate": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"hostname":         "tf-device",
+				"plan":             "c3.small.x86",
+				"metro":            "sv",
+				"operating_system": "ubuntu_20_04",
+				"billing_cycle":    "hourly",
+				"project_id":       "projectId",
+				"user_data":        "#cloud-config\npackages: [jq]\n",
+			}
+			for k, v := range tt.extra {
+				raw[k] = v
+			}
+
+			diff, err := resourceMetalDevice().Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if diff.Attributes["user_data"] == nil {
+				t.Fatalf("Diff() = %v, want a user_data change", diff)
+			}
+			if got := diff.RequiresNew(); got != tt.wantReplaced {
+				t.Errorf("Diff().RequiresNew() = %v, want %v", got, tt.wantReplaced)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDevice_sshKeyChange(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "deviceId",
+		Attributes: map[string]string{
+			"id":                    "deviceId",
+			"hostname":              "tf-device",
+			"plan":                  "c3.small.x86",
+			"metro":                 "sv",
+			"operating_system":      "ubuntu_20_04",
+			"billing_cycle":         "hourly",
+			"project_id":            "projectId",
+			"project_ssh_key_ids.#": "1",
+			"project_ssh_key_ids.0": "oldKeyId",
+		},
+	}
+
+	// the API cannot change the SSH keys of a provisioned device
+	diff, err := resourceMetalDevice().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"hostname":            "tf-device",
+		"plan":                "c3.small.x86",
+		"metro":               "sv",
+		"operating_system":    "ubuntu_20_04",
+		"billing_cycle":       "hourly",
+		"project_id":          "projectId",
+		"project_ssh_key_ids": []interface{}{"newKeyId"},
+	}), nil)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if !diff.RequiresNew() {
+		t.Errorf("Diff().RequiresNew() = false, want the device to be recreated (diff %v)", diff)
+	}
+}
+
+func TestResourceMetalDevice_preferReinstallOverRecreate(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "deviceId",
+		Attributes: map[string]string{
+			"id":               "deviceId",
+			"hostname":         "tf-device",
+			"plan":             "c3.small.x86",
+			"metro":            "sv",
+			"operating_system": "ubuntu_20_04",
+			"billing_cycle":    "hourly",
+			"project_id":       "projectId",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		extra        map[string]interface{}
+		wantReplaced bool
+		wantErr      string
+	}{
+		{
+			name:         "default",
+			wantReplaced: true,
+		},
+		{
+			name:  "prefer reinstall",
+			extra: map[string]interface{}{"prefer_reinstall_over_recreate": true},
+		},
+		{
+			name: "prefer reinstall with reinstall options",
+			extra: map[string]interface{}{
+				"prefer_reinstall_over_recreate": true,
+				"reinstall":                      []interface{}{map[string]interface{}{"preserve_data": true}},
+			},
+		},
+		{
+			name: "prefer reinstall with reinstall disabled",
+			extra: map[string]interface{}{
+				"prefer_reinstall_over_recreate": true,
+				"reinstall":                      []interface{}{map[string]interface{}{"enabled": false}},
+			},
+			wantErr: "prefer_reinstall_over_recreate cannot be combined with reinstall.enabled = false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"hostname":         "tf-device",
+				"plan":             "c3.small.x86",
+				"metro":            "sv",
+				"operating_system": "ubuntu_22_04",
+				"billing_cycle":    "hourly",
+				"project_id":       "projectId",
+			}
+			for k, v := range tt.extra {
+				raw[k] = v
+			}
+
+			// Terraform sends the raw config along with the state, the
+			// reinstall.enabled check needs it to tell false from unset
+			r := resourceMetalDevice()
+			rawJSON, err := json.Marshal(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rawConfig, err := ctyjson.Unmarshal(rawJSON, r.CoreConfigSchema().ImpliedType())
+			if err != nil {
+				t.Fatal(err)
+			}
+			state := state.DeepCopy()
+			state.RawConfig = rawConfig
+
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Diff() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if diff.Attributes["operating_system"] == nil {
+				t.Fatalf("Diff() = %v, want an operating_system change", diff)
+			}
+			if got := diff.RequiresNew(); got != tt.wantReplaced {
+				t.Errorf("Diff().RequiresNew() = %v, want %v", got, tt.wantReplaced)
+			}
+		})
+	}
+}
+
+func TestExtendedTerminationTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name     string
+		current  string
+		want     string
+		extended bool
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "earlier",
+			current:  "2024-05-02T12:00:00Z",
+			want:     "2024-05-04T12:00:00Z",
+			extended: true,
+		},
+		{
+			name:    "same time in another time zone",
+			current: "2024-05-04T14:00:00+02:00",
+		},
+		{
+			name:    "later",
+			current: "2024-06-01T00:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, extended := extendedTerminationTime(tt.current, 72*time.Hour, now)
+			if got != tt.want || extended != tt.extended {
+				t.Errorf("extendedTerminationTime(%q) = %q, %v, want %q, %v", tt.current, got, extended, tt.want, tt.extended)
+			}
+		})
+	}
+}
+
+func TestResourceMetalDevice_terminationTimeAutoExtend(t *testing.T) {
+	soon := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	state := &terraform.InstanceState{
+		ID: "deviceId",
+		Attributes: map[string]string{
+			"id":               "deviceId",
+			"hostname":         "tf-device",
+			"plan":             "c3.small.x86",
+			"metro":            "sv",
+			"operating_system": "ubuntu_22_04",
+			"billing_cycle":    "hourly",
+			"project_id":       "projectId",
+			"termination_time": soon,
+		},
+	}
+
+	tests := []struct {
+		name              string
+		autoExtend        map[string]interface{}
+		noTerminationTime bool
+		wantExtended      bool
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:              "enabled without a termination time",
+			autoExtend:        map[string]interface{}{"enabled": true, "duration": "72h"},
+			noTerminationTime: true,
+		},
+		{
+			name:       "disabled",
+			autoExtend: map[string]interface{}{"enabled": false, "duration": "72h"},
+		},
+		{
+			name:         "enabled",
+			autoExtend:   map[string]interface{}{"enabled": true, "duration": "72h"},
+			wantExtended: true,
+		},
+		{
+			name:       "enabled with a shorter duration",
+			autoExtend: map[string]interface{}{"enabled": true, "duration": "1m"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"hostname":         "tf-device",
+				"plan":             "c3.small.x86",
+				"metro":            "sv",
+				"operating_system": "ubuntu_22_04",
+				"billing_cycle":    "hourly",
+				"project_id":       "projectId",
+				"termination_time": soon,
+			}
+			state := state.DeepCopy()
+			if tt.noTerminationTime {
+				delete(raw, "termination_time")
+				delete(state.Attributes, "termination_time")
+			}
+			if tt.autoExtend != nil {
+				raw["termination_time_auto_extend"] = []interface{}{tt.autoExtend}
+			}
+
+			before := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+			diff, err := resourceMetalDevice().Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+
+			var attr *terraform.ResourceAttrDiff
+			if diff != nil {
+				attr = diff.Attributes["termination_time"]
+			}
+			if !tt.wantExtended {
+				if attr != nil {
+					t.Fatalf("Diff() termination_time = %v, want no change", attr)
+				}
+				return
+			}
+			if attr == nil {
+				t.Fatalf("Diff() = %v, want a termination_time change", diff)
+			}
+			extended, err := time.Parse(time.RFC3339, attr.New)
+			if err != nil {
+				t.Fatalf("Diff() termination_time = %q: %v", attr.New, err)
+			}
+			if extended.Before(before) || extended.After(time.Now().Add(72*time.Hour)) {
+				t.Errorf("Diff() termination_time = %s, want 72h from now", attr.New)
+			}
+			if diff.RequiresNew() {
+				t.Errorf("Diff().RequiresNew() = true, want an in-place update")
+			}
+		})
+	}
+}
+
+func TestResourceMetalDevice_terminationTimeRemoved(t *testing.T) {
+	ctx := context.Background()
+	soon := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	var updates []map[string]interface{}
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+			update := map[string]interface{}{}
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				t.Errorf("decoding the update: %v", err)
+			}
+			updates = append(updates, update)
+			w.Write([]byte(`{"id": "deviceId"}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/devices/deviceId"):
+			w.Write([]byte(`{"id": "deviceId"}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/devices/deviceId/bgp/neighbors"):
+			w.Write([]byte(`{"bgp_neighbors": []}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(ctx)
+
+	state := &terraform.InstanceState{
+		ID: "deviceId",
+		Attributes: map[string]string{
+			"id":               "deviceId",
+			"hostname":         "tf-device",
+			"plan":             "c3.small.x86",
+			"metro":            "sv",
+			"operating_system": "ubuntu_22_04",
+			"billing_cycle":    "hourly",
+			"project_id":       "projectId",
+			"termination_time": soon,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		configured  bool
+		wantCleared bool
+	}{
+		{name: "kept", configured: true},
+		{name: "removed", wantCleared: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"hostname":         "tf-device",
+				"plan":             "c3.small.x86",
+				"metro":            "sv",
+				"operating_system": "ubuntu_22_04",
+				"billing_cycle":    "hourly",
+				"project_id":       "projectId",
+			}
+			if tt.configured {
+				raw["termination_time"] = soon
+			}
+
+			// Terraform sends the raw config along with the state, the
+			// removal is only seen in it as termination_time is computed
+			r := resourceMetalDevice()
+			rawJSON, err := json.Marshal(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rawConfig, err := ctyjson.Unmarshal(rawJSON, r.CoreConfigSchema().ImpliedType())
+			if err != nil {
+				t.Fatal(err)
+			}
+			state := state.DeepCopy()
+			state.RawConfig = rawConfig
+
+			diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			var attr *terraform.ResourceAttrDiff
+			if diff != nil {
+				attr = diff.Attributes["termination_time"]
+			}
+			if !tt.wantCleared {
+				if attr != nil {
+					t.Errorf("Diff() termination_time = %v, want no change", attr)
+				}
+				return
+			}
+			if attr == nil || attr.New != "" {
+				t.Fatalf("Diff() termination_time = %v, want it cleared", attr)
+			}
+			if diff.RequiresNew() {
+				t.Errorf("Diff().RequiresNew() = true, want an in-place update")
+			}
+
+			updates = nil
+			if _, diags := r.Apply(ctx, state, diff, meta); diags.HasError() {
+				t.Fatalf("Apply() error = %v", diags)
+			}
+			if len(updates) != 1 {
+				t.Fatalf("Apply() sent %d updates, want 1", len(updates))
+			}
+			if v, ok := updates[0]["termination_time"]; !ok || v != nil {
+				t.Errorf("Apply() update = %v, want termination_time null", updates[0])
+			}
+		})
+	}
+}
+
+func TestCreateDeviceWithMetroFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		errors      map[string]string
+		fallback    []string
+		wantMetros  []string
+		wantCreated string
+		wantErr     bool
+	}{
+		{
+			name:        "primary",
+			fallback:    []string{"sv"},
+			wantMetros:  []string{"da"},
+			wantCreated: "da",
+		},
+		{
+			name:        "fallback",
+			errors:      map[string]string{"da": "Oh snap, we do not have enough capacity for that plan", "sv": "There is no capacity for c3.small.x86 in sv"},
+			fallback:    []string{"sv", "ny"},
+			wantMetros:  []string{"da", "sv", "ny"},
+			wantCreated: "ny",
+		},
+		{
+			name:       "exhausted",
+			errors:     map[string]string{"da": "not enough capacity", "sv": "not enough capacity"},
+			fallback:   []string{"sv"},
+			wantMetros: []string{"da", "sv"},
+			wantErr:    true,
+		},
+		{
+			name:       "other error",
+			errors:     map[string]string{"da": "hostname is invalid"},
+			fallback:   []string{"sv"},
+			wantMetros: []string{"da"},
+			wantErr:    true,
+		},
+		{
+			name:       "operating system not available",
+			errors:     map[string]string{"da": "Operating system is not available for the plan"},
+			fallback:   []string{"sv"},
+			wantMetros: []string{"da"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			var gotMetros []string
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				input := metalv1.DeviceCreateInMetroInput{}
+				if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+					t.Fatal(err)
+				}
+				gotMetros = append(gotMetros, input.Metro)
+
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				if message, ok := tt.errors[input.Metro]; ok {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					w.Write([]byte(`{"errors": ["` + message + `"]}`))
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id": "deviceId", "metro": {"code": "` + input.Metro + `"}}`))
+			}))
+			defer mockAPI.Close()
+
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			createRequest := metalv1.CreateDeviceRequest{
+				DeviceCreateInMetroInput: &metalv1.DeviceCreateInMetroInput{
+					Metro:           "da",
+					Plan:            "c3.small.x86",
+					OperatingSystem: "ubuntu_20_04",
+				},
+			}
+			device, _, err := createDeviceWithMetroFallback(ctx, meta.NewMetalClientForTesting(), "projectId", createRequest, tt.fallback)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("createDeviceWithMetroFallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotMetros, tt.wantMetros) {
+				t.Errorf("createDeviceWithMetroFallback() tried metros %v, want %v", gotMetros, tt.wantMetros)
+			}
+			if err == nil && device.Metro.GetCode() != tt.wantCreated {
+				t.Errorf("createDeviceWithMetroFallback() created in %s, want %s", device.Metro.GetCode(), tt.wantCreated)
+			}
+		})
+	}
+}
+
+func TestCreateDevice_projectNotFound(t *testing.T) {
+	timeout, interval := projectNotFoundTimeout, projectNotFoundInterval
+	projectNotFoundTimeout, projectNotFoundInterval = 50*time.Millisecond, time.Millisecond
+	t.Cleanup(func() { projectNotFoundTimeout, projectNotFoundInterval = timeout, interval })
+
+	tests := []struct {
+		name      string
+		notFound  int
+		status    int
+		wantCalls int
+		wantErr   bool
+	}{
+		{name: "created", wantCalls: 1},
+		{name: "project ready after retries", notFound: 2, wantCalls: 3},
+		{name: "project never found", notFound: -1, wantErr: true},
+		{name: "other error", status: http.StatusUnprocessableEntity, wantCalls: 1, wantErr: true},
+		{name: "plan not found", status: http.StatusNotFound, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			calls := 0
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				switch {
+				case tt.notFound < 0 || calls <= tt.notFound:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors": ["Project not found"]}`))
+				case tt.status == http.StatusNotFound:
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"errors": ["Plan not found"]}`))
+				case tt.status != 0:
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"errors": ["hostname is invalid"]}`))
+				default:
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"id": "deviceId"}`))
+				}
+			}))
+			defer mockAPI.Close()
+
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			createRequest := metalv1.CreateDeviceRequest{
+				DeviceCreateInMetroInput: &metalv1.DeviceCreateInMetroInput{
+					Metro:           "da",
+					Plan:            "c3.small.x86",
+					OperatingSystem: "ubuntu_20_04",
+				},
+			}
+			device, _, err := createDevice(ctx, meta.NewMetalClientForTesting(), "projectId", createRequest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("createDevice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantCalls != 0 && calls != tt.wantCalls {
+				t.Errorf("createDevice() made %d requests, want %d", calls, tt.wantCalls)
+			}
+			if tt.notFound < 0 && calls < 2 {
+				t.Errorf("createDevice() made %d requests, want retries until the timeout", calls)
+			}
+			if err == nil && device.GetId() != "deviceId" {
+				t.Errorf("createDevice() created %s, want deviceId", device.GetId())
+			}
+		})
+	}
+}
+
+func TestHandleProvisioningTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		action      string
+		deleteState int
+		wantDeleted bool
+		wantID      string
+		wantErr     string
+	}{
+		{
+			name:        "delete",
+			action:      provisioningTimeoutDelete,
+			deleteState: http.StatusNoContent,
+			wantDeleted: true,
+			wantErr:     "device deviceId was not active within the create timeout and was deleted",
+		},
+		{
+			name:        "delete failed",
+			action:      provisioningTimeoutDelete,
+			deleteState: http.StatusInternalServerError,
+			wantDeleted: true,
+			wantID:      "deviceId",
+			wantErr:     "could not be deleted, it is kept in state to be destroyed by the next apply",
+		},
+		{
+			name:        "already deleted",
+			action:      provisioningTimeoutDelete,
+			deleteState: http.StatusNotFound,
+			wantDeleted: true,
+			wantErr:     "device deviceId was not active within the create timeout and was deleted",
+		},
+		{
+			name:    "keep",
+			action:  provisioningTimeoutKeep,
+			wantID:  "deviceId",
+			wantErr: "device deviceId was not active within the create timeout and is kept for inspection",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			deleted := false
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || !strings.HasSuffix(r.URL.Path, "/devices/deviceId") {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				deleted = true
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.WriteHeader(tt.deleteState)
+			}))
+			defer mockAPI.Close()
+
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			d := schema.TestResourceDataRaw(t, resourceMetalDevice().Schema, map[string]interface{}{
+				"provisioning_timeout_action": tt.action,
+			})
+			// the waiter removes the device from state when it gives up
+			d.SetId("")
+
+			err := handleProvisioningTimeout(ctx, meta.NewMetalClientForTesting(), d, "deviceId", &retry.TimeoutError{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("handleProvisioningTimeout() error = %v, want an error containing %q", err, tt.wantErr)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("handleProvisioningTimeout() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("handleProvisioningTimeout() id = %q, want %q", d.Id(), tt.wantID)
+			}
+		})
+	}
+}
+
+func TestCustomizeDiffDeployedLocation(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       map[string]interface{}
+		wantMetro    string
+		wantFacility string
+	}{
+		{
+			name:      "metro",
+			config:    map[string]interface{}{"metro": "SV"},
+			wantMetro: "sv",
+		},
+		{
+			name:   "metro with fallback",
+			config: map[string]interface{}{"metro": "sv", "metro_fallback": []interface{}{"da"}},
+		},
+		{
+			name:         "preferred facility",
+			config:       map[string]interface{}{"metro": "sv", "preferred_facility": "sv15"},
+			wantMetro:    "sv",
+			wantFacility: "sv15",
+		},
+		{
+			name:      "preferred facility with fallback",
+			config:    map[string]interface{}{"metro": "sv", "preferred_facility": "sv15", "preferred_facility_fallback": true},
+			wantMetro: "sv",
+		},
+	}
+
+	r := resourceMetalDevice()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["project_id"] = "projectId"
+			tt.config["plan"] = "c3.small.x86"
+			tt.config["operating_system"] = "ubuntu_22_04"
+			tt.config["billing_cycle"] = "hourly"
+			diff, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+
+			for k, want := range map[string]string{
+				"deployed_metro":    tt.wantMetro,
+				"deployed_facility": tt.wantFacility,
+			} {
+				attr := diff.Attributes[k]
+				if want == "" {
+					if attr == nil || !attr.NewComputed {
+						t.Errorf("Diff() %s = %+v, want it to be computed", k, attr)
+					}
+					continue
+				}
+				if attr == nil || attr.NewComputed || attr.New != want {
+					t.Errorf("Diff() %s = %+v, want %q", k, attr, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCustomizeDiffOperatingSystemPlan(t *testing.T) {
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/operating-systems") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.Write([]byte(`{"operating_systems": [
+			{"slug": "ubuntu_22_04", "provisionable_on": ["c3.small.x86", "m3.large.x86"]},
+			{"slug": "custom_ipxe"}
+		]}`))
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(context.Background())
+
+	tests := []struct {
+		name    string
+		os      string
+		plan    string
+		wantErr string
+	}{
+		{
+			name: "provisionable",
+			os:   "ubuntu_22_04",
+			plan: "c3.small.x86",
+		},
+		{
+			name:    "not provisionable",
+			os:      "ubuntu_22_04",
+			plan:    "a3.large.opf",
+			wantErr: `operating_system "ubuntu_22_04" is not provisionable on plan "a3.large.opf"`,
+		},
+		{
+			name: "no provisionable plans listed",
+			os:   "custom_ipxe",
+			plan: "a3.large.opf",
+		},
+		{
+			name: "unknown operating system",
+			os:   "ubuntu_99_04",
+			plan: "c3.small.x86",
+		},
+	}
+
+	r := resourceMetalDevice()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"project_id":       "projectId",
+				"metro":            "sv",
+				"plan":             tt.plan,
+				"operating_system": tt.os,
+				"billing_cycle":    "hourly",
+			}
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(raw), meta)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Diff() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+		})
+	}
+}
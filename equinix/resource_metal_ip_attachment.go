@@ -3,11 +3,13 @@ package equinix
 import (
 	"fmt"
 	"log"
+	"net"
 	"path"
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/mutexkv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/packethost/packngo"
@@ -21,9 +23,34 @@ func resourceMetalIPAttachment() *schema.Resource {
 		Required: true,
 	}
 	ipAttachmentSchema["cidr_notation"] = &schema.Schema{
-		Type:     schema.TypeString,
-		ForceNew: true,
-		Required: true,
+		Type:         schema.TypeString,
+		ForceNew:     true,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"cidr_notation", "reservation_id"},
+	}
+	ipAttachmentSchema["reservation_id"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ForceNew:     true,
+		RequiredWith: []string{"cidr"},
+		Description:  "ID of the reserved IP block to attach the next available subnet of size `cidr` from",
+	}
+	ipAttachmentSchema["cidr"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		Computed:     true,
+		ForceNew:     true,
+		RequiredWith: []string{"reservation_id"},
+		Description:  "Length of CIDR prefix of the subnet as integer",
+	}
+	ipAttachmentSchema["vrf_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		ForceNew:    true,
+		Description: "ID of the VRF the subnet belongs to. When set, `cidr_notation` must be within one of the VRF's `ip_ranges`",
 	}
 	return &schema.Resource{
 		Create: resourceMetalIPAttachmentCreate,
@@ -43,6 +70,39 @@ func resourceMetalIPAttachmentCreate(d *schema.ResourceData, meta interface{}) e
 
 	deviceID := d.Get("device_id").(string)
 	ipa := d.Get("cidr_notation").(string)
+
+	if reservationID, ok := d.GetOk("reservation_id"); ok {
+		// attachments drawing from the same block would otherwise pick the
+		// same subnet, the lock is held until the subnet is assigned
+		lockID := "ip_reservation:" + reservationID.(string)
+		mutexkv.Metal.Lock(lockID)
+		defer mutexkv.Metal.Unlock(lockID)
+
+		cidr := d.Get("cidr").(int)
+		available, _, err := client.ProjectIPs.AvailableAddresses(reservationID.(string), &packngo.AvailableRequest{CIDR: cidr})
+		if err != nil {
+			return fmt.Errorf("error listing available subnets of reserved IP block %s: %s", reservationID, equinix_errors.FriendlyError(err))
+		}
+		if len(available) == 0 {
+			return fmt.Errorf("reserved IP block %s has no available /%d subnet", reservationID, cidr)
+		}
+		ipa = available[0]
+	}
+
+	if vrfID, ok := d.GetOk("vrf_id"); ok {
+		vrf, _, err := client.VRFs.Get(vrfID.(string), nil)
+		if err != nil {
+			return fmt.Errorf("error reading VRF %s: %s", vrfID, equinix_errors.FriendlyError(err))
+		}
+		within, err := cidrWithinRanges(ipa, vrf.IPRanges)
+		if err != nil {
+			return err
+		}
+		if !within {
+			return fmt.Errorf("subnet %s is not within any of the ip_ranges of VRF %s: %v", ipa, vrfID, vrf.IPRanges)
+		}
+	}
+
 	req := packngo.AddressStruct{Address: ipa}
 	assignment, _, err := client.DeviceIPs.Assign(deviceID, &req)
 	if err != nil {
@@ -57,7 +117,8 @@ func resourceMetalIPAttachmentCreate(d *schema.ResourceData, meta interface{}) e
 func resourceMetalIPAttachmentRead(d *schema.ResourceData, meta interface{}) error {
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
-	assignment, _, err := client.DeviceIPs.Get(d.Id(), nil)
+	getOpts := &packngo.GetOptions{Includes: []string{"vrf"}}
+	assignment, _, err := client.DeviceIPs.Get(d.Id(), getOpts)
 	if err != nil {
 		err = equinix_errors.FriendlyError(err)
 
@@ -70,6 +131,10 @@ func resourceMetalIPAttachmentRead(d *schema.ResourceData, meta interface{}) err
 		return err
 	}
 
+	return loadIPAttachment(d, assignment)
+}
+
+func loadIPAttachment(d *schema.ResourceData, assignment *packngo.IPAddressAssignment) error {
 	d.SetId(assignment.ID)
 	d.Set("address", assignment.Address)
 	d.Set("gateway", assignment.Gateway)
@@ -82,6 +147,13 @@ func resourceMetalIPAttachmentRead(d *schema.ResourceData, meta interface{}) err
 	d.Set("manageable", assignment.Manageable)
 
 	d.Set("global", assignment.Global)
+	if assignment.VRF != nil {
+		d.Set("vrf_id", assignment.VRF.ID)
+	}
+
+	if assignment.ParentBlock != nil && assignment.ParentBlock.Href != nil {
+		d.Set("reservation_id", path.Base(*assignment.ParentBlock.Href))
+	}
 
 	d.Set("device_id", path.Base(assignment.AssignedTo.Href))
 	d.Set("cidr_notation",
@@ -90,6 +162,27 @@ func resourceMetalIPAttachmentRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
+// cidrWithinRanges reports whether the subnet given in CIDR notation is fully
+// contained in one of the given CIDR ranges
+func cidrWithinRanges(cidr string, ranges []string) (bool, error) {
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, fmt.Errorf("invalid cidr_notation %s: %s", cidr, err)
+	}
+	subnetOnes, _ := subnet.Mask.Size()
+	for _, r := range ranges {
+		_, ipRange, err := net.ParseCIDR(r)
+		if err != nil {
+			continue
+		}
+		rangeOnes, _ := ipRange.Mask.Size()
+		if ipRange.Contains(subnet.IP) && rangeOnes <= subnetOnes {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func resourceMetalIPAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
@@ -128,6 +128,67 @@ resource "equinix_metal_ip_attachment" "test" {
 }`, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), name, testDeviceTerminationTime())
 }
 
+func TestAccMetalIPAttachment_reservation(t *testing.T) {
+	rs := acctest.RandString(10)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ExternalProviders:        testExternalProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccMetalIPAttachmentCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalIPAttachmentConfig_reservation(rs),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_ip_attachment.test.0", "reservation_id",
+						"equinix_metal_reserved_ip_block.test", "id"),
+					resource.TestCheckResourceAttr(
+						"equinix_metal_ip_attachment.test.0", "cidr", "32"),
+					resource.TestCheckResourceAttrSet(
+						"equinix_metal_ip_attachment.test.0", "address"),
+					resource.TestCheckResourceAttrSet(
+						"equinix_metal_ip_attachment.test.1", "gateway"),
+					resource.TestCheckResourceAttrSet(
+						"equinix_metal_ip_attachment.test.1", "netmask"),
+				),
+			},
+		},
+	})
+}
+
+func testAccMetalIPAttachmentConfig_reservation(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "equinix_metal_project" "test" {
+    name = "tfacc-ip_attachment-%s"
+}
+
+resource "equinix_metal_device" "test" {
+  hostname         = "tfacc-device-ip-attachment-test"
+  plan             = local.plan
+  metro            = local.metro
+  operating_system = local.os
+  billing_cycle    = "hourly"
+  project_id       = equinix_metal_project.test.id
+  termination_time = "%s"
+}
+
+resource "equinix_metal_reserved_ip_block" "test" {
+    project_id = equinix_metal_project.test.id
+    metro      = equinix_metal_device.test.metro
+    quantity   = 2
+}
+
+resource "equinix_metal_ip_attachment" "test" {
+	count          = 2
+	device_id      = equinix_metal_device.test.id
+	reservation_id = equinix_metal_reserved_ip_block.test.id
+	cidr           = 32
+}`, confAccMetalDevice_base(preferable_plans, preferable_metros, preferable_os), name, testDeviceTerminationTime())
+}
+
 func testAccMetalIPAttachmentCheckDestroyed(s *terraform.State) error {
 	client := testAccProvider.Meta().(*config.Config).Metal
 
@@ -0,0 +1,32 @@
+package equinix
+
+import "testing"
+
+func TestCidrWithinRanges(t *testing.T) {
+	ranges := []string{"10.0.0.0/16", "192.168.100.0/24"}
+
+	tests := []struct {
+		cidr    string
+		want    bool
+		wantErr bool
+	}{
+		{cidr: "10.0.4.0/29", want: true},
+		{cidr: "192.168.100.8/30", want: true},
+		{cidr: "192.168.100.0/24", want: true},
+		{cidr: "192.168.0.0/16", want: false},
+		{cidr: "172.16.0.0/29", want: false},
+		{cidr: "not-a-cidr", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cidr, func(t *testing.T) {
+			got, err := cidrWithinRanges(tt.cidr, ranges)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("cidrWithinRanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("cidrWithinRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
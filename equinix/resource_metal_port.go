@@ -2,7 +2,9 @@ package equinix
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"slices"
@@ -40,8 +42,9 @@ func resourceMetalPort() *schema.Resource {
 		UpdateContext: resourceMetalPortUpdate,
 		DeleteContext: resourceMetalPortDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceMetalPortImportState,
 		},
+		CustomizeDiff: customizeDiffMetalPortVlans,
 
 		Schema: map[string]*schema.Schema{
 			"port_id": {
@@ -58,7 +61,8 @@ func resourceMetalPort() *schema.Resource {
 			"layer2": {
 				Type:        schema.TypeBool,
 				Optional:    true,
-				Description: "Flag indicating whether the port is in layer2 (or layer3) mode. The `layer2` flag can be set only for bond ports.",
+				Computed:    true,
+				Description: "Flag indicating whether the port is in layer2 (or layer3) mode. The `layer2` flag can be set only for bond ports. If unset, the mode of the port is left as is.",
 			},
 			"native_vlan_id": {
 				Type:        schema.TypeString,
@@ -125,6 +129,48 @@ func resourceMetalPort() *schema.Resource {
 	}
 }
 
+// resourceMetalPortImportState accepts the UUID of the port, or the ID of its
+// device and the name of the port as <device_id>:<port_name>, e.g. for bond0
+func resourceMetalPortImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	deviceID, portName, ok := strings.Cut(d.Id(), ":")
+	if !ok {
+		return []*schema.ResourceData{d}, nil
+	}
+	if deviceID == "" || portName == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected <port_id> or <device_id>:<port_name>", d.Id())
+	}
+
+	meta.(*config.Config).AddModuleToMetalUserAgent(d)
+	client := meta.(*config.Config).Metal
+
+	device, _, err := client.Devices.Get(deviceID, nil)
+	if err != nil {
+		return nil, equinix_errors.FriendlyError(err)
+	}
+	port, err := device.GetPortByName(portName)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(port.ID)
+	if err := d.Set("port_id", port.ID); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// customizeDiffMetalPortVlans marks the VLAN list that is not configured as
+// unknown when the configured one changes, since both are read back from the
+// VLANs attached to the port
+func customizeDiffMetalPortVlans(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange("vxlan_ids") {
+		return d.SetNewComputed("vlan_ids")
+	}
+	if d.HasChange("vlan_ids") {
+		return d.SetNewComputed("vxlan_ids")
+	}
+	return nil
+}
+
 func resourceMetalPortUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	start := time.Now()
 	cpr, _, err := getClientPortResource(d, meta)
@@ -321,6 +321,13 @@ func testAccMetalPortTemplate(t *testing.T, conf func(string) string, expectedTy
 				ImportStateVerify:       true,
 				ImportStateVerifyIgnore: []string{"reset_on_delete"},
 			},
+			{
+				ResourceName:            "equinix_metal_port.bond0",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccMetalPortImportStateIdByName("equinix_metal_device.test", "bond0"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"reset_on_delete"},
+			},
 			{
 				// Remove equinix_metal_port resources to trigger reset_on_delete
 				Config: confAccMetalPort_base(rs),
@@ -381,6 +388,18 @@ func testAccMetalPortDestroyed(s *terraform.State) error {
 	return nil
 }
 
+// testAccMetalPortImportStateIdByName returns the <device_id>:<port_name>
+// import ID of a port of the device
+func testAccMetalPortImportStateIdByName(deviceName, portName string) resource.ImportStateIdFunc {
+	return func(state *terraform.State) (string, error) {
+		rs, ok := state.RootModule().Resources[deviceName]
+		if !ok {
+			return "", fmt.Errorf("device not found: %s", deviceName)
+		}
+		return rs.Primary.ID + ":" + portName, nil
+	}
+}
+
 func testAccWaitForPortActive(deviceName, portName string) resource.ImportStateIdFunc {
 	return func(state *terraform.State) (string, error) {
 		rs, ok := state.RootModule().Resources[deviceName]
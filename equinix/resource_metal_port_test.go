@@ -0,0 +1,80 @@
+package equinix
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceMetalPortImportState(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "port id",
+			id:   "port-id",
+			want: "port-id",
+		},
+		{
+			name: "device id and port name",
+			id:   "6b5b6c1e-4a2b-4c3d-9e8f-0a1b2c3d4e5f:eth1",
+			want: "eth1-id",
+		},
+		{
+			name:    "unknown port name",
+			id:      "6b5b6c1e-4a2b-4c3d-9e8f-0a1b2c3d4e5f:eth9",
+			wantErr: "eth9",
+		},
+		{
+			name:    "missing port name",
+			id:      "6b5b6c1e-4a2b-4c3d-9e8f-0a1b2c3d4e5f:",
+			wantErr: "invalid import ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/devices/6b5b6c1e-4a2b-4c3d-9e8f-0a1b2c3d4e5f") {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.Write([]byte(`{"id": "6b5b6c1e-4a2b-4c3d-9e8f-0a1b2c3d4e5f", "network_ports": [{"id": "bond0-id", "name": "bond0"}, {"id": "eth1-id", "name": "eth1"}]}`))
+			}))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			d := schema.TestResourceDataRaw(t, resourceMetalPort().Schema, map[string]interface{}{})
+			d.SetId(tt.id)
+
+			got, err := resourceMetalPortImportState(ctx, d, meta)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resourceMetalPortImportState() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resourceMetalPortImportState() error = %v", err)
+			}
+			if len(got) != 1 || got[0].Id() != tt.want {
+				t.Fatalf("resourceMetalPortImportState() = %v, want port %q", got, tt.want)
+			}
+		})
+	}
+}
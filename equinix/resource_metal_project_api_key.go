@@ -74,6 +74,8 @@ func resourceMetalAPIKeyCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	d.SetId(apiKey.ID)
+	// the token is only known for sure in the create response
+	d.Set("token", apiKey.Token)
 
 	return resourceMetalAPIKeyRead(d, meta)
 }
@@ -92,19 +94,7 @@ func resourceMetalAPIKeyRead(d *schema.ResourceData, meta interface{}) error {
 
 	projectId := projectIdFromResourceData(d)
 
-	var apiKey *packngo.APIKey
-	var err error
-
-	// if project has been set in the resource, look up project API key
-	// (this is the reason project API key can't be imported)
-	if projectId != "" {
-		apiKey, err = client.APIKeys.ProjectGet(projectId, d.Id(),
-			&packngo.GetOptions{Includes: []string{"project"}})
-	} else {
-		apiKey, err = client.APIKeys.UserGet(d.Id(),
-			&packngo.GetOptions{Includes: []string{"user"}})
-	}
-
+	apiKey, err := findAPIKey(client, projectId, d.Id())
 	if err != nil {
 		err = equinix_errors.FriendlyError(err)
 		// If the key is somehow already destroyed, mark as
@@ -116,12 +106,22 @@ func resourceMetalAPIKeyRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		return err
 	}
+	if apiKey == nil {
+		log.Printf("[WARN] APIKey (%s) not listed by the API, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 
 	d.SetId(apiKey.ID)
 	attrMap := map[string]interface{}{
 		"description": apiKey.Description,
 		"read_only":   apiKey.ReadOnly,
-		"token":       apiKey.Token,
+	}
+	// the token can not be retrieved reliably after the key is created,
+	// keep the one from state rather than replacing it with what the
+	// listing returns
+	if _, ok := d.GetOk("token"); !ok && apiKey.Token != "" {
+		attrMap["token"] = apiKey.Token
 	}
 
 	// this is kind of unnecessary as the project ID most likely already set,
@@ -137,6 +137,48 @@ func resourceMetalAPIKeyRead(d *schema.ResourceData, meta interface{}) error {
 	return equinix_schema.SetMap(d, attrMap)
 }
 
+// apiKeysPerPage is the page size used to list the API keys of a project or
+// user when looking one up
+const apiKeysPerPage = 100
+
+// findAPIKey looks up an API key of a project or, without a project, of the
+// user. The API has no endpoint to get a key by ID, so the keys are listed
+// page by page and nil is returned only when the key is not in any of them.
+// Failing to list the keys is reported as an error, so that the key is not
+// mistaken for deleted.
+func findAPIKey(client *packngo.Client, projectID, id string) (*packngo.APIKey, error) {
+	if err := packngo.ValidateUUID(id); err != nil {
+		return nil, err
+	}
+	// if project has been set in the resource, look up project API key
+	// (this is the reason project API key can't be imported)
+	opts := &packngo.ListOptions{Includes: []string{"user"}, PerPage: apiKeysPerPage}
+	if projectID != "" {
+		opts.Includes = []string{"project"}
+	}
+	for page := 1; ; page++ {
+		opts.Page = page
+		var keys []packngo.APIKey
+		var err error
+		if projectID != "" {
+			keys, _, err = client.APIKeys.ProjectList(projectID, opts)
+		} else {
+			keys, _, err = client.APIKeys.UserList(opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i := range keys {
+			if keys[i].ID == id {
+				return &keys[i], nil
+			}
+		}
+		if len(keys) < apiKeysPerPage {
+			return nil, nil
+		}
+	}
+}
+
 func resourceMetalAPIKeyDelete(d *schema.ResourceData, meta interface{}) error {
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
@@ -0,0 +1,145 @@
+package equinix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceMetalAPIKeyRead(t *testing.T) {
+	const (
+		projectID = "a1b2c3d4-0000-0000-0000-000000000001"
+		keyID     = "a1b2c3d4-0000-0000-0000-000000000002"
+	)
+
+	// fullPage is a page of other keys, so that the key is looked up on the
+	// next page
+	otherKeys := make([]string, apiKeysPerPage)
+	for i := range otherKeys {
+		otherKeys[i] = fmt.Sprintf(`{"id": "a1b2c3d4-0000-0000-0001-%012d"}`, i)
+	}
+	fullPage := `{"api_keys": [` + strings.Join(otherKeys, ",") + `]}`
+
+	tests := []struct {
+		name      string
+		id        string
+		pages     []string
+		status    int
+		token     string
+		wantErr   bool
+		wantID    string
+		wantToken string
+	}{
+		{
+			name:      "token kept from create",
+			pages:     []string{`{"api_keys": [{"id": "` + keyID + `", "description": "ci", "read_only": true}]}`},
+			token:     "fromCreate",
+			wantID:    keyID,
+			wantToken: "fromCreate",
+		},
+		{
+			name:      "token listed",
+			pages:     []string{`{"api_keys": [{"id": "` + keyID + `", "description": "ci", "token": "listed"}]}`},
+			wantID:    keyID,
+			wantToken: "listed",
+		},
+		{
+			name:      "listed on the second page",
+			pages:     []string{fullPage, `{"api_keys": [{"id": "` + keyID + `", "description": "ci"}]}`},
+			token:     "fromCreate",
+			wantID:    keyID,
+			wantToken: "fromCreate",
+		},
+		{
+			name:  "deleted out of band",
+			pages: []string{fullPage, `{"api_keys": []}`},
+			token: "fromCreate",
+		},
+		{
+			name:   "project deleted",
+			status: http.StatusNotFound,
+			token:  "fromCreate",
+		},
+		{
+			name:      "listing failed",
+			status:    http.StatusBadRequest,
+			token:     "fromCreate",
+			wantErr:   true,
+			wantID:    keyID,
+			wantToken: "fromCreate",
+		},
+		{
+			name:      "invalid key ID",
+			id:        "notAUUID",
+			token:     "fromCreate",
+			wantErr:   true,
+			wantID:    "notAUUID",
+			wantToken: "fromCreate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				if !strings.HasSuffix(r.URL.Path, "/projects/"+projectID+"/api-keys") {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(`{"errors": ["listing failed"]}`))
+					return
+				}
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				if page < 1 || page > len(tt.pages) {
+					t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+					w.Write([]byte(`{"api_keys": []}`))
+					return
+				}
+				w.Write([]byte(tt.pages[page-1]))
+			}))
+			defer mockAPI.Close()
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			d := schema.TestResourceDataRaw(t, resourceMetalProjectAPIKey().Schema, map[string]interface{}{
+				"project_id":  projectID,
+				"description": "ci",
+				"read_only":   true,
+			})
+			id := keyID
+			if tt.id != "" {
+				id = tt.id
+			}
+			d.SetId(id)
+			if tt.token != "" {
+				d.Set("token", tt.token)
+			}
+
+			if err := resourceMetalAPIKeyRead(d, meta); (err != nil) != tt.wantErr {
+				t.Fatalf("resourceMetalAPIKeyRead() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d.Id() != tt.wantID {
+				t.Errorf("resourceMetalAPIKeyRead() id = %q, want %q", d.Id(), tt.wantID)
+			}
+			if tt.wantID != "" && d.Get("token") != tt.wantToken {
+				t.Errorf("resourceMetalAPIKeyRead() token = %q, want %q", d.Get("token"), tt.wantToken)
+			}
+		})
+	}
+}
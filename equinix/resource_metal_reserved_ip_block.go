@@ -3,6 +3,7 @@ package equinix
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"path"
@@ -15,8 +16,10 @@ import (
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
@@ -28,6 +31,14 @@ const (
 	ReservedIPCreateTimeout = 10 * time.Minute
 )
 
+// reservedIPBlockPrefixes are the shortest and longest prefix length of the
+// blocks that can be reserved of each type
+var reservedIPBlockPrefixes = map[string][2]int{
+	"public_ipv4": {24, 32},
+	"global_ipv4": {30, 32},
+	"vrf":         {22, 31},
+}
+
 func metalIPComputedFields() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"address": {
@@ -146,12 +157,11 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		Description: "Arbitrary description",
 	}
 	reservedBlockSchema["quantity"] = &schema.Schema{
-		Type:         schema.TypeInt,
-		Optional:     true,
-		ForceNew:     true,
-		Computed:     true,
-		ExactlyOneOf: []string{"vrf_id", "quantity"},
-		Description:  "The number of allocated /32 addresses, a power of 2",
+		Type:        schema.TypeInt,
+		Optional:    true,
+		ForceNew:    true,
+		Computed:    true,
+		Description: "The number of allocated /32 addresses, a power of 2. For public_ipv4 and global_ipv4 blocks exactly one of quantity and cidr must be set, it can't be set for vrf blocks",
 	}
 	reservedBlockSchema["type"] = &schema.Schema{
 		Type:         schema.TypeString,
@@ -200,11 +210,10 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 	}
 
 	reservedBlockSchema["vrf_id"] = &schema.Schema{
-		Type:         schema.TypeString,
-		Optional:     true,
-		ForceNew:     true,
-		ExactlyOneOf: []string{"vrf_id", "quantity"},
-		Description:  "VRF ID for type=vrf reservations",
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "VRF ID for type=vrf reservations",
 	}
 	reservedBlockSchema["network"] = &schema.Schema{
 		Type:         schema.TypeString,
@@ -215,12 +224,11 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		Description:  "an unreserved network address from an existing vrf ip_range. `network` can only be specified with vrf_id",
 	}
 	reservedBlockSchema["cidr"] = &schema.Schema{
-		Type:         schema.TypeInt,
-		Optional:     true,
-		RequiredWith: []string{"vrf_id"},
-		ForceNew:     true,
-		Computed:     true,
-		Description:  "the size of the network to reserve from an existing vrf ip_range. `cidr` can only be specified with `vrf_id`. Minimum range is 22-29, with 30-31 supported and necessary for virtual-circuits",
+		Type:        schema.TypeInt,
+		Optional:    true,
+		ForceNew:    true,
+		Computed:    true,
+		Description: "The prefix length of the block. Required for vrf blocks, where it is the size of the network to reserve from an existing vrf ip_range: minimum range is 22-29, with 30-31 supported and necessary for virtual-circuits. For public_ipv4 (24-32) and global_ipv4 (30-32) blocks it can be set instead of quantity",
 	}
 	// TODO: add comments field, used for reservations that are not automatically approved
 	return &schema.Resource{
@@ -231,6 +239,10 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffMetalReservedIPBlockLocation,
+			customizeDiffMetalReservedIPBlockSize,
+		),
 
 		Schema: reservedBlockSchema,
 		Timeouts: &schema.ResourceTimeout{
@@ -239,6 +251,92 @@ func resourceMetalReservedIPBlock() *schema.Resource {
 	}
 }
 
+// customizeDiffMetalReservedIPBlockLocation rejects a facility or metro for
+// global blocks, which are not bound to a location, and requires one of them
+// for public blocks. Only new blocks and location changes are checked, the
+// location of existing blocks is read from the API.
+func customizeDiffMetalReservedIPBlockLocation(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("type", "facility", "metro") {
+		return nil
+	}
+	if !d.NewValueKnown("type") || !d.NewValueKnown("facility") || !d.NewValueKnown("metro") {
+		return nil
+	}
+
+	_, facOk := d.GetOk("facility")
+	_, metOk := d.GetOk("metro")
+
+	switch d.Get("type").(string) {
+	case "global_ipv4":
+		if facOk || metOk {
+			return fmt.Errorf("facility and metro can't be set for global IP block reservation, global blocks are not bound to a location")
+		}
+	case "public_ipv4":
+		if !(facOk || metOk) {
+			return fmt.Errorf("you should set either metro or facility for non-global IP block reservation")
+		}
+	}
+	return nil
+}
+
+// reservedIPBlockConfigured reports whether k is set in the configuration.
+// quantity and cidr are also computed, so their planned value does not tell
+// whether they were configured.
+func reservedIPBlockConfigured(d *schema.ResourceDiff, k string) bool {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		_, ok := d.GetOk(k)
+		return ok
+	}
+	return !raw.GetAttr(k).IsNull()
+}
+
+// customizeDiffMetalReservedIPBlockSize checks the combination of type,
+// vrf_id, network, quantity and cidr, which the API would only reject when
+// the block is requested
+func customizeDiffMetalReservedIPBlockSize(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("type", "vrf_id", "network", "quantity", "cidr") {
+		return nil
+	}
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+
+	typ := d.Get("type").(string)
+	quantity := reservedIPBlockConfigured(d, "quantity")
+	cidr := reservedIPBlockConfigured(d, "cidr")
+
+	if typ == "vrf" {
+		if !reservedIPBlockConfigured(d, "vrf_id") || !reservedIPBlockConfigured(d, "network") || !cidr {
+			return fmt.Errorf("vrf_id, network and cidr must be set for type vrf")
+		}
+		if quantity {
+			return fmt.Errorf("quantity can't be set for type vrf, the size of the block is set by cidr")
+		}
+	} else {
+		if reservedIPBlockConfigured(d, "vrf_id") || reservedIPBlockConfigured(d, "network") {
+			return fmt.Errorf("vrf_id and network can only be set for type vrf")
+		}
+		if quantity == cidr {
+			return fmt.Errorf("exactly one of quantity and cidr must be set for type %s", typ)
+		}
+	}
+
+	prefixes := reservedIPBlockPrefixes[typ]
+	if cidr && d.NewValueKnown("cidr") {
+		if c := d.Get("cidr").(int); c < prefixes[0] || c > prefixes[1] {
+			return fmt.Errorf("cidr must be between %d and %d for type %s, got %d", prefixes[0], prefixes[1], typ, c)
+		}
+	}
+	if quantity && d.NewValueKnown("quantity") {
+		minQuantity, maxQuantity := 1<<(32-prefixes[1]), 1<<(32-prefixes[0])
+		if q := d.Get("quantity").(int); q < minQuantity || q > maxQuantity || q&(q-1) != 0 {
+			return fmt.Errorf("quantity must be a power of 2 between %d and %d for type %s, got %d", minQuantity, maxQuantity, typ, q)
+		}
+	}
+	return nil
+}
+
 func resourceMetalReservedIPBlockCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
@@ -290,9 +388,14 @@ func resourceMetalReservedIPBlockCreate(ctx context.Context, d *schema.ResourceD
 		req.CustomData = d.Get("custom_data")
 	}
 
-	req.VRFID = d.Get("vrf_id").(string)
-	req.Network = d.Get("network").(string)
-	req.CIDR = d.Get("cidr").(int)
+	if typ == "vrf" {
+		req.VRFID = d.Get("vrf_id").(string)
+		req.Network = d.Get("network").(string)
+		req.CIDR = d.Get("cidr").(int)
+	} else if cidr := d.Get("cidr").(int); quantity == 0 && cidr > 0 {
+		// public and global blocks are requested by the number of addresses
+		req.Quantity = 1 << (32 - cidr)
+	}
 
 	start := time.Now()
 	blockAddr, _, err := client.ProjectIPs.Create(projectID, &req)
@@ -308,15 +411,25 @@ func resourceMetalReservedIPBlockCreate(ctx context.Context, d *schema.ResourceD
 	if wfs != string(packngo.IPReservationStateCreated) {
 		target = append(target, wfs)
 	}
-	stateConf := &retry.StateChangeConf{
-		Pending:    []string{string(packngo.IPReservationStatePending)},
-		Target:     target,
-		Refresh:    reservedIPStateRefreshFunc(client, d.Id()),
-		Timeout:    d.Timeout(schema.TimeoutCreate) - 30*time.Second - time.Since(start),
-		MinTimeout: 15 * time.Second,
-	}
-	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
-		return diag.Errorf("error waiting for IP Reservation (%s) to become %s: %s", d.Id(), wfs, err)
+	if _, err := wait.ForState(ctx,
+		reservedIPStateRefreshFunc(client, d.Id()),
+		[]string{string(packngo.IPReservationStatePending)},
+		target,
+		wait.Remaining(d.Timeout(schema.TimeoutCreate), start),
+		wait.WithDelay(0),
+		wait.WithMinTimeout(15*time.Second),
+	); err != nil {
+		id := d.Id()
+		if errors.Is(err, errIPReservationDenied) {
+			// A denied request will never become usable, keeping it in the
+			// state would only have it replaced on the next apply
+			resp, rmErr := client.ProjectIPs.Remove(d.Id())
+			if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, rmErr) != nil {
+				log.Printf("[WARN] Could not remove denied IP Reservation (%s): %s", d.Id(), rmErr)
+			}
+			d.SetId("")
+		}
+		return diag.Errorf("error waiting for IP Reservation (%s) to become %s: %s", id, wfs, err)
 	}
 
 	return resourceMetalReservedIPBlockRead(ctx, d, meta)
@@ -344,7 +457,7 @@ func resourceMetalReservedIPBlockUpdate(ctx context.Context, d *schema.ResourceD
 
 	if d.HasChange("custom_data") {
 		var v interface{}
-		if err := json.Unmarshal([]byte(d.Get("custom_data").(string)), v); err != nil {
+		if err := json.Unmarshal([]byte(d.Get("custom_data").(string)), &v); err != nil {
 			return diag.FromErr(fmt.Errorf("error unmarshalling custom_data: %w", err))
 		}
 		req.CustomData = v
@@ -364,10 +477,23 @@ func reservedIPStateRefreshFunc(client *packngo.Client, reservedIPId string) ret
 			return nil, "", fmt.Errorf("error retrieving reserved IP block %s: %s", reservedIPId, err)
 		}
 
-		return reservedIP, string(reservedIP.State), nil
+		return reservedIP, string(reservedIP.State), reservedIPStateError(reservedIP)
 	}
 }
 
+var errIPReservationDenied = errors.New("IP reservation request was denied")
+
+// reservedIPStateError reports a denied reservation request, such as a BYOIP
+// block that was not approved, as an error so that the waiter stops
+func reservedIPStateError(r *packngo.IPAddressReservation) error {
+	if r.State != packngo.IPReservationStateDenied {
+		return nil
+	}
+	// the details of the reservation are the description of the request, not
+	// a reason given by the API
+	return fmt.Errorf("%w (state: %s), contact Equinix Metal support for the reason", errIPReservationDenied, r.State)
+}
+
 func getType(r *packngo.IPAddressReservation) (string, error) {
 	switch {
 	case !r.Public:
@@ -421,14 +547,24 @@ func loadBlock(d *schema.ResourceData, reservedBlock *packngo.IPAddressReservati
 		"netmask":        reservedBlock.Netmask,
 		"address_family": reservedBlock.AddressFamily,
 		"cidr":           reservedBlock.CIDR,
-		"type":           reservedBlock.Type,
-		"tags":           reservedBlock.Tags,
-		"public":         reservedBlock.Public,
-		"management":     reservedBlock.Management,
-		"manageable":     reservedBlock.Manageable,
-		"quantity":       quantity,
-		"project_id":     path.Base(reservedBlock.Project.Href),
-		"cidr_notation":  fmt.Sprintf("%s/%d", reservedBlock.Network, reservedBlock.CIDR),
+		"type": func(d *schema.ResourceData, k string) error {
+			if reservedBlock.Type != "" {
+				return d.Set(k, reservedBlock.Type)
+			}
+			// older reservations do not report their type
+			typ, err := getType(reservedBlock)
+			if err != nil {
+				return err
+			}
+			return d.Set(k, typ)
+		},
+		"tags":          reservedBlock.Tags,
+		"public":        reservedBlock.Public,
+		"management":    reservedBlock.Management,
+		"manageable":    reservedBlock.Manageable,
+		"quantity":      quantity,
+		"project_id":    path.Base(reservedBlock.Project.Href),
+		"cidr_notation": fmt.Sprintf("%s/%d", reservedBlock.Network, reservedBlock.CIDR),
 		"custom_data": func(d *schema.ResourceData, k string) error {
 			if reservedBlock.CustomData == nil {
 				return nil
@@ -8,6 +8,7 @@ import (
 	"text/template"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/packethost/packngo"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -187,6 +188,89 @@ func TestAccMetalReservedIPBlock_metro(t *testing.T) {
 	})
 }
 
+func testAccMetalReservedIPBlockConfig_tags(name, tags, customData string) string {
+	return fmt.Sprintf(`
+resource "equinix_metal_project" "foobar" {
+	name = "tfacc-reserved_ip_block-%s"
+}
+
+resource "equinix_metal_reserved_ip_block" "test" {
+	project_id  = equinix_metal_project.foobar.id
+	metro       = "sv"
+	type        = "public_ipv4"
+	quantity    = 2
+	tags        = %s
+	custom_data = jsonencode(%s)
+}`, name, tags, customData)
+}
+
+func TestAccMetalReservedIPBlock_tagsUpdate(t *testing.T) {
+	rs := acctest.RandString(10)
+	var before, after packngo.IPAddressReservation
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ExternalProviders:        testExternalProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccMetalReservedIPBlockCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalReservedIPBlockConfig_tags(rs, `["Tag1"]`, `{foo = "bar"}`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalReservedIPBlockExists("equinix_metal_reserved_ip_block.test", &before),
+					resource.TestCheckResourceAttr("equinix_metal_reserved_ip_block.test", "tags.#", "1"),
+					resource.TestCheckResourceAttr("equinix_metal_reserved_ip_block.test", "custom_data", `{"foo":"bar"}`),
+				),
+			},
+			{
+				Config: testAccMetalReservedIPBlockConfig_tags(rs, `["Tag2", "Tag3"]`, `{foo = "baz"}`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccMetalReservedIPBlockExists("equinix_metal_reserved_ip_block.test", &after),
+					testAccMetalSameReservedIPBlock(t, &before, &after),
+					resource.TestCheckResourceAttr("equinix_metal_reserved_ip_block.test", "tags.#", "2"),
+					resource.TestCheckTypeSetElemAttr("equinix_metal_reserved_ip_block.test", "tags.*", "Tag2"),
+					resource.TestCheckTypeSetElemAttr("equinix_metal_reserved_ip_block.test", "tags.*", "Tag3"),
+					resource.TestCheckResourceAttr("equinix_metal_reserved_ip_block.test", "custom_data", `{"foo":"baz"}`),
+				),
+			},
+		},
+	})
+}
+
+func testAccMetalReservedIPBlockExists(n string, block *packngo.IPAddressReservation) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Record ID is set")
+		}
+
+		client := testAccProvider.Meta().(*config.Config).Metal
+		found, _, err := client.ProjectIPs.Get(rs.Primary.ID, nil)
+		if err != nil {
+			return err
+		}
+		if found.ID != rs.Primary.ID {
+			return fmt.Errorf("Record not found: %v - %v", rs.Primary.ID, found)
+		}
+
+		*block = *found
+
+		return nil
+	}
+}
+
+func testAccMetalSameReservedIPBlock(t *testing.T, before, after *packngo.IPAddressReservation) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if before.ID != after.ID {
+			t.Fatalf("Expected reserved IP block to be the same, but it was recreated: %s -> %s", before.ID, after.ID)
+		}
+		return nil
+	}
+}
+
 func TestAccMetalReservedIPBlock_importBasic(t *testing.T) {
 	rs := acctest.RandString(10)
 
@@ -0,0 +1,263 @@
+package equinix
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/packethost/packngo"
+)
+
+func TestReservedIPStateError(t *testing.T) {
+	details := "BYOIP 192.0.2.0/24, LOA attached"
+
+	tests := []struct {
+		name        string
+		reservation *packngo.IPAddressReservation
+		wantErr     bool
+	}{
+		{
+			name:        "pending",
+			reservation: &packngo.IPAddressReservation{State: packngo.IPReservationStatePending},
+		},
+		{
+			name:        "created",
+			reservation: &packngo.IPAddressReservation{State: packngo.IPReservationStateCreated},
+		},
+		{
+			name:        "denied",
+			reservation: &packngo.IPAddressReservation{State: packngo.IPReservationStateDenied},
+			wantErr:     true,
+		},
+		{
+			name:        "denied with details",
+			reservation: &packngo.IPAddressReservation{State: packngo.IPReservationStateDenied, Description: &details},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := reservedIPStateError(tt.reservation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reservedIPStateError() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, errIPReservationDenied) {
+				t.Errorf("reservedIPStateError() = %v, want it to wrap errIPReservationDenied", err)
+			}
+			// the details are the description of the request, not the reason
+			// of the denial
+			if strings.Contains(err.Error(), details) {
+				t.Errorf("reservedIPStateError() = %v, want the details of the request left out", err)
+			}
+			if !strings.Contains(err.Error(), string(packngo.IPReservationStateDenied)) {
+				t.Errorf("reservedIPStateError() = %v, want the state of the reservation", err)
+			}
+		})
+	}
+}
+
+func TestCustomizeDiffMetalReservedIPBlockLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "global without location",
+			config: map[string]interface{}{"type": "global_ipv4"},
+		},
+		{
+			name:    "global with metro",
+			config:  map[string]interface{}{"type": "global_ipv4", "metro": "sv"},
+			wantErr: "facility and metro can't be set for global IP block reservation",
+		},
+		{
+			name:    "global with facility",
+			config:  map[string]interface{}{"type": "global_ipv4", "facility": "sv15"},
+			wantErr: "facility and metro can't be set for global IP block reservation",
+		},
+		{
+			name:   "public with metro",
+			config: map[string]interface{}{"type": "public_ipv4", "metro": "sv"},
+		},
+		{
+			name:    "public without location",
+			config:  map[string]interface{}{"type": "public_ipv4"},
+			wantErr: "you should set either metro or facility",
+		},
+	}
+
+	r := resourceMetalReservedIPBlock()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["project_id"] = "projectId"
+			tt.config["quantity"] = 1
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Diff() error = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Diff() error = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadBlock_global(t *testing.T) {
+	block := &packngo.IPAddressReservation{
+		IpAddressCommon: packngo.IpAddressCommon{
+			ID:            "blockId",
+			Address:       "192.0.2.1",
+			Network:       "192.0.2.1",
+			AddressFamily: 4,
+			CIDR:          32,
+			Public:        true,
+			Global:        true,
+			Project:       packngo.Href{Href: "/metal/v1/projects/projectId"},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceMetalReservedIPBlock().Schema, map[string]interface{}{})
+	if err := loadBlock(d, block); err != nil {
+		t.Fatalf("loadBlock() error = %v", err)
+	}
+
+	for k, want := range map[string]interface{}{
+		"type":       "global_ipv4",
+		"global":     true,
+		"metro":      "",
+		"facility":   "",
+		"quantity":   1,
+		"project_id": "projectId",
+	} {
+		if got := d.Get(k); got != want {
+			t.Errorf("loadBlock() %s = %v, want %v", k, got, want)
+		}
+	}
+}
+
+func TestCustomizeDiffMetalReservedIPBlockSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "public quantity",
+			config: map[string]interface{}{"type": "public_ipv4", "metro": "sv", "quantity": 4},
+		},
+		{
+			name:   "public cidr",
+			config: map[string]interface{}{"type": "public_ipv4", "metro": "sv", "cidr": 29},
+		},
+		{
+			name:    "public quantity and cidr",
+			config:  map[string]interface{}{"type": "public_ipv4", "metro": "sv", "quantity": 4, "cidr": 30},
+			wantErr: "exactly one of quantity and cidr must be set for type public_ipv4",
+		},
+		{
+			name:    "public without size",
+			config:  map[string]interface{}{"type": "public_ipv4", "metro": "sv"},
+			wantErr: "exactly one of quantity and cidr must be set for type public_ipv4",
+		},
+		{
+			name:    "public quantity not a power of 2",
+			config:  map[string]interface{}{"type": "public_ipv4", "metro": "sv", "quantity": 3},
+			wantErr: "quantity must be a power of 2 between 1 and 256",
+		},
+		{
+			name:    "public with vrf_id",
+			config:  map[string]interface{}{"type": "public_ipv4", "metro": "sv", "quantity": 4, "vrf_id": "vrfId"},
+			wantErr: "vrf_id and network can only be set for type vrf",
+		},
+		{
+			name:   "global cidr",
+			config: map[string]interface{}{"type": "global_ipv4", "cidr": 31},
+		},
+		{
+			name:    "global cidr too short",
+			config:  map[string]interface{}{"type": "global_ipv4", "cidr": 29},
+			wantErr: "cidr must be between 30 and 32 for type global_ipv4",
+		},
+		{
+			name:    "global quantity too large",
+			config:  map[string]interface{}{"type": "global_ipv4", "quantity": 8},
+			wantErr: "quantity must be a power of 2 between 1 and 4",
+		},
+		{
+			name:   "vrf",
+			config: map[string]interface{}{"type": "vrf", "vrf_id": "vrfId", "network": "192.168.0.0", "cidr": 29},
+		},
+		{
+			name:    "vrf without cidr",
+			config:  map[string]interface{}{"type": "vrf", "vrf_id": "vrfId", "network": "192.168.0.0"},
+			wantErr: "vrf_id, network and cidr must be set for type vrf",
+		},
+		{
+			name:    "vrf with quantity",
+			config:  map[string]interface{}{"type": "vrf", "vrf_id": "vrfId", "network": "192.168.0.0", "cidr": 29, "quantity": 8},
+			wantErr: "quantity can't be set for type vrf",
+		},
+		{
+			name:    "vrf cidr too long",
+			config:  map[string]interface{}{"type": "vrf", "vrf_id": "vrfId", "network": "192.168.0.0", "cidr": 32},
+			wantErr: "cidr must be between 22 and 31 for type vrf",
+		},
+	}
+
+	r := resourceMetalReservedIPBlock()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["project_id"] = "projectId"
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Diff() error = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Diff() error = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadBlock_dataSource(t *testing.T) {
+	block := &packngo.IPAddressReservation{
+		IpAddressCommon: packngo.IpAddressCommon{
+			ID:            "block-id",
+			Network:       "192.0.2.0",
+			AddressFamily: 4,
+			CIDR:          30,
+			Public:        true,
+			Project:       packngo.Href{Href: "/metal/v1/projects/project-id"},
+			Tags:          []string{"ipam", "prod"},
+			CustomData:    map[string]interface{}{"owner": "network"},
+			Type:          packngo.PublicIPv4,
+		},
+	}
+
+	d := dataSourceMetalReservedIPBlock().TestResourceData()
+	if err := loadBlock(d, block); err != nil {
+		t.Fatalf("loadBlock() error = %v", err)
+	}
+
+	tags := d.Get("tags").(*schema.Set)
+	if tags.Len() != 2 || !tags.Contains("ipam") || !tags.Contains("prod") {
+		t.Errorf("tags = %v, want [ipam prod]", tags.List())
+	}
+	if got, want := d.Get("custom_data").(string), `{"owner":"network"}`; got != want {
+		t.Errorf("custom_data = %q, want %q", got, want)
+	}
+}
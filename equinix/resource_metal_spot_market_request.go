@@ -2,11 +2,13 @@ package equinix
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
@@ -15,6 +17,7 @@ import (
 	equinix_schema "github.com/equinix/terraform-provider-equinix/internal/schema"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
@@ -26,9 +29,10 @@ func resourceMetalSpotMarketRequest() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceMetalSpotMarketRequestCreate,
 		ReadContext:   resourceMetalSpotMarketRequestRead,
+		UpdateContext: resourceMetalSpotMarketRequestUpdate,
 		DeleteContext: resourceMetalSpotMarketRequestDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceMetalSpotMarketRequestImportState,
 		},
 		Schema: map[string]*schema.Schema{
 			"devices_min": {
@@ -143,6 +147,12 @@ func resourceMetalSpotMarketRequest() *schema.Resource {
 							Type:     schema.TypeString,
 							Required: true,
 						},
+						"deploy_hostname_from_index": {
+							Type:        schema.TypeBool,
+							Description: "Whether each device gets its own hostname from the hostname template, with `{{index}}` replaced by the index of the device. Without `{{index}}`, `-<index>` is appended to the hostname",
+							Optional:    true,
+							Default:     false,
+						},
 						"termintation_time": {
 							Type:       schema.TypeString,
 							Computed:   true,
@@ -206,11 +216,17 @@ func resourceMetalSpotMarketRequest() *schema.Resource {
 				Required:    true,
 				ForceNew:    true,
 			},
+			"device_hostnames": {
+				Type:        schema.TypeMap,
+				Description: "Hostnames of the devices provisioned from this request, by device ID",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
 			"wait_for_devices": {
 				Type:        schema.TypeBool,
-				Description: "On resource creation - wait until all desired devices are active, on resource destruction - wait until devices are removed",
+				Description: "On resource creation - wait until devices_min devices are active, on resource destruction - wait until devices are removed",
 				Optional:    true,
-				ForceNew:    true,
+				Default:     true,
 			},
 		},
 		Timeouts: &schema.ResourceTimeout{
@@ -241,6 +257,12 @@ func resourceMetalSpotMarketRequestCreate(ctx context.Context, d *schema.Resourc
 		OperatingSystem: d.Get("instance_parameters.0.operating_system").(string),
 	}
 
+	if d.Get("instance_parameters.0.deploy_hostname_from_index").(bool) {
+		// the API gives the devices the hostnames in order, one each
+		params.Hostnames = spotMarketRequestHostnames(params.Hostname, d.Get("devices_max").(int))
+		params.Hostname = ""
+	}
+
 	if val, ok := d.GetOk("instance_parameters.0.userdata"); ok {
 		params.UserData = val.(string)
 	}
@@ -344,17 +366,22 @@ func resourceMetalSpotMarketRequestCreate(ctx context.Context, d *schema.Resourc
 	d.SetId(smr.ID)
 
 	if waitForDevices {
-		stateConf := &retry.StateChangeConf{
-			Pending:        []string{"not_done"},
-			Target:         []string{"done"},
-			Refresh:        resourceStateRefreshFunc(d, meta),
-			Timeout:        d.Timeout(schema.TimeoutCreate) - time.Since(start) - time.Second*10, // reduce 30s to avoid context deadline
-			MinTimeout:     5 * time.Second,
-			Delay:          3 * time.Second, // Wait 10 secs before starting
-			NotFoundChecks: 600,             // Setting high number, to support long timeouts
+		devicesMin := smrc.DevicesMin
+		// the create keeps a shorter margin than the other waiters
+		timeout := wait.RemainingWithMargin(d.Timeout(schema.TimeoutCreate), start, 10*time.Second)
+		_, err = wait.ForState(ctx,
+			resourceStateRefreshFunc(d, meta, func(active, _ int) bool { return active >= devicesMin }),
+			[]string{"not_done"},
+			[]string{"done"},
+			timeout,
+			spotMarketRequestWaitOpts...,
+		)
+		var timeoutErr *retry.TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return diag.Errorf("fewer than devices_min (%d) devices of spot market request %s became active within %s, "+
+				"the request may not be fulfilled at max_bid_price in the requested location; "+
+				"set wait_for_devices to false to not wait for the devices", devicesMin, d.Id(), timeout)
 		}
-
-		_, err = stateConf.WaitForStateContext(ctx)
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -363,6 +390,21 @@ func resourceMetalSpotMarketRequestCreate(ctx context.Context, d *schema.Resourc
 	return resourceMetalSpotMarketRequestRead(ctx, d, meta)
 }
 
+// resourceMetalSpotMarketRequestUpdate only records wait_for_devices, every
+// other argument recreates the request
+func resourceMetalSpotMarketRequestUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceMetalSpotMarketRequestRead(ctx, d, meta)
+}
+
+// resourceMetalSpotMarketRequestImportState sets wait_for_devices to its
+// default, it has no API representation
+func resourceMetalSpotMarketRequestImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := d.Set("wait_for_devices", true); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	meta.(*config.Config).AddModuleToMetalUserAgent(d)
 	client := meta.(*config.Config).Metal
@@ -389,6 +431,15 @@ func resourceMetalSpotMarketRequestRead(ctx context.Context, d *schema.ResourceD
 		"devices_min":   smr.DevicesMin,
 		"devices_max":   smr.DevicesMax,
 		"max_bid_price": smr.MaxBidPrice,
+		"device_hostnames": func(d *schema.ResourceData, k string) error {
+			hostnames := map[string]string{}
+			for _, device := range smr.Devices {
+				if device.Hostname != "" {
+					hostnames[device.ID] = device.Hostname
+				}
+			}
+			return d.Set(k, hostnames)
+		},
 		"facilities": func(d *schema.ResourceData, k string) error {
 			facilityIDs := make([]string, len(smr.Facilities))
 			facilityCodes := make([]string, len(smr.Facilities))
@@ -414,26 +465,13 @@ func resourceMetalSpotMarketRequestDelete(ctx context.Context, d *schema.Resourc
 		waitForDevices = val.(bool)
 	}
 	if waitForDevices {
-		smr, _, err := client.SpotMarketRequests.Get(d.Id(), &packngo.GetOptions{Includes: []string{"project", "devices", "facilities", "metro"}})
+		smr, resp, err := client.SpotMarketRequests.Get(d.Id(), &packngo.GetOptions{Includes: []string{"devices"}})
 		if err != nil {
-			return nil
-		}
-
-		stateConf := &retry.StateChangeConf{
-			Pending:        []string{"not_done"},
-			Target:         []string{"done"},
-			Refresh:        resourceStateRefreshFunc(d, meta),
-			Timeout:        d.Timeout(schema.TimeoutDelete) - 30*time.Second,
-			MinTimeout:     5 * time.Second,
-			Delay:          3 * time.Second, // Wait 10 secs before starting
-			NotFoundChecks: 600,             // Setting high number, to support long timeouts
-		}
-
-		_, err = stateConf.WaitForStateContext(ctx)
-		if err != nil {
-			return diag.FromErr(err)
+			return diag.FromErr(equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err))
 		}
 
+		// The devices are deleted whatever their state, waiting for them to
+		// become active would block the destroy on a failed device
 		for _, d := range smr.Devices {
 			resp, err := client.Devices.Delete(d.ID, true)
 			if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err) != nil {
@@ -445,7 +483,35 @@ func resourceMetalSpotMarketRequestDelete(ctx context.Context, d *schema.Resourc
 	return diag.FromErr(equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(resp, err))
 }
 
-func resourceStateRefreshFunc(d *schema.ResourceData, meta interface{}) retry.StateRefreshFunc {
+// spotMarketRequestHostnameIndex is replaced by the index of the device in
+// the hostnames of deploy_hostname_from_index
+const spotMarketRequestHostnameIndex = "{{index}}"
+
+// spotMarketRequestHostnames returns a hostname for each of count devices built
+// from the hostname template
+func spotMarketRequestHostnames(template string, count int) []string {
+	if !strings.Contains(template, spotMarketRequestHostnameIndex) {
+		template += "-" + spotMarketRequestHostnameIndex
+	}
+	hostnames := make([]string, count)
+	for i := range hostnames {
+		hostnames[i] = strings.ReplaceAll(template, spotMarketRequestHostnameIndex, strconv.Itoa(i))
+	}
+	return hostnames
+}
+
+// spotMarketRequestWaitOpts tune the waiter for the devices of a spot market
+// request, which are polled more often than the default and may not be listed
+// for a long time while the request is being fulfilled
+var spotMarketRequestWaitOpts = []wait.Option{
+	wait.WithDelay(3 * time.Second),
+	wait.WithMinTimeout(5 * time.Second),
+	wait.WithNotFoundChecks(600),
+}
+
+// resourceStateRefreshFunc reports the spot market request as done once done
+// returns true for the number of active devices out of the devices of the request
+func resourceStateRefreshFunc(d *schema.ResourceData, meta interface{}, done func(active, total int) bool) retry.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 		meta.(*config.Config).AddModuleToMetalUserAgent(d)
 		client := meta.(*config.Config).Metal
@@ -454,23 +520,20 @@ func resourceStateRefreshFunc(d *schema.ResourceData, meta interface{}) retry.St
 		if err != nil {
 			return nil, "", fmt.Errorf("Failed to fetch Spot market request with following error: %s", err.Error())
 		}
-		var finished bool
-
+		active := 0
 		for _, d := range smr.Devices {
-
 			dev, _, err := client.Devices.Get(d.ID, nil)
 			if err != nil {
 				return nil, "", fmt.Errorf("Failed to fetch Device with following error: %s", err.Error())
 			}
-			if dev.State != "active" {
-				break
-			} else {
-				finished = true
+			if dev.State == "active" {
+				active++
 			}
 		}
-		if finished {
+		log.Printf("[DEBUG] %d of %d devices of spot market request (%s) are active", active, len(smr.Devices), smr.ID)
+		if done(active, len(smr.Devices)) {
 			return smr, "done", nil
 		}
-		return nil, "not_done", nil
+		return smr, "not_done", nil
 	}
 }
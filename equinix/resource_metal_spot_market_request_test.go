@@ -0,0 +1,207 @@
+package equinix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/packethost/packngo"
+)
+
+func TestResourceStateRefreshFunc_devicesMin(t *testing.T) {
+	const (
+		requestID      = "a1b2c3d4-0000-0000-0000-000000000001"
+		activeID       = "a1b2c3d4-0000-0000-0000-000000000002"
+		provisioningID = "a1b2c3d4-0000-0000-0000-000000000003"
+	)
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.WriteHeader(http.StatusOK)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/spot-market-requests/"+requestID):
+			w.Write([]byte(`{"id": "` + requestID + `", "devices": [{"id": "` + activeID + `"}, {"id": "` + provisioningID + `"}]}`))
+		case strings.HasSuffix(r.URL.Path, "/devices/"+activeID):
+			w.Write([]byte(`{"id": "` + activeID + `", "state": "active"}`))
+		case strings.HasSuffix(r.URL.Path, "/devices/"+provisioningID):
+			w.Write([]byte(`{"id": "` + provisioningID + `", "state": "provisioning"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockAPI.Close()
+
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(context.Background())
+
+	d := schema.TestResourceDataRaw(t, resourceMetalSpotMarketRequest().Schema, map[string]interface{}{})
+	d.SetId(requestID)
+
+	tests := []struct {
+		name       string
+		devicesMin int
+		want       string
+	}{
+		{name: "reached", devicesMin: 1, want: "done"},
+		{name: "not reached", devicesMin: 2, want: "not_done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refresh := resourceStateRefreshFunc(d, meta, func(active, _ int) bool { return active >= tt.devicesMin })
+			result, state, err := refresh()
+			if err != nil {
+				t.Fatalf("refresh() error = %v", err)
+			}
+			if state != tt.want {
+				t.Errorf("refresh() state = %s, want %s", state, tt.want)
+			}
+			// a nil result would count against the not found checks
+			if result == nil {
+				t.Error("refresh() result is nil")
+			}
+		})
+	}
+}
+
+func TestSpotMarketRequestHostnames(t *testing.T) {
+	tests := []struct {
+		template string
+		want     []string
+	}{
+		{template: "worker-{{index}}.example.com", want: []string{"worker-0.example.com", "worker-1.example.com"}},
+		{template: "worker", want: []string{"worker-0", "worker-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got := spotMarketRequestHostnames(tt.template, 2)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("spotMarketRequestHostnames(%q) = %v, want %v", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceMetalSpotMarketRequest_deployHostnameFromIndex(t *testing.T) {
+	const (
+		projectID = "a1b2c3d4-0000-0000-0000-000000000000"
+		requestID = "a1b2c3d4-0000-0000-0000-000000000001"
+		deviceID  = "a1b2c3d4-0000-0000-0000-000000000002"
+	)
+
+	var created packngo.SpotMarketRequestCreateRequest
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/projects/"+projectID+"/spot-market-requests"):
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("cannot decode spot market request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "` + requestID + `"}`))
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/spot-market-requests/"+requestID):
+			// devices that are not provisioned yet have no hostname
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "` + requestID + `", "project": {"id": "` + projectID + `"}, "devices": [
+				{"id": "` + deviceID + `", "hostname": "worker-0"},
+				{"id": "a1b2c3d4-0000-0000-0000-000000000003"}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer mockAPI.Close()
+
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(context.Background())
+
+	resource := resourceMetalSpotMarketRequest()
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"project_id":       projectID,
+		"devices_min":      1,
+		"devices_max":      3,
+		"max_bid_price":    0.5,
+		"metro":            "sv",
+		"wait_for_devices": false,
+		"instance_parameters": []interface{}{map[string]interface{}{
+			"billing_cycle":              "hourly",
+			"plan":                       "c3.small.x86",
+			"operating_system":           "ubuntu_22_04",
+			"hostname":                   "worker-{{index}}",
+			"deploy_hostname_from_index": true,
+		}},
+	})
+
+	if diags := resource.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("Create() error = %v", diags)
+	}
+
+	if got, want := strings.Join(created.Parameters.Hostnames, ","), "worker-0,worker-1,worker-2"; got != want {
+		t.Errorf("Create() hostnames = %s, want %s", got, want)
+	}
+	if created.Parameters.Hostname != "" {
+		t.Errorf("Create() hostname = %q, want it left to the hostnames", created.Parameters.Hostname)
+	}
+	if got := d.Get("device_hostnames").(map[string]interface{}); len(got) != 1 || got[deviceID] != "worker-0" {
+		t.Errorf("Create() device_hostnames = %v, want only %s = worker-0", got, deviceID)
+	}
+}
+
+func TestResourceMetalSpotMarketRequestDelete_devicesNotActive(t *testing.T) {
+	const (
+		requestID      = "a1b2c3d4-0000-0000-0000-000000000001"
+		failedID       = "a1b2c3d4-0000-0000-0000-000000000002"
+		provisioningID = "a1b2c3d4-0000-0000-0000-000000000003"
+	)
+
+	var deleted []string
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		switch {
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/spot-market-requests/"+requestID):
+			w.Write([]byte(`{"id": "` + requestID + `", "devices": [{"id": "` + failedID + `"}, {"id": "` + provisioningID + `"}]}`))
+		default:
+			// the devices are never active, their state must not be polled
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockAPI.Close()
+
+	meta := &config.Config{
+		BaseURL: mockAPI.URL,
+		Token:   "fakeTokenForMock",
+	}
+	meta.Load(context.Background())
+
+	d := schema.TestResourceDataRaw(t, resourceMetalSpotMarketRequest().Schema, map[string]interface{}{
+		"wait_for_devices": true,
+	})
+	d.SetId(requestID)
+
+	if diags := resourceMetalSpotMarketRequestDelete(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("resourceMetalSpotMarketRequestDelete() error = %v", diags)
+	}
+	if want := []string{failedID, provisioningID, requestID}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted = %v, want %v", deleted, want)
+	}
+}
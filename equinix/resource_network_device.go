@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/equinix/ne-go"
 	"github.com/equinix/rest-go"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -44,6 +46,7 @@ var neDeviceSchemaNames = map[string]string{
 	"LicenseStatus":         "license_status",
 	"ACLTemplateUUID":       "acl_template_id",
 	"MgmtAclTemplateUuid":   "mgmt_acl_template_uuid",
+	"ACLTemplateStatus":     "acl_template_status",
 	"SSHIPAddress":          "ssh_ip_address",
 	"SSHIPFqdn":             "ssh_ip_fqdn",
 	"AccountNumber":         "account_number",
@@ -93,6 +96,7 @@ var neDeviceDescriptions = map[string]string{
 	"LicenseStatus":         "Device license registration status",
 	"ACLTemplateUUID":       "Unique identifier of applied ACL template",
 	"MgmtAclTemplateUuid":   "Unique identifier of applied MGMT ACL template",
+	"ACLTemplateStatus":     "Provisioning status of the ACL templates applied to the device, e.g. PROVISIONING or PROVISIONED",
 	"SSHIPAddress":          "IP address of SSH enabled interface on the device",
 	"SSHIPFqdn":             "FQDN of SSH enabled interface on the device",
 	"AccountNumber":         "Device billing account number",
@@ -221,7 +225,7 @@ func resourceNetworkDevice() *schema.Resource {
 		UpdateContext: resourceNetworkDeviceUpdate,
 		DeleteContext: resourceNetworkDeviceDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceNetworkDeviceImportState,
 		},
 		Schema: createNetworkDeviceSchema(),
 		Timeouts: &schema.ResourceTimeout{
@@ -372,6 +376,11 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 			ValidateFunc: validation.StringIsNotEmpty,
 			Description:  neDeviceDescriptions["MgmtAclTemplateUuid"],
 		},
+		neDeviceSchemaNames["ACLTemplateStatus"]: {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: neDeviceDescriptions["ACLTemplateStatus"],
+		},
 		neDeviceSchemaNames["SSHIPAddress"]: {
 			Type:        schema.TypeString,
 			Computed:    true,
@@ -444,10 +453,11 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 			Description: neDeviceDescriptions["DiverseFromDeviceName"],
 		},
 		neDeviceSchemaNames["AdditionalBandwidth"]: {
-			Type:        schema.TypeInt,
-			Optional:    true,
-			Computed:    true,
-			Description: neDeviceDescriptions["AdditionalBandwidth"],
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  neDeviceDescriptions["AdditionalBandwidth"],
 		},
 		neDeviceSchemaNames["OrderReference"]: {
 			Type:         schema.TypeString,
@@ -632,6 +642,11 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 						ValidateFunc: validation.StringIsNotEmpty,
 						Description:  neDeviceDescriptions["MgmtAclTemplateUuid"],
 					},
+					neDeviceSchemaNames["ACLTemplateStatus"]: {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: neDeviceDescriptions["ACLTemplateStatus"],
+					},
 					neDeviceSchemaNames["SSHIPAddress"]: {
 						Type:        schema.TypeString,
 						Computed:    true,
@@ -670,10 +685,11 @@ func createNetworkDeviceSchema() map[string]*schema.Schema {
 						Description: neDeviceDescriptions["RedundantUUID"],
 					},
 					neDeviceSchemaNames["AdditionalBandwidth"]: {
-						Type:        schema.TypeInt,
-						Optional:    true,
-						Computed:    true,
-						Description: neDeviceDescriptions["AdditionalBandwidth"],
+						Type:         schema.TypeInt,
+						Optional:     true,
+						Computed:     true,
+						ValidateFunc: validation.IntAtLeast(0),
+						Description:  neDeviceDescriptions["AdditionalBandwidth"],
 					},
 					neDeviceSchemaNames["WanInterfaceId"]: {
 						Type:         schema.TypeString,
@@ -1029,9 +1045,119 @@ func resourceNetworkDeviceRead(ctx context.Context, d *schema.ResourceData, m in
 	if err = updateNetworkDeviceResource(primary, secondary, d); err != nil {
 		return diag.FromErr(err)
 	}
+	diags = append(diags, updateNetworkDeviceACLStatuses(client.GetDeviceACLDetails, primary, secondary, d)...)
 	return diags
 }
 
+// updateNetworkDeviceACLStatuses sets the status of the ACL templates applied to
+// the primary and, for redundant devices, the secondary device. Each device
+// reports its own status as their templates are provisioned independently. The
+// status is informational, so a status that can not be fetched is left empty
+// with a warning rather than failing the refresh of the device.
+func updateNetworkDeviceACLStatuses(fetchFunc getACL, primary, secondary *ne.Device, d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	status, err := networkDeviceACLStatus(fetchFunc, primary)
+	if err != nil {
+		diags = append(diags, networkDeviceACLStatusWarning(err, cty.GetAttrPath(neDeviceSchemaNames["ACLTemplateStatus"])))
+	}
+	if err := d.Set(neDeviceSchemaNames["ACLTemplateStatus"], status); err != nil {
+		return append(diags, diag.Errorf("error reading ACLTemplateStatus: %s", err)...)
+	}
+	if secondary == nil {
+		return diags
+	}
+	status, err = networkDeviceACLStatus(fetchFunc, secondary)
+	if err != nil {
+		diags = append(diags, networkDeviceACLStatusWarning(err, cty.GetAttrPath(neDeviceSchemaNames["Secondary"]).IndexInt(0).GetAttr(neDeviceSchemaNames["ACLTemplateStatus"])))
+	}
+	secondaries, ok := d.Get(neDeviceSchemaNames["Secondary"]).([]interface{})
+	if !ok || len(secondaries) == 0 {
+		return diags
+	}
+	if secondaryMap, ok := secondaries[0].(map[string]interface{}); ok {
+		secondaryMap[neDeviceSchemaNames["ACLTemplateStatus"]] = status
+		if err := d.Set(neDeviceSchemaNames["Secondary"], secondaries); err != nil {
+			return append(diags, diag.Errorf("error reading Secondary: %s", err)...)
+		}
+	}
+	return diags
+}
+
+func networkDeviceACLStatusWarning(err error, attributePath cty.Path) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity:      diag.Warning,
+		Summary:       "Failed to read the status of the ACL templates of the network device",
+		Detail:        err.Error(),
+		AttributePath: attributePath,
+	}
+}
+
+// networkDeviceACLStatus returns the provisioning status of the ACL templates
+// applied to a device, or an empty string when none are applied
+func networkDeviceACLStatus(fetchFunc getACL, device *ne.Device) (string, error) {
+	if ne.StringValue(device.ACLTemplateUUID) == "" && ne.StringValue(device.MgmtAclTemplateUuid) == "" {
+		return "", nil
+	}
+	acl, err := fetchFunc(ne.StringValue(device.UUID))
+	if err != nil {
+		return "", fmt.Errorf("cannot fetch ACL details of network device %s due to %v", ne.StringValue(device.UUID), err)
+	}
+	return ne.StringValue(acl.Status), nil
+}
+
+// neDeviceRedundancyTypeSecondary is the redundancy type of the secondary device of a
+// redundant pair
+const neDeviceRedundancyTypeSecondary = "SECONDARY"
+
+func resourceNetworkDeviceImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*config.Config).Ne
+	m.(*config.Config).AddModuleToNEUserAgent(&client, d)
+	if err := importNetworkDevice(client.GetDevice, d); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// importNetworkDevice sets the attributes of an imported device that Read leaves to the
+// configuration, so that importing does not plan a replacement of the device. A redundant
+// pair is managed through its primary device, so importing the secondary device imports
+// the pair by the primary device UUID. The attributes of the secondary device are set by
+// Read, which takes them from the API while the state has no secondary device. Cluster
+// devices can not be imported as the API does not report the licenses of the cluster
+// nodes, which can not be changed without replacing the cluster.
+func importNetworkDevice(fetchFunc getDevice, d *schema.ResourceData) error {
+	device, err := fetchFunc(d.Id())
+	if err != nil {
+		return fmt.Errorf("cannot fetch network device %s due to %v", d.Id(), err)
+	}
+	if device.ClusterDetails != nil {
+		return fmt.Errorf("network device %s is a cluster device, importing cluster devices is not supported as the licenses of the cluster nodes are not reported by the API", d.Id())
+	}
+	if strings.EqualFold(ne.StringValue(device.RedundancyType), neDeviceRedundancyTypeSecondary) && ne.StringValue(device.RedundantUUID) != "" {
+		log.Printf("[DEBUG] Network device %s is a secondary device, importing its primary device %s", d.Id(), ne.StringValue(device.RedundantUUID))
+		device, err = fetchFunc(ne.StringValue(device.RedundantUUID))
+		if err != nil {
+			return fmt.Errorf("cannot fetch primary network device due to %v", err)
+		}
+		d.SetId(ne.StringValue(device.UUID))
+	}
+	if err := d.Set(neDeviceSchemaNames["LicenseToken"], device.LicenseToken); err != nil {
+		return fmt.Errorf("error reading LicenseToken: %s", err)
+	}
+	if err := d.Set(neDeviceSchemaNames["MgmtAclTemplateUuid"], device.MgmtAclTemplateUuid); err != nil {
+		return fmt.Errorf("error reading MgmtAclTemplateUuid: %s", err)
+	}
+	if err := d.Set(neDeviceSchemaNames["WanInterfaceId"], device.WanInterfaceId); err != nil {
+		return fmt.Errorf("error reading WanInterfaceId: %s", err)
+	}
+	if ne.StringValue(device.Connectivity) != "" {
+		if err := d.Set(neDeviceSchemaNames["Connectivity"], device.Connectivity); err != nil {
+			return fmt.Errorf("error reading Connectivity: %s", err)
+		}
+	}
+	return nil
+}
+
 func resourceNetworkDeviceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*config.Config).Ne
 	m.(*config.Config).AddModuleToNEUserAgent(&client, d)
@@ -1328,6 +1454,8 @@ func updateNetworkDeviceResource(primary *ne.Device, secondary *ne.Device, d *sc
 			secondary.LicenseFile = secondaryFromSchema.LicenseFile
 			secondary.LicenseToken = secondaryFromSchema.LicenseToken
 			secondary.CloudInitFileID = secondaryFromSchema.CloudInitFileID
+			secondary.MgmtAclTemplateUuid = secondaryFromSchema.MgmtAclTemplateUuid
+			secondary.WanInterfaceId = secondaryFromSchema.WanInterfaceId
 		}
 		if err := d.Set(neDeviceSchemaNames["Secondary"], flattenNetworkDeviceSecondary(secondary)); err != nil {
 			return fmt.Errorf("error reading Secondary: %s", err)
@@ -1363,6 +1491,7 @@ func flattenNetworkDeviceSecondary(device *ne.Device) interface{} {
 	transformed[neDeviceSchemaNames["LicenseToken"]] = device.LicenseToken
 	transformed[neDeviceSchemaNames["CloudInitFileID"]] = device.CloudInitFileID
 	transformed[neDeviceSchemaNames["ACLTemplateUUID"]] = device.ACLTemplateUUID
+	transformed[neDeviceSchemaNames["MgmtAclTemplateUuid"]] = device.MgmtAclTemplateUuid
 	transformed[neDeviceSchemaNames["SSHIPAddress"]] = device.SSHIPAddress
 	transformed[neDeviceSchemaNames["SSHIPFqdn"]] = device.SSHIPFqdn
 	transformed[neDeviceSchemaNames["AccountNumber"]] = device.AccountNumber
@@ -1371,6 +1500,7 @@ func flattenNetworkDeviceSecondary(device *ne.Device) interface{} {
 	transformed[neDeviceSchemaNames["ProjectID"]] = device.ProjectID
 	transformed[neDeviceSchemaNames["RedundantUUID"]] = device.RedundantUUID
 	transformed[neDeviceSchemaNames["AdditionalBandwidth"]] = device.AdditionalBandwidth
+	transformed[neDeviceSchemaNames["WanInterfaceId"]] = device.WanInterfaceId
 	transformed[neDeviceSchemaNames["Interfaces"]] = flattenNetworkDeviceInterfaces(device.Interfaces)
 	transformed[neDeviceSchemaNames["VendorConfiguration"]] = device.VendorConfiguration
 	transformed[neDeviceSchemaNames["UserPublicKey"]] = flattenNetworkDeviceUserKeys([]*ne.DeviceUserPublicKey{device.UserPublicKey})
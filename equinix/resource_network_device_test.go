@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path"
 	"testing"
 	"time"
 
 	"github.com/equinix/ne-go"
+	"github.com/equinix/terraform-provider-equinix/internal/config"
 	"github.com/equinix/terraform-provider-equinix/internal/converters"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
@@ -170,6 +174,143 @@ func TestNetworkDevice_updateResourceData(t *testing.T) {
 	assert.Equal(t, secondarySchemaLicenseFile, ne.StringValue(expandNetworkDeviceSecondary(d.Get(neDeviceSchemaNames["Secondary"]).([]interface{})).LicenseFile), "Secondary LicenseFile matches")
 }
 
+func TestNetworkDevice_updateACLStatuses(t *testing.T) {
+	// given
+	primary := &ne.Device{
+		UUID:            ne.String("primaryId"),
+		ACLTemplateUUID: ne.String("primaryAclId"),
+	}
+	secondary := &ne.Device{
+		UUID:                ne.String("secondaryId"),
+		MgmtAclTemplateUuid: ne.String("secondaryMgmtAclId"),
+	}
+	statuses := map[string]string{
+		"primaryId":   ne.ACLDeviceStatusProvisioned,
+		"secondaryId": ne.ACLDeviceStatusProvisioning,
+	}
+	fetchFunc := func(uuid string) (*ne.DeviceACLDetails, error) {
+		return &ne.DeviceACLDetails{Status: ne.String(statuses[uuid])}, nil
+	}
+	d := schema.TestResourceDataRaw(t, createNetworkDeviceSchema(), make(map[string]interface{}))
+	d.Set(neDeviceSchemaNames["Secondary"], flattenNetworkDeviceSecondary(secondary))
+	// when
+	diags := updateNetworkDeviceACLStatuses(fetchFunc, primary, secondary, d)
+	// then
+	assert.Empty(t, diags, "Update of ACL statuses does not return diagnostics")
+	assert.Equal(t, ne.ACLDeviceStatusProvisioned, d.Get(neDeviceSchemaNames["ACLTemplateStatus"]), "Primary ACLTemplateStatus matches")
+	assert.Equal(t, ne.ACLDeviceStatusProvisioning, d.Get(neDeviceSchemaNames["Secondary"]+".0."+neDeviceSchemaNames["ACLTemplateStatus"]), "Secondary ACLTemplateStatus matches")
+
+	// given ACL details that can not be fetched
+	fetchFunc = func(uuid string) (*ne.DeviceACLDetails, error) {
+		return nil, fmt.Errorf("cannot fetch ACL details for %s", uuid)
+	}
+	// when
+	diags = updateNetworkDeviceACLStatuses(fetchFunc, primary, secondary, d)
+	// then
+	assert.False(t, diags.HasError(), "Update of ACL statuses does not fail on fetch errors")
+	assert.Len(t, diags, 2, "Update of ACL statuses warns about each device")
+	assert.Empty(t, d.Get(neDeviceSchemaNames["ACLTemplateStatus"]), "Primary ACLTemplateStatus is empty")
+	assert.Empty(t, d.Get(neDeviceSchemaNames["Secondary"]+".0."+neDeviceSchemaNames["ACLTemplateStatus"]), "Secondary ACLTemplateStatus is empty")
+
+	// given no ACL templates
+	primary.ACLTemplateUUID = nil
+	fetchFunc = func(uuid string) (*ne.DeviceACLDetails, error) {
+		return nil, fmt.Errorf("unexpected fetch of ACL details for %s", uuid)
+	}
+	// when
+	diags = updateNetworkDeviceACLStatuses(fetchFunc, primary, nil, d)
+	// then
+	assert.Empty(t, diags, "Update of ACL statuses does not fetch unassigned ACLs")
+	assert.Empty(t, d.Get(neDeviceSchemaNames["ACLTemplateStatus"]), "Primary ACLTemplateStatus is empty")
+}
+
+func TestNetworkDevice_import(t *testing.T) {
+	// given
+	primaryID := "8e7ed5f6-2c3f-4c1f-9c8a-6d1bb1a4a001"
+	secondaryID := "8e7ed5f6-2c3f-4c1f-9c8a-6d1bb1a4a002"
+	fixtures := map[string]string{
+		primaryID:   "test-fixtures/ne_device_import_primary.json",
+		secondaryID: "test-fixtures/ne_device_import_secondary.json",
+	}
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := fixtures[path.Base(r.URL.Path)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Errorf("cannot read fixture %s: %v", fixture, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{Ne: ne.NewClient(context.Background(), mockAPI.URL, mockAPI.Client())}
+	resource := resourceNetworkDevice()
+
+	for _, importID := range []string{primaryID, secondaryID} {
+		d := resource.TestResourceData()
+		d.SetId(importID)
+		// when
+		imported, err := resource.Importer.StateContext(context.Background(), d, meta)
+		assert.Nil(t, err, "Import does not return error")
+		assert.Len(t, imported, 1, "Import returns one device")
+		diags := resource.ReadContext(context.Background(), imported[0], meta)
+		// then
+		assert.False(t, diags.HasError(), "Read of imported device does not return error")
+		assert.Equal(t, primaryID, d.Id(), "Imported device is the primary device")
+		assert.Equal(t, "CSR1000V", d.Get(neDeviceSchemaNames["TypeCode"]), "TypeCode matches")
+		assert.Equal(t, "SEC", d.Get(neDeviceSchemaNames["PackageCode"]), "PackageCode matches")
+		assert.Equal(t, "16.09.05", d.Get(neDeviceSchemaNames["Version"]), "Version matches")
+		assert.Equal(t, "SV", d.Get(neDeviceSchemaNames["MetroCode"]), "MetroCode matches")
+		assert.Equal(t, "200461", d.Get(neDeviceSchemaNames["AccountNumber"]), "AccountNumber matches")
+		assert.Equal(t, true, d.Get(neDeviceSchemaNames["IsBYOL"]), "IsBYOL matches")
+		assert.Equal(t, true, d.Get(neDeviceSchemaNames["IsSelfManaged"]), "IsSelfManaged matches")
+		assert.Equal(t, "licenseTokenPrimary", d.Get(neDeviceSchemaNames["LicenseToken"]), "LicenseToken matches")
+		assert.Equal(t, "PRIVATE", d.Get(neDeviceSchemaNames["Connectivity"]), "Connectivity matches")
+		assert.Equal(t, "7", d.Get(neDeviceSchemaNames["WanInterfaceId"]), "WanInterfaceId matches")
+		assert.Equal(t, secondaryID, d.Get(neDeviceSchemaNames["Secondary"]+".0."+neDeviceSchemaNames["UUID"]), "Secondary UUID matches")
+		assert.Equal(t, "licenseTokenSecondary", d.Get(neDeviceSchemaNames["Secondary"]+".0."+neDeviceSchemaNames["LicenseToken"]), "Secondary LicenseToken matches")
+		assert.Equal(t, "8", d.Get(neDeviceSchemaNames["Secondary"]+".0."+neDeviceSchemaNames["WanInterfaceId"]), "Secondary WanInterfaceId matches")
+		// and when
+		diags = resource.ReadContext(context.Background(), imported[0], meta)
+		// then
+		assert.False(t, diags.HasError(), "Refresh of imported device does not return error")
+		assert.Equal(t, "8", d.Get(neDeviceSchemaNames["Secondary"]+".0."+neDeviceSchemaNames["WanInterfaceId"]), "Secondary WanInterfaceId is kept on refresh")
+	}
+}
+
+func TestNetworkDevice_importCluster(t *testing.T) {
+	// given
+	clusterID := "8e7ed5f6-2c3f-4c1f-9c8a-6d1bb1a4a003"
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != clusterID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, err := os.ReadFile("test-fixtures/ne_device_import_cluster.json")
+		if err != nil {
+			t.Errorf("cannot read fixture: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{Ne: ne.NewClient(context.Background(), mockAPI.URL, mockAPI.Client())}
+	resource := resourceNetworkDevice()
+	d := resource.TestResourceData()
+	d.SetId(clusterID)
+	// when
+	_, err := resource.Importer.StateContext(context.Background(), d, meta)
+	// then
+	assert.ErrorContains(t, err, "importing cluster devices is not supported", "Import of a cluster device returns error")
+}
+
 func TestNetworkDevice_flattenSecondary(t *testing.T) {
 	// given
 	input := &ne.Device{
@@ -232,6 +373,7 @@ func TestNetworkDevice_flattenSecondary(t *testing.T) {
 			neDeviceSchemaNames["LicenseFile"]:         input.LicenseFile,
 			neDeviceSchemaNames["CloudInitFileID"]:     input.CloudInitFileID,
 			neDeviceSchemaNames["ACLTemplateUUID"]:     input.ACLTemplateUUID,
+			neDeviceSchemaNames["MgmtAclTemplateUuid"]: input.MgmtAclTemplateUuid,
 			neDeviceSchemaNames["SSHIPAddress"]:        input.SSHIPAddress,
 			neDeviceSchemaNames["SSHIPFqdn"]:           input.SSHIPFqdn,
 			neDeviceSchemaNames["AccountNumber"]:       input.AccountNumber,
@@ -239,6 +381,7 @@ func TestNetworkDevice_flattenSecondary(t *testing.T) {
 			neDeviceSchemaNames["RedundancyType"]:      input.RedundancyType,
 			neDeviceSchemaNames["RedundantUUID"]:       input.RedundantUUID,
 			neDeviceSchemaNames["AdditionalBandwidth"]: input.AdditionalBandwidth,
+			neDeviceSchemaNames["WanInterfaceId"]:      input.WanInterfaceId,
 			neDeviceSchemaNames["ProjectID"]:           input.ProjectID,
 			neDeviceSchemaNames["Interfaces"]: []interface{}{
 				map[string]interface{}{
@@ -453,6 +596,22 @@ func TestNetworkDevice_ACLStatusWaitConfiguration(t *testing.T) {
 	assert.Equal(t, delay, waitConfig.MinTimeout, "Device status wait configuration min timeout matches")
 }
 
+func TestNetworkDevice_AdditionalBandwidthValidation(t *testing.T) {
+	deviceSchema := createNetworkDeviceSchema()
+	secondarySchema := deviceSchema[neDeviceSchemaNames["Secondary"]].Elem.(*schema.Resource).Schema
+	validators := map[string]schema.SchemaValidateFunc{
+		"primary":   deviceSchema[neDeviceSchemaNames["AdditionalBandwidth"]].ValidateFunc,
+		"secondary": secondarySchema[neDeviceSchemaNames["AdditionalBandwidth"]].ValidateFunc,
+	}
+	for name, validate := range validators {
+		// the most allowed depends on the device package, which only the API checks
+		for bandwidth, valid := range map[int]bool{-1: false, 0: true, 500: true, 10000: true} {
+			_, errs := validate(bandwidth, neDeviceSchemaNames["AdditionalBandwidth"])
+			assert.Equal(t, valid, len(errs) == 0, "%s additional bandwidth %d is valid", name, bandwidth)
+		}
+	}
+}
+
 func TestNetworkDevice_AdditionalBandwidthStatusWaitConfiguration(t *testing.T) {
 	// given
 	deviceID := "test"
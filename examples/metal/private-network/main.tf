@@ -0,0 +1,24 @@
+provider "equinix" {
+  auth_token = var.equinix_auth_token
+}
+
+resource "equinix_metal_vlan" "private" {
+  description = var.name
+  metro       = var.metro
+  project_id  = var.project_id
+}
+
+resource "equinix_metal_reserved_ip_block" "private" {
+  description = var.name
+  project_id  = var.project_id
+  metro       = var.metro
+  quantity    = var.ip_block_size
+}
+
+# The gateway references both the VLAN and the IP reservation, so Terraform
+# creates it last and destroys it first
+resource "equinix_metal_gateway" "private" {
+  project_id        = var.project_id
+  vlan_id           = equinix_metal_vlan.private.id
+  ip_reservation_id = equinix_metal_reserved_ip_block.private.id
+}
@@ -0,0 +1,19 @@
+output "vlan_id" {
+  value = equinix_metal_vlan.private.id
+}
+
+output "vxlan" {
+  value = equinix_metal_vlan.private.vxlan
+}
+
+output "gateway_id" {
+  value = equinix_metal_gateway.private.id
+}
+
+output "cidr_notation" {
+  value = equinix_metal_reserved_ip_block.private.cidr_notation
+}
+
+output "gateway_address" {
+  value = equinix_metal_reserved_ip_block.private.gateway
+}
@@ -0,0 +1,8 @@
+variable "equinix_auth_token" {}
+variable "project_id" {}
+variable "metro" {}
+variable "name" {}
+
+variable "ip_block_size" {
+  default = 8
+}
@@ -12,6 +12,7 @@ import (
 	"path"
 	"regexp"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -173,6 +174,16 @@ func (c *Config) NewFabricClientForSDK(d *schema.ResourceData) *fabricv4.APIClie
 	return client
 }
 
+// NewFabricClientForSDKDiff returns a client for the CustomizeDiff of SDKv2
+// resources, which cannot read the module name from provider_meta
+func (c *Config) NewFabricClientForSDKDiff() *fabricv4.APIClient {
+	client := c.newFabricClient()
+
+	client.GetConfig().UserAgent = c.tfSdkUserAgent(client.GetConfig().UserAgent)
+
+	return client
+}
+
 // newFabricClient returns the base fabricv4 client that is then used for either the sdkv2 or framework
 // implementations of the Terraform Provider with exported Methods
 func (c *Config) newFabricClient() *fabricv4.APIClient {
@@ -185,6 +196,8 @@ func (c *Config) newFabricClient() *fabricv4.APIClient {
 	retryClient.RetryWaitMin = time.Second
 	retryClient.RetryWaitMax = c.MaxRetryWait
 	retryClient.CheckRetry = RetryPolicy
+	retryClient.Backoff = RetryBackoff
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
 	standardClient := retryClient.StandardClient()
 
 	baseURL, _ := url.Parse(c.BaseURL)
@@ -215,6 +228,8 @@ func (c *Config) NewMetalClient() *packngo.Client {
 	retryClient.RetryWaitMin = time.Second
 	retryClient.RetryWaitMax = c.MaxRetryWait
 	retryClient.CheckRetry = RetryPolicy
+	retryClient.Backoff = RetryBackoff
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
 	standardClient := retryClient.StandardClient()
 	baseURL, _ := url.Parse(c.BaseURL)
 	baseURL.Path = path.Join(baseURL.Path, metalBasePath) + "/"
@@ -233,6 +248,16 @@ func (c *Config) NewMetalClientForSDK(d *schema.ResourceData) *metalv1.APIClient
 	return client
 }
 
+// NewMetalClientForSDKDiff returns a client for the CustomizeDiff of SDKv2
+// resources, which cannot read the module name from provider_meta
+func (c *Config) NewMetalClientForSDKDiff() *metalv1.APIClient {
+	client := c.newMetalClient()
+
+	client.GetConfig().UserAgent = c.tfSdkUserAgent(client.GetConfig().UserAgent)
+
+	return client
+}
+
 func (c *Config) NewMetalClientForFramework(ctx context.Context, meta tfsdk.Config) *metalv1.APIClient {
 	client := c.newMetalClient()
 
@@ -262,6 +287,8 @@ func (c *Config) newMetalClient() *metalv1.APIClient {
 	retryClient.RetryWaitMin = time.Second
 	retryClient.RetryWaitMax = c.MaxRetryWait
 	retryClient.CheckRetry = RetryPolicy
+	retryClient.Backoff = RetryBackoff
+	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
 	standardClient := retryClient.StandardClient()
 
 	baseURL, _ := url.Parse(c.BaseURL)
@@ -306,9 +333,63 @@ func RetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, err
 		// The error is likely recoverable so retry.
 		return true, nil
 	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// Rate limited requests were not processed, so they are always retried
+		return true, nil
+	}
+	if resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented {
+		// The request may have been processed before the server failed, only
+		// retry it when sending it again has the same effect
+		return isIdempotent(resp.Request), nil
+	}
 	return false, nil
 }
 
+// RetryBackoff waits for as long as the Retry-After header of a rate limited
+// or failed response asks for, up to max, and backs off exponentially from min
+// otherwise
+func RetryBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500) {
+		if sleep, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if sleep > max {
+				return max
+			}
+			return sleep
+		}
+	}
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, nil)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as
+// an HTTP date
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func isIdempotent(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
 func appendUserAgentFromEnv(ua string) string {
 	if add := os.Getenv(uaEnvVar); add != "" {
 		add = strings.TrimSpace(add)
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+		want   bool
+	}{
+		{name: "ok", method: http.MethodGet, status: http.StatusOK, want: false},
+		{name: "not found", method: http.MethodGet, status: http.StatusNotFound, want: false},
+		{name: "rate limited get", method: http.MethodGet, status: http.StatusTooManyRequests, want: true},
+		{name: "rate limited post", method: http.MethodPost, status: http.StatusTooManyRequests, want: true},
+		{name: "server error get", method: http.MethodGet, status: http.StatusBadGateway, want: true},
+		{name: "server error delete", method: http.MethodDelete, status: http.StatusServiceUnavailable, want: true},
+		{name: "server error post", method: http.MethodPost, status: http.StatusServiceUnavailable, want: false},
+		{name: "not implemented", method: http.MethodGet, status: http.StatusNotImplemented, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "https://api.equinix.com/metal/v1/projects", nil)
+			resp := &http.Response{StatusCode: tt.status, Request: req}
+			got, err := RetryPolicy(context.Background(), resp, nil)
+			if err != nil {
+				t.Fatalf("RetryPolicy() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RetryPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	withRetryAfter := func(status int, value string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		resp.Header.Set("Retry-After", value)
+		return resp
+	}
+
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		attempt int
+		want    time.Duration
+	}{
+		{name: "exponential", resp: nil, attempt: 2, want: 4 * time.Second},
+		{name: "exponential capped", resp: nil, attempt: 10, want: 30 * time.Second},
+		{name: "retry after", resp: withRetryAfter(http.StatusTooManyRequests, "7"), attempt: 0, want: 7 * time.Second},
+		{name: "retry after server error", resp: withRetryAfter(http.StatusBadGateway, "3"), attempt: 0, want: 3 * time.Second},
+		{name: "retry after capped", resp: withRetryAfter(http.StatusTooManyRequests, "120"), attempt: 0, want: 30 * time.Second},
+		{name: "retry after invalid", resp: withRetryAfter(http.StatusTooManyRequests, "soon"), attempt: 1, want: 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RetryBackoff(time.Second, 30*time.Second, tt.attempt, tt.resp); got != tt.want {
+				t.Errorf("RetryBackoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMetalClientRetriesRateLimits(t *testing.T) {
+	var calls int32
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Add("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "projectId"}`))
+	}))
+	defer mockAPI.Close()
+
+	meta := &Config{BaseURL: mockAPI.URL, Token: "fakeTokenForMock", MaxRetries: 3, MaxRetryWait: time.Second}
+	if err := meta.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	project, _, err := meta.NewMetalClientForTesting().ProjectsApi.FindProjectById(context.Background(), "projectId").Execute()
+	if err != nil {
+		t.Fatalf("FindProjectById() error = %v", err)
+	}
+	if project.GetId() != "projectId" || calls != 3 {
+		t.Errorf("FindProjectById() = %v after %d calls, want projectId after 3 calls", project.GetId(), calls)
+	}
+}
@@ -218,6 +218,11 @@ func applyFilters(recordSchema map[string]*schema.Schema, records []map[string]i
 		var filteredRecords []map[string]interface{}
 
 		filterFunc := func(record map[string]interface{}) bool {
+			// Records without a value for the attribute match no filter
+			if record[f.attribute] == nil {
+				return false
+			}
+
 			result := f.all
 
 			for _, filterValue := range f.values {
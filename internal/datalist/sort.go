@@ -68,6 +68,14 @@ func applySorts(recordSchema map[string]*schema.Schema, records []map[string]int
 
 			value1 := records[i]
 			value2 := records[j]
+			// Records without a value for the attribute sort last, whatever
+			// the direction
+			if missing1, missing2 := records[_i][s.attribute] == nil, records[_j][s.attribute] == nil; missing1 || missing2 {
+				if missing1 != missing2 {
+					return missing2
+				}
+				continue
+			}
 			cmp := compareValues(recordSchema[s.attribute], value1[s.attribute], value2[s.attribute])
 			if cmp != 0 {
 				return cmp < 0
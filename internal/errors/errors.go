@@ -1,8 +1,11 @@
 package errors
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/equinix/equinix-sdk-go/services/fabricv4"
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"net/http"
 	"strings"
 
@@ -49,6 +52,33 @@ func convertToFriendlyError(errors Errors, resp *http.Response) error {
 	return er
 }
 
+// MetalErrorMessages returns the messages of an Equinix Metal API error
+// response from packngo or metal-go, which lists every validation failure of a
+// request as a separate message. It returns nil for errors that do not come
+// from an API response.
+func MetalErrorMessages(err error) []string {
+	var messages []string
+	if e, ok := err.(*packngo.ErrorResponse); ok {
+		messages = append(messages, e.Errors...)
+		if e.SingleError != "" {
+			messages = append(messages, e.SingleError)
+		}
+		return messages
+	}
+
+	var apiErr *metalv1.GenericOpenAPIError
+	if errors.As(err, &apiErr) {
+		model := metalv1.Error{}
+		if json.Unmarshal(apiErr.Body(), &model) == nil {
+			messages = append(messages, model.GetErrors()...)
+			if model.GetError() != "" {
+				messages = append(messages, model.GetError())
+			}
+		}
+	}
+	return messages
+}
+
 func FormatFabricError(err error) error {
 	var errors Errors
 	errors = append(errors, err.Error())
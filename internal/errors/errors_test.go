@@ -1,12 +1,16 @@
 package errors
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/equinix/rest-go"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/packethost/packngo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,3 +51,35 @@ func TestProvider_IsRestNotFoundError(t *testing.T) {
 	// then
 	assert.Equal(t, expected, result, "Result matches expected output")
 }
+
+func TestProvider_MetalErrorMessages(t *testing.T) {
+	// given
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"errors": ["Hostname is invalid", "Plan is not available"]}`))
+	}))
+	defer mockAPI.Close()
+
+	configuration := metalv1.NewConfiguration()
+	configuration.Servers = metalv1.ServerConfigurations{{URL: mockAPI.URL}}
+	_, _, metalErr := metalv1.NewAPIClient(configuration).DevicesApi.FindDeviceById(context.Background(), "deviceId").Execute()
+
+	input := []error{
+		metalErr,
+		&packngo.ErrorResponse{Errors: []string{"Hostname is invalid"}, SingleError: "Plan is not available"},
+		fmt.Errorf("some bogus error"),
+	}
+	expected := [][]string{
+		{"Hostname is invalid", "Plan is not available"},
+		{"Hostname is invalid", "Plan is not available"},
+		nil,
+	}
+	// when
+	result := make([][]string, len(input))
+	for i := range input {
+		result[i] = MetalErrorMessages(input[i])
+	}
+	// then
+	assert.Equal(t, expected, result, "Result matches expected output")
+}
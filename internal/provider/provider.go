@@ -5,14 +5,19 @@ import (
 	"fmt"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	metalcapacity "github.com/equinix/terraform-provider-equinix/internal/resources/metal/capacity"
 	metalconnection "github.com/equinix/terraform-provider-equinix/internal/resources/metal/connection"
+	metaldeviceevents "github.com/equinix/terraform-provider-equinix/internal/resources/metal/device_events"
 	metalgateway "github.com/equinix/terraform-provider-equinix/internal/resources/metal/gateway"
 	metalorganization "github.com/equinix/terraform-provider-equinix/internal/resources/metal/organization"
 	metalorganizationmember "github.com/equinix/terraform-provider-equinix/internal/resources/metal/organization_member"
 	metalproject "github.com/equinix/terraform-provider-equinix/internal/resources/metal/project"
+	metalprojectbgpconfig "github.com/equinix/terraform-provider-equinix/internal/resources/metal/project_bgp_config"
 	metalprojectsshkey "github.com/equinix/terraform-provider-equinix/internal/resources/metal/project_ssh_key"
+	metalprojectsshkeys "github.com/equinix/terraform-provider-equinix/internal/resources/metal/project_ssh_keys"
 	metalsshkey "github.com/equinix/terraform-provider-equinix/internal/resources/metal/ssh_key"
 	"github.com/equinix/terraform-provider-equinix/internal/resources/metal/vlan"
+	metalvlanattachment "github.com/equinix/terraform-provider-equinix/internal/resources/metal/vlan_attachment"
 	equinix_validation "github.com/equinix/terraform-provider-equinix/internal/validation"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -88,11 +93,11 @@ func (p *FrameworkProvider) Schema(
 			},
 			"max_retries": schema.Int64Attribute{
 				Optional:    true,
-				Description: "Maximum number of retries.",
+				Description: "Maximum number of retries after network failures, rate limited (429) responses, and server errors (5xx) of idempotent requests.",
 			},
 			"max_retry_wait_seconds": schema.Int64Attribute{
 				Optional:    true,
-				Description: "Maximum number of seconds to wait before retrying a request.",
+				Description: "Maximum number of seconds to wait before retrying a request, including waits requested by a Retry-After header.",
 			},
 		},
 	}
@@ -117,11 +122,14 @@ func (p *FrameworkProvider) Resources(ctx context.Context) []func() resource.Res
 		metalgateway.NewResource,
 		metalproject.NewResource,
 		metalprojectsshkey.NewResource,
+		metalprojectsshkeys.NewResource,
 		metalsshkey.NewResource,
 		metalconnection.NewResource,
 		metalorganization.NewResource,
 		metalorganizationmember.NewResource,
 		vlan.NewResource,
+		metalvlanattachment.NewResource,
+		metalprojectbgpconfig.NewResource,
 	}
 }
 
@@ -130,8 +138,11 @@ func (p *FrameworkProvider) DataSources(ctx context.Context) []func() datasource
 		metalgateway.NewDataSource,
 		metalproject.NewDataSource,
 		metalprojectsshkey.NewDataSource,
+		metalsshkey.NewDataSource,
 		metalconnection.NewDataSource,
 		metalorganization.NewDataSource,
 		vlan.NewDataSource,
+		metalcapacity.NewDataSource,
+		metaldeviceevents.NewDataSource,
 	}
 }
@@ -0,0 +1,75 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/equinix/equinix-sdk-go/services/fabricv4"
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// customizeDiffFabricConnectionBandwidth replaces the connection when its bandwidth is
+// changed but the z_side service profile does not allow bandwidth changes, bandwidth
+// changes are otherwise made in place
+func customizeDiffFabricConnectionBandwidth(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("bandwidth") {
+		return nil
+	}
+	profileUuid := zSideProfileUuid(d)
+	if profileUuid == "" {
+		return nil
+	}
+	// meta is not set when the diff is computed in unit tests
+	cfg, ok := meta.(*config.Config)
+	if !ok {
+		return nil
+	}
+
+	client := cfg.NewFabricClientForSDKDiff()
+	profile, resp, err := client.ServiceProfilesApi.GetServiceProfileByUuid(ctx, profileUuid).Execute()
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			log.Printf("[WARN] Service profile %s of connection %s not found, updating bandwidth in place", profileUuid, d.Id())
+			return nil
+		}
+		return fmt.Errorf("error reading service profile %s to check for bandwidth changes: %v", profileUuid, equinix_errors.FormatFabricError(err))
+	}
+
+	if !bandwidthChangeAllowed(profile) {
+		log.Printf("[DEBUG] Service profile %s does not allow bandwidth changes, connection %s will be replaced", profileUuid, d.Id())
+		return d.ForceNew("bandwidth")
+	}
+	return nil
+}
+
+// zSideProfileUuid returns the uuid of the z_side service profile, or an empty string
+// when the connection is not to a service profile
+func zSideProfileUuid(d *schema.ResourceDiff) string {
+	profiles, ok := d.Get("z_side.0.access_point.0.profile").(*schema.Set)
+	if !ok || profiles.Len() == 0 {
+		return ""
+	}
+	profile := profiles.List()[0].(map[string]interface{})
+	uuid, _ := profile["uuid"].(string)
+	return uuid
+}
+
+// bandwidthChangeAllowed reports whether the COLO access point type of the service profile
+// allows the bandwidth of existing connections to be changed. Profiles that do not say are
+// left for the API to decide.
+func bandwidthChangeAllowed(profile *fabricv4.ServiceProfile) bool {
+	for _, accessPointType := range profile.GetAccessPointTypeConfigs() {
+		colo := accessPointType.ServiceProfileAccessPointTypeCOLO
+		if colo == nil {
+			continue
+		}
+		if allowed, ok := colo.GetAllowBandwidthUpgradeOk(); ok && !*allowed {
+			return false
+		}
+	}
+	return true
+}
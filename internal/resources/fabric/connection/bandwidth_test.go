@@ -0,0 +1,36 @@
+package connection
+
+import (
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/fabricv4"
+)
+
+func TestBandwidthChangeAllowed(t *testing.T) {
+	allowed, notAllowed := true, false
+	colo := func(allowBandwidthUpgrade *bool) fabricv4.ServiceProfileAccessPointType {
+		return fabricv4.ServiceProfileAccessPointTypeCOLOAsServiceProfileAccessPointType(&fabricv4.ServiceProfileAccessPointTypeCOLO{
+			Type:                  fabricv4.SERVICEPROFILEACCESSPOINTTYPEENUM_COLO,
+			AllowBandwidthUpgrade: allowBandwidthUpgrade,
+		})
+	}
+
+	tests := map[string]struct {
+		configs []fabricv4.ServiceProfileAccessPointType
+		want    bool
+	}{
+		"allowed":     {configs: []fabricv4.ServiceProfileAccessPointType{colo(&allowed)}, want: true},
+		"not allowed": {configs: []fabricv4.ServiceProfileAccessPointType{colo(&notAllowed)}, want: false},
+		"unset":       {configs: []fabricv4.ServiceProfileAccessPointType{colo(nil)}, want: true},
+		"no configs":  {want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			profile := &fabricv4.ServiceProfile{AccessPointTypeConfigs: tt.configs}
+			if got := bandwidthChangeAllowed(profile); got != tt.want {
+				t.Errorf("bandwidthChangeAllowed() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,8 +11,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"strings"
+	"time"
 )
 
+// defaultStatisticsWindow is how far back connection statistics are read
+// from when no start_date_time is given
+const defaultStatisticsWindow = 24 * time.Hour
+
 func DataSource() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceFabricConnectionRead,
@@ -27,6 +32,65 @@ func dataSourceFabricConnectionRead(ctx context.Context, d *schema.ResourceData,
 	return resourceFabricConnectionRead(ctx, d, meta)
 }
 
+func DataSourceStatistics() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceFabricConnectionStatisticsRead,
+		Schema:      readFabricConnectionStatisticsSchema(),
+		Description: "Fabric V4 API compatible data resource that allow user to fetch bandwidth utilization statistics of a connection for a given UUID",
+	}
+}
+
+func dataSourceFabricConnectionStatisticsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*config.Config).NewFabricClientForSDK(d)
+	connectionId := d.Get("connection_id").(string)
+
+	endDateTime := time.Now().UTC()
+	if v, ok := d.GetOk("end_date_time"); ok {
+		endDateTime, _ = time.Parse(time.RFC3339, v.(string))
+	}
+	startDateTime := endDateTime.Add(-defaultStatisticsWindow)
+	if v, ok := d.GetOk("start_date_time"); ok {
+		startDateTime, _ = time.Parse(time.RFC3339, v.(string))
+	}
+	if !startDateTime.Before(endDateTime) {
+		return diag.Errorf("start_date_time %s must be before end_date_time %s", startDateTime.Format(time.RFC3339), endDateTime.Format(time.RFC3339))
+	}
+	viewPoint := fabricv4.ViewPoint(d.Get("view_point").(string))
+
+	stats, _, err := client.StatisticsApi.GetConnectionStatsByPortUuid(ctx, connectionId).
+		StartDateTime(startDateTime).
+		EndDateTime(endDateTime).
+		ViewPoint(viewPoint).
+		Execute()
+	if err != nil {
+		return diag.FromErr(equinix_errors.FormatFabricError(err))
+	}
+
+	d.SetId(connectionId)
+	return setConnectionStatisticsData(d, stats, startDateTime, endDateTime)
+}
+
+func setConnectionStatisticsData(d *schema.ResourceData, stats *fabricv4.Statistics, startDateTime, endDateTime time.Time) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	// The API echoes the window it collected the statistics for, which may be
+	// aligned to the metric interval
+	if v, ok := stats.GetStartDateTimeOk(); ok {
+		startDateTime = *v
+	}
+	if v, ok := stats.GetEndDateTimeOk(); ok {
+		endDateTime = *v
+	}
+	err := equinix_schema.SetMap(d, map[string]interface{}{
+		"start_date_time":       startDateTime.Format(time.RFC3339),
+		"end_date_time":         endDateTime.Format(time.RFC3339),
+		"bandwidth_utilization": bandwidthUtilizationGoToTerraform(stats.BandwidthUtilization),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return diags
+}
+
 func DataSourceSearch() *schema.Resource {
 	return &schema.Resource{
 		ReadContext: dataSourceFabricConnectionSearch,
@@ -21,6 +21,8 @@ func readFabricConnectionResourceSchema() map[string]*schema.Schema {
 			sch[key].Computed = true
 			sch[key].MaxItems = 0
 			sch[key].ValidateFunc = nil
+			sch[key].ExactlyOneOf = nil
+			sch[key].DiffSuppressFunc = nil
 		}
 	}
 	return sch
@@ -130,3 +132,105 @@ func readFabricConnectionSearchSchema() map[string]*schema.Schema {
 		},
 	}
 }
+
+func readFabricConnectionStatisticsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"connection_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.IsUUID,
+			Description:  "Equinix-assigned connection identifier",
+		},
+		"start_date_time": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IsRFC3339Time,
+			Description:  "Start of the statistics window, in RFC 3339 format. Defaults to 24 hours before end_date_time",
+		},
+		"end_date_time": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.IsRFC3339Time,
+			Description:  "End of the statistics window, in RFC 3339 format. Defaults to the time the data source is read",
+		},
+		"view_point": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      string(fabricv4.VIEWPOINT_A_SIDE),
+			ValidateFunc: validation.StringInSlice([]string{string(fabricv4.VIEWPOINT_A_SIDE), string(fabricv4.VIEWPOINT_Z_SIDE)}, false),
+			Description:  fmt.Sprintf("Side of the connection the statistics are collected from. One of %v. Defaults to aSide", fabricv4.AllowedViewPointEnumValues),
+		},
+		"bandwidth_utilization": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Bandwidth utilization of the connection over the statistics window",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"unit": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Unit of the bandwidth utilization values, e.g. Mbps",
+					},
+					"metric_interval": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Interval between two metrics, e.g. PT5M",
+					},
+					"inbound": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "Ingress traffic of the connection",
+						Elem:        &schema.Resource{Schema: readFabricConnectionStatisticsDirectionSchema()},
+					},
+					"outbound": {
+						Type:        schema.TypeList,
+						Computed:    true,
+						Description: "Egress traffic of the connection",
+						Elem:        &schema.Resource{Schema: readFabricConnectionStatisticsDirectionSchema()},
+					},
+				},
+			},
+		},
+	}
+}
+
+func readFabricConnectionStatisticsDirectionSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"max": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Highest bandwidth utilization over the statistics window",
+		},
+		"mean": {
+			Type:        schema.TypeFloat,
+			Computed:    true,
+			Description: "Average bandwidth utilization over the statistics window",
+		},
+		"metrics": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Bandwidth utilization of every interval of the statistics window",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"interval_end_timestamp": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "End of the interval, in RFC 3339 format",
+					},
+					"max": {
+						Type:        schema.TypeFloat,
+						Computed:    true,
+						Description: "Highest bandwidth utilization over the interval",
+					},
+					"mean": {
+						Type:        schema.TypeFloat,
+						Computed:    true,
+						Description: "Average bandwidth utilization over the interval",
+					},
+				},
+			},
+		},
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"log"
+	"time"
 )
 
 func additionalInfoContainsAWSSecrets(info []interface{}) ([]interface{}, bool) {
@@ -41,6 +42,7 @@ func connectionMap(conn *fabricv4.Connection) map[string]interface{} {
 	connection["name"] = conn.GetName()
 	connection["uuid"] = conn.GetUuid()
 	connection["bandwidth"] = conn.GetBandwidth()
+	connection["speed"] = formatSpeed(int(conn.GetBandwidth()))
 	connection["href"] = conn.GetHref()
 	connection["is_remote"] = conn.GetIsRemote()
 	connection["type"] = string(conn.GetType())
@@ -573,7 +575,7 @@ func simplifiedServiceProfileGoToTerraform(profile *fabricv4.SimplifiedServicePr
 	mappedProfile["href"] = profile.GetHref()
 	mappedProfile["type"] = string(profile.GetType())
 	mappedProfile["name"] = profile.GetName()
-	mappedProfile["uuid"] = profile.GetName()
+	mappedProfile["uuid"] = profile.GetUuid()
 	mappedProfile["access_point_type_configs"] = accessPointTypeConfigGoToTerraform(profile.AccessPointTypeConfigs)
 
 	profileSet := schema.NewSet(
@@ -734,3 +736,39 @@ func portRedundancyGoToTerraform(redundancy *fabricv4.PortRedundancy) *schema.Se
 	)
 	return redundancySet
 }
+
+func bandwidthUtilizationGoToTerraform(utilization *fabricv4.BandwidthUtilization) []map[string]interface{} {
+	if utilization == nil {
+		return nil
+	}
+	mappedUtilization := map[string]interface{}{
+		"unit":            string(utilization.GetUnit()),
+		"metric_interval": utilization.GetMetricInterval(),
+		"inbound":         statisticsDirectionGoToTerraform(utilization.Inbound),
+		"outbound":        statisticsDirectionGoToTerraform(utilization.Outbound),
+	}
+	return []map[string]interface{}{mappedUtilization}
+}
+
+func statisticsDirectionGoToTerraform(direction *fabricv4.Direction) []map[string]interface{} {
+	if direction == nil {
+		return nil
+	}
+	metrics := make([]map[string]interface{}, len(direction.Metrics))
+	for index, metric := range direction.Metrics {
+		mappedMetric := map[string]interface{}{
+			"max":  float64(metric.GetMax()),
+			"mean": float64(metric.GetMean()),
+		}
+		if timestamp, ok := metric.GetIntervalEndTimestampOk(); ok {
+			mappedMetric["interval_end_timestamp"] = timestamp.Format(time.RFC3339)
+		}
+		metrics[index] = mappedMetric
+	}
+	mappedDirection := map[string]interface{}{
+		"max":     float64(direction.GetMax()),
+		"mean":    float64(direction.GetMean()),
+		"metrics": metrics,
+	}
+	return []map[string]interface{}{mappedDirection}
+}
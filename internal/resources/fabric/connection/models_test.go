@@ -0,0 +1,77 @@
+package connection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/fabricv4"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSetConnectionStatisticsData(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	intervalEnd := start.Add(5 * time.Minute)
+	unit := fabricv4.BANDWIDTHUTILIZATIONUNIT_MBPS
+	var inboundMax, inboundMean, outboundMax float32 = 40, 12.5, 8
+	stats := &fabricv4.Statistics{
+		StartDateTime: &start,
+		EndDateTime:   &end,
+		BandwidthUtilization: &fabricv4.BandwidthUtilization{
+			Unit:           &unit,
+			MetricInterval: fabricv4.PtrString("PT5M"),
+			Inbound: &fabricv4.Direction{
+				Max:  &inboundMax,
+				Mean: &inboundMean,
+				Metrics: []fabricv4.Metrics{
+					{IntervalEndTimestamp: &intervalEnd, Max: &inboundMax, Mean: &inboundMean},
+				},
+			},
+			Outbound: &fabricv4.Direction{Max: &outboundMax},
+		},
+	}
+
+	d := schema.TestResourceDataRaw(t, readFabricConnectionStatisticsSchema(), map[string]interface{}{
+		"connection_id": "e5d71a2c-b4f0-4e7e-8b9a-8e4317d1f6c0",
+	})
+	if diags := setConnectionStatisticsData(d, stats, end.Add(-24*time.Hour), end); diags.HasError() {
+		t.Fatalf("setConnectionStatisticsData() = %v", diags)
+	}
+
+	want := map[string]interface{}{
+		"start_date_time":                                                    "2024-05-01T00:00:00Z",
+		"end_date_time":                                                      "2024-05-01T01:00:00Z",
+		"bandwidth_utilization.0.unit":                                       "Mbps",
+		"bandwidth_utilization.0.metric_interval":                            "PT5M",
+		"bandwidth_utilization.0.inbound.0.max":                              40.,
+		"bandwidth_utilization.0.inbound.0.mean":                             12.5,
+		"bandwidth_utilization.0.inbound.0.metrics.0.interval_end_timestamp": "2024-05-01T00:05:00Z",
+		"bandwidth_utilization.0.outbound.0.max":                             8.,
+		"bandwidth_utilization.0.outbound.0.mean":                            0.,
+	}
+	for key, value := range want {
+		if got := d.Get(key); got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+}
+
+func TestSimplifiedServiceProfileGoToTerraform(t *testing.T) {
+	profile := &fabricv4.SimplifiedServiceProfile{
+		Href: fabricv4.PtrString("https://api.equinix.com/fabric/v4/serviceProfiles/1c8e8c9a-4a4b-4e0b-9c59-0c4a3d1e2f5b"),
+		Uuid: fabricv4.PtrString("1c8e8c9a-4a4b-4e0b-9c59-0c4a3d1e2f5b"),
+		Name: fabricv4.PtrString("AWS Direct Connect"),
+	}
+
+	profiles := simplifiedServiceProfileGoToTerraform(profile).List()
+	if len(profiles) != 1 {
+		t.Fatalf("simplifiedServiceProfileGoToTerraform() = %v, want one profile", profiles)
+	}
+	mapped := profiles[0].(map[string]interface{})
+	if got, want := mapped["uuid"], profile.GetUuid(); got != want {
+		t.Errorf("uuid = %v, want %v", got, want)
+	}
+	if got, want := mapped["name"], profile.GetName(); got != want {
+		t.Errorf("name = %v, want %v", got, want)
+	}
+}
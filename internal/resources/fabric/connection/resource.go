@@ -10,9 +10,11 @@ import (
 	"github.com/equinix/terraform-provider-equinix/internal/config"
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	equinix_fabric_schema "github.com/equinix/terraform-provider-equinix/internal/fabric/schema"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
 	"github.com/equinix/equinix-sdk-go/services/fabricv4"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -33,6 +35,10 @@ func Resource() *schema.Resource {
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: fabricConnectionResourceSchema(),
+		CustomizeDiff: customdiff.Sequence(
+			customizeDiffFabricConnectionSpeed,
+			customizeDiffFabricConnectionBandwidth,
+		),
 
 		Description: "Fabric V4 API compatible resource allows creation and management of Equinix Fabric connection",
 	}
@@ -79,6 +85,13 @@ func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData,
 	connectionZSide := connectionSideTerraformToGo(zSide)
 	createConnectionRequest.SetZSide(connectionZSide)
 
+	if err := validateVirtualDeviceInterface(meta.(*config.Config).Ne, "a_side", connectionASide); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := validateVirtualDeviceInterface(meta.(*config.Config).Ne, "z_side", connectionZSide); err != nil {
+		return diag.FromErr(err)
+	}
+
 	additionalInfoTerraConfig, ok := d.GetOk("additional_info")
 	if ok {
 		zSideAccessPoint := connectionZSide.GetAccessPoint()
@@ -99,7 +112,7 @@ func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData,
 	}
 	d.SetId(conn.GetUuid())
 
-	createTimeout := d.Timeout(schema.TimeoutCreate) - 30*time.Second - time.Since(start)
+	createTimeout := wait.Remaining(d.Timeout(schema.TimeoutCreate), start)
 	if err = waitUntilConnectionIsCreated(d.Id(), meta, d, ctx, createTimeout); err != nil {
 		return diag.Errorf("error waiting for connection (%s) to be created: %s", d.Id(), err)
 	}
@@ -119,7 +132,7 @@ func resourceFabricConnectionCreate(ctx context.Context, d *schema.ResourceData,
 			return diag.FromErr(equinix_errors.FormatFabricError(patchErr))
 		}
 
-		createTimeout := d.Timeout(schema.TimeoutCreate) - 30*time.Second - time.Since(start)
+		createTimeout := wait.Remaining(d.Timeout(schema.TimeoutCreate), start)
 		if _, statusChangeErr := waitForConnectionProviderStatusChange(d.Id(), meta, d, ctx, createTimeout); statusChangeErr != nil {
 			return diag.Errorf("error waiting for AWS Approval for connection %s: %v", d.Id(), statusChangeErr)
 		}
@@ -178,7 +191,7 @@ func resourceFabricConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 			waitFunction = waitForConnectionProviderStatusChange
 		}
 
-		updateTimeout := d.Timeout(schema.TimeoutUpdate) - 30*time.Second - time.Since(start)
+		updateTimeout := wait.Remaining(d.Timeout(schema.TimeoutUpdate), start)
 		conn, err := waitFunction(d.Id(), meta, d, ctx, updateTimeout)
 
 		if err != nil {
@@ -194,28 +207,22 @@ func resourceFabricConnectionUpdate(ctx context.Context, d *schema.ResourceData,
 
 func waitForConnectionUpdateCompletion(uuid string, meta interface{}, d *schema.ResourceData, ctx context.Context, timeout time.Duration) (*fabricv4.Connection, error) {
 	log.Printf("[DEBUG] Waiting for connection update to complete, uuid %s", uuid)
-	stateConf := &retry.StateChangeConf{
-		Target: []string{"COMPLETED", "SUBMITTED_FOR_APPROVAL"},
-		Refresh: func() (interface{}, string, error) {
-			client := meta.(*config.Config).NewFabricClientForSDK(d)
-			dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
-			if err != nil {
-				return "", "", equinix_errors.FormatFabricError(err)
-			}
-			updatableState := ""
-			change := dbConn.GetChange()
-			status := change.GetStatus()
-			if string(status) == "COMPLETED" {
-				updatableState = string(status)
-			}
-			return dbConn, updatableState, nil
-		},
-		Timeout:    timeout,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+	refresh := func() (interface{}, string, error) {
+		client := meta.(*config.Config).NewFabricClientForSDK(d)
+		dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
+		if err != nil {
+			return "", "", equinix_errors.FormatFabricError(err)
+		}
+		updatableState := ""
+		change := dbConn.GetChange()
+		status := change.GetStatus()
+		if string(status) == "COMPLETED" {
+			updatableState = string(status)
+		}
+		return dbConn, updatableState, nil
 	}
-
-	inter, err := stateConf.WaitForStateContext(ctx)
+	// without pending states, any other state keeps the waiter polling
+	inter, err := wait.ForState(ctx, refresh, nil, []string{"COMPLETED", "SUBMITTED_FOR_APPROVAL"}, timeout, connectionWaitOpts...)
 	var dbConn *fabricv4.Connection
 
 	if err == nil {
@@ -226,59 +233,38 @@ func waitForConnectionUpdateCompletion(uuid string, meta interface{}, d *schema.
 
 func waitUntilConnectionIsCreated(uuid string, meta interface{}, d *schema.ResourceData, ctx context.Context, timeout time.Duration) error {
 	log.Printf("Waiting for connection to be created, uuid %s", uuid)
-	stateConf := &retry.StateChangeConf{
-		Pending: []string{
-			string(fabricv4.CONNECTIONSTATE_PROVISIONING),
-		},
-		Target: []string{
-			string(fabricv4.CONNECTIONSTATE_PENDING),
-			string(fabricv4.CONNECTIONSTATE_PROVISIONED),
-			string(fabricv4.CONNECTIONSTATE_ACTIVE),
-		},
-		Refresh: func() (interface{}, string, error) {
-			client := meta.(*config.Config).NewFabricClientForSDK(d)
-			dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
-			if err != nil {
-				return "", "", equinix_errors.FormatFabricError(err)
-			}
-			return dbConn, string(dbConn.GetState()), nil
-		},
-		Timeout:    timeout,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+	pending := []string{
+		string(fabricv4.CONNECTIONSTATE_PROVISIONING),
 	}
-
-	_, err := stateConf.WaitForStateContext(ctx)
-
+	target := []string{
+		string(fabricv4.CONNECTIONSTATE_PENDING),
+		string(fabricv4.CONNECTIONSTATE_PROVISIONED),
+		string(fabricv4.CONNECTIONSTATE_ACTIVE),
+	}
+	_, err := wait.ForState(ctx, connectionStateRefreshFunc(ctx, uuid, meta, d), pending, target, timeout, connectionWaitOpts...)
 	return err
 }
 
 func waitForConnectionProviderStatusChange(uuid string, meta interface{}, d *schema.ResourceData, ctx context.Context, timeout time.Duration) (*fabricv4.Connection, error) {
 	log.Printf("DEBUG: wating for provider status to update. Connection uuid: %s", uuid)
-	stateConf := &retry.StateChangeConf{
-		Pending: []string{
-			string(fabricv4.PROVIDERSTATUS_PENDING_APPROVAL),
-			string(fabricv4.PROVIDERSTATUS_PROVISIONING),
-		},
-		Target: []string{
-			string(fabricv4.PROVIDERSTATUS_PROVISIONED),
-		},
-		Refresh: func() (interface{}, string, error) {
-			client := meta.(*config.Config).NewFabricClientForSDK(d)
-			dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
-			if err != nil {
-				return "", "", equinix_errors.FormatFabricError(err)
-			}
-			operation := dbConn.GetOperation()
-			providerStatus := operation.GetProviderStatus()
-			return dbConn, string(providerStatus), nil
-		},
-		Timeout:    timeout,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+	pending := []string{
+		string(fabricv4.PROVIDERSTATUS_PENDING_APPROVAL),
+		string(fabricv4.PROVIDERSTATUS_PROVISIONING),
 	}
-
-	inter, err := stateConf.WaitForStateContext(ctx)
+	target := []string{
+		string(fabricv4.PROVIDERSTATUS_PROVISIONED),
+	}
+	refresh := func() (interface{}, string, error) {
+		client := meta.(*config.Config).NewFabricClientForSDK(d)
+		dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
+		if err != nil {
+			return "", "", equinix_errors.FormatFabricError(err)
+		}
+		operation := dbConn.GetOperation()
+		providerStatus := operation.GetProviderStatus()
+		return dbConn, string(providerStatus), nil
+	}
+	inter, err := wait.ForState(ctx, refresh, pending, target, timeout, connectionWaitOpts...)
 	var dbConn *fabricv4.Connection
 
 	if err == nil {
@@ -289,26 +275,12 @@ func waitForConnectionProviderStatusChange(uuid string, meta interface{}, d *sch
 
 func verifyConnectionCreated(uuid string, meta interface{}, d *schema.ResourceData, ctx context.Context, timeout time.Duration) (*fabricv4.Connection, error) {
 	log.Printf("Waiting for connection to be in created state, uuid %s", uuid)
-	stateConf := &retry.StateChangeConf{
-		Target: []string{
-			string(fabricv4.CONNECTIONSTATE_ACTIVE),
-			string(fabricv4.CONNECTIONSTATE_PROVISIONED),
-			string(fabricv4.CONNECTIONSTATE_PENDING),
-		},
-		Refresh: func() (interface{}, string, error) {
-			client := meta.(*config.Config).NewFabricClientForSDK(d)
-			dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
-			if err != nil {
-				return "", "", equinix_errors.FormatFabricError(err)
-			}
-			return dbConn, string(dbConn.GetState()), nil
-		},
-		Timeout:    timeout,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+	target := []string{
+		string(fabricv4.CONNECTIONSTATE_ACTIVE),
+		string(fabricv4.CONNECTIONSTATE_PROVISIONED),
+		string(fabricv4.CONNECTIONSTATE_PENDING),
 	}
-
-	inter, err := stateConf.WaitForStateContext(ctx)
+	inter, err := wait.ForState(ctx, connectionStateRefreshFunc(ctx, uuid, meta, d), nil, target, timeout, connectionWaitOpts...)
 	var dbConn *fabricv4.Connection
 
 	if err == nil {
@@ -334,7 +306,7 @@ func resourceFabricConnectionDelete(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(equinix_errors.FormatFabricError(err))
 	}
 
-	deleteTimeout := d.Timeout(schema.TimeoutDelete) - 30*time.Second - time.Since(start)
+	deleteTimeout := wait.Remaining(d.Timeout(schema.TimeoutDelete), start)
 	err = WaitUntilConnectionDeprovisioned(d.Id(), meta, d, ctx, deleteTimeout)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("API call failed while waiting for resource deletion. Error %v", err))
@@ -344,28 +316,32 @@ func resourceFabricConnectionDelete(ctx context.Context, d *schema.ResourceData,
 
 func WaitUntilConnectionDeprovisioned(uuid string, meta interface{}, d *schema.ResourceData, ctx context.Context, timeout time.Duration) error {
 	log.Printf("Waiting for connection to be deprovisioned, uuid %s", uuid)
-	stateConf := &retry.StateChangeConf{
-		Pending: []string{
-			string(fabricv4.CONNECTIONSTATE_DEPROVISIONING),
-			string(fabricv4.CONNECTIONSTATE_ACTIVE),
-			string(fabricv4.CONNECTIONSTATE_PENDING),
-		},
-		Target: []string{
-			string(fabricv4.CONNECTIONSTATE_DEPROVISIONED),
-		},
-		Refresh: func() (interface{}, string, error) {
-			client := meta.(*config.Config).NewFabricClientForSDK(d)
-			dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
-			if err != nil {
-				return "", "", equinix_errors.FormatFabricError(err)
-			}
-			return dbConn, string(dbConn.GetState()), nil
-		},
-		Timeout:    timeout,
-		Delay:      30 * time.Second,
-		MinTimeout: 30 * time.Second,
+	pending := []string{
+		string(fabricv4.CONNECTIONSTATE_DEPROVISIONING),
+		string(fabricv4.CONNECTIONSTATE_ACTIVE),
+		string(fabricv4.CONNECTIONSTATE_PENDING),
 	}
-
-	_, err := stateConf.WaitForStateContext(ctx)
+	target := []string{
+		string(fabricv4.CONNECTIONSTATE_DEPROVISIONED),
+	}
+	_, err := wait.ForState(ctx, connectionStateRefreshFunc(ctx, uuid, meta, d), pending, target, timeout, connectionWaitOpts...)
 	return err
 }
+
+// connectionWaitOpts poll connections less often than the default, as their
+// provisioning is handled by the Fabric and takes minutes rather than seconds
+var connectionWaitOpts = []wait.Option{
+	wait.WithDelay(30 * time.Second),
+	wait.WithMinTimeout(30 * time.Second),
+}
+
+func connectionStateRefreshFunc(ctx context.Context, uuid string, meta interface{}, d *schema.ResourceData) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		client := meta.(*config.Config).NewFabricClientForSDK(d)
+		dbConn, _, err := client.ConnectionsApi.GetConnectionByUuid(ctx, uuid).Execute()
+		if err != nil {
+			return "", "", equinix_errors.FormatFabricError(err)
+		}
+		return dbConn, string(dbConn.GetState()), nil
+	}
+}
@@ -39,9 +39,20 @@ func fabricConnectionResourceSchema() map[string]*schema.Schema {
 			},
 		},
 		"bandwidth": {
-			Type:        schema.TypeInt,
-			Required:    true,
-			Description: "Connection bandwidth in Mbps",
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"bandwidth", "speed"},
+			Description:  "Connection bandwidth in Mbps. One of bandwidth or speed is required",
+		},
+		"speed": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ExactlyOneOf:     []string{"bandwidth", "speed"},
+			ValidateFunc:     validateSpeed,
+			DiffSuppressFunc: suppressEquivalentSpeed,
+			Description:      "Connection speed with an optional Mbps or Gbps unit, e.g. 50Mbps, 1Gbps or 10000 (Mbps). Must be one of the standard connection speeds. One of bandwidth or speed is required",
 		},
 		//"geo_scope": {
 		//	Type:         schema.TypeString,
@@ -451,7 +462,7 @@ func accessPointInterface() map[string]*schema.Schema {
 			Type:        schema.TypeInt,
 			Computed:    true,
 			Optional:    true,
-			Description: "id",
+			Description: "Interface index on the virtual device. It must not exceed the interface count of the device, Fabric picks an available interface if unset",
 		},
 		"type": {
 			Type:        schema.TypeString,
@@ -289,6 +289,102 @@ func testAccFabricCreatePort2PortConnectionConfig(bandwidth int32, aSidePortUuid
 	}`, bandwidth, aSidePortUuid, zSidePortUuid)
 }
 
+func TestAccFabricUpdatePort2PortConnectionSpeed_PFCR(t *testing.T) {
+	ports := testing_helpers.GetFabricEnvPorts(t)
+	var aSidePortUuid, zSidePortUuid string
+	if len(ports) > 0 {
+		aSidePortUuid = ports["pfcr"]["dot1q"][0].GetUuid()
+		zSidePortUuid = ports["pfcr"]["dot1q"][1].GetUuid()
+	}
+	var connectionId string
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:     func() { acceptance.TestAccPreCheck(t); acceptance.TestAccPreCheckProviderConfigured(t) },
+		Providers:    acceptance.TestAccProviders,
+		CheckDestroy: CheckConnectionDelete,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFabricCreatePort2PortConnectionSpeedConfig("50Mbps", aSidePortUuid, zSidePortUuid),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"equinix_fabric_connection.test", "speed", "50Mbps"),
+					resource.TestCheckResourceAttr(
+						"equinix_fabric_connection.test", "bandwidth", "50"),
+					testAccFabricConnectionId("equinix_fabric_connection.test", &connectionId),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccFabricCreatePort2PortConnectionSpeedConfig("100Mbps", aSidePortUuid, zSidePortUuid),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"equinix_fabric_connection.test", "speed", "100Mbps"),
+					resource.TestCheckResourceAttr(
+						"equinix_fabric_connection.test", "bandwidth", "100"),
+					resource.TestCheckResourceAttrPtr(
+						"equinix_fabric_connection.test", "id", &connectionId),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccFabricConnectionId(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("not found: %s", name)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+func testAccFabricCreatePort2PortConnectionSpeedConfig(speed, aSidePortUuid, zSidePortUuid string) string {
+	return fmt.Sprintf(`resource "equinix_fabric_connection" "test" {
+		type = "EVPL_VC"
+		name = "port_speed_test_PFCR"
+		notifications{
+			type = "ALL"
+			emails = ["test@equinix.com","test1@equinix.com"]
+		}
+		order {
+			purchase_order_number = "1-129105284100"
+		}
+		speed = "%s"
+		a_side {
+			access_point {
+				type = "COLO"
+				port {
+				 uuid = "%s"
+				}
+				link_protocol {
+					type= "DOT1Q"
+					vlan_tag= 2399
+				}
+				location {
+					metro_code = "SV"
+				}
+			}
+		}
+		z_side {
+			access_point {
+				type = "COLO"
+				port{
+				 uuid = "%s"
+				}
+				link_protocol {
+					type= "DOT1Q"
+					vlan_tag= 2400
+				}
+				location {
+					metro_code= "SV"
+				}
+			}
+		}
+	}`, speed, aSidePortUuid, zSidePortUuid)
+}
+
 func TestAccFabricCreateCloudRouter2PortConnection_PFCR(t *testing.T) {
 	ports := testing_helpers.GetFabricEnvPorts(t)
 	var portUuid string
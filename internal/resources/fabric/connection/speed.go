@@ -0,0 +1,95 @@
+package connection
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var (
+	// speedFormat matches speeds such as 50Mbps, 1Gbps or 10000, bare numbers are in Mbps
+	speedFormat = regexp.MustCompile(`(?i)^(\d+)\s*(mbps|gbps)?$`)
+
+	// speedTiers are the standard connection bandwidths in Mbps
+	speedTiers = []int{50, 100, 200, 500, 1000, 2000, 5000, 10000, 25000, 50000, 100000}
+)
+
+// parseSpeed converts a speed with an optional Mbps or Gbps unit to Mbps
+func parseSpeed(speed string) (int, error) {
+	match := speedFormat.FindStringSubmatch(strings.TrimSpace(speed))
+	if match == nil {
+		return 0, fmt.Errorf("invalid speed %q, must be a number of Mbps optionally followed by a Mbps or Gbps unit, e.g. 50Mbps, 1Gbps or 10000", speed)
+	}
+	value, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid speed %q: %w", speed, err)
+	}
+	if strings.EqualFold(match[2], "gbps") {
+		value *= 1000
+	}
+	return value, nil
+}
+
+// formatSpeed returns the speed in Gbps when it is a whole number of Gbps and in Mbps otherwise
+func formatSpeed(mbps int) string {
+	if mbps >= 1000 && mbps%1000 == 0 {
+		return strconv.Itoa(mbps/1000) + "Gbps"
+	}
+	return strconv.Itoa(mbps) + "Mbps"
+}
+
+func validateSpeed(v interface{}, k string) ([]string, []error) {
+	mbps, err := parseSpeed(v.(string))
+	if err != nil {
+		return nil, []error{err}
+	}
+	if !slices.Contains(speedTiers, mbps) {
+		valid := make([]string, len(speedTiers))
+		for i, tier := range speedTiers {
+			valid[i] = formatSpeed(tier)
+		}
+		return nil, []error{fmt.Errorf("%q is not a valid %s, valid speeds are %s; use bandwidth for custom bandwidths", v, k, strings.Join(valid, ", "))}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentSpeed ignores differences in how the same speed is written, e.g. 1Gbps and 1000
+func suppressEquivalentSpeed(k, old, new string, d *schema.ResourceData) bool {
+	oldMbps, oldErr := parseSpeed(old)
+	newMbps, newErr := parseSpeed(new)
+	return oldErr == nil && newErr == nil && oldMbps == newMbps
+}
+
+// customizeDiffFabricConnectionSpeed plans bandwidth from a configured speed,
+// and marks speed as unknown when bandwidth is changed directly
+func customizeDiffFabricConnectionSpeed(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+
+	speed := raw.GetAttr("speed")
+	if speed.IsNull() {
+		if d.HasChange("bandwidth") {
+			return d.SetNewComputed("speed")
+		}
+		return nil
+	}
+	if !speed.IsKnown() {
+		return d.SetNewComputed("bandwidth")
+	}
+
+	mbps, err := parseSpeed(speed.AsString())
+	if err != nil {
+		return err
+	}
+	if d.Get("bandwidth").(int) != mbps {
+		return d.SetNew("bandwidth", mbps)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package connection
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSpeed(t *testing.T) {
+	tests := []struct {
+		speed   string
+		want    int
+		wantErr bool
+	}{
+		{speed: "50Mbps", want: 50},
+		{speed: "1Gbps", want: 1000},
+		{speed: "10 gbps", want: 10000},
+		{speed: "10000", want: 10000},
+		{speed: "1.5Gbps", wantErr: true},
+		{speed: "1Tbps", wantErr: true},
+		{speed: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.speed, func(t *testing.T) {
+			got, err := parseSpeed(tt.speed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSpeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseSpeed() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSpeed(t *testing.T) {
+	for mbps, want := range map[int]string{50: "50Mbps", 1000: "1Gbps", 1500: "1500Mbps", 25000: "25Gbps"} {
+		if got := formatSpeed(mbps); got != want {
+			t.Errorf("formatSpeed(%d) = %s, want %s", mbps, got, want)
+		}
+	}
+}
+
+func TestValidateSpeed(t *testing.T) {
+	if _, errs := validateSpeed("1Gbps", "speed"); len(errs) != 0 {
+		t.Errorf("validateSpeed() unexpected errors %v", errs)
+	}
+
+	_, errs := validateSpeed("300Mbps", "speed")
+	if len(errs) != 1 {
+		t.Fatalf("validateSpeed() errors = %v, want 1 error", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "50Mbps, 100Mbps, 200Mbps, 500Mbps, 1Gbps") {
+		t.Errorf("validateSpeed() error %q does not list the valid speeds", errs[0])
+	}
+}
+
+func TestSuppressEquivalentSpeed(t *testing.T) {
+	if !suppressEquivalentSpeed("speed", "1Gbps", "1000", nil) {
+		t.Error("suppressEquivalentSpeed() should suppress 1Gbps and 1000")
+	}
+	if suppressEquivalentSpeed("speed", "1Gbps", "10Gbps", nil) {
+		t.Error("suppressEquivalentSpeed() should not suppress 1Gbps and 10Gbps")
+	}
+	if suppressEquivalentSpeed("speed", "", "1Gbps", nil) {
+		t.Error("suppressEquivalentSpeed() should not suppress setting a speed")
+	}
+}
@@ -0,0 +1,47 @@
+package connection
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/equinix/equinix-sdk-go/services/fabricv4"
+	"github.com/equinix/ne-go"
+	"github.com/equinix/rest-go"
+)
+
+// validateVirtualDeviceInterface checks that the interface of a Network Edge
+// virtual device access point exists on the device. Fabric only reports an
+// out of range interface once the connection is being provisioned. An
+// interface without an ID is left for Fabric to pick. A device that can not be
+// read with the configured credentials, such as the device of another account
+// on the z_side, is left for Fabric to validate.
+func validateVirtualDeviceInterface(client ne.Client, side string, connectionSide fabricv4.ConnectionSide) error {
+	accessPoint := connectionSide.GetAccessPoint()
+	if accessPoint.GetType() != fabricv4.ACCESSPOINTTYPE_VD {
+		return nil
+	}
+	virtualDevice := accessPoint.GetVirtualDevice()
+	if virtualDevice.GetUuid() == "" {
+		return fmt.Errorf("%s access point of type VD requires a virtual_device uuid", side)
+	}
+	interface_ := accessPoint.GetInterface()
+	id := int(interface_.GetId())
+	if id <= 0 {
+		return nil
+	}
+
+	device, err := client.GetDevice(virtualDevice.GetUuid())
+	if err != nil {
+		if restErr, ok := err.(rest.Error); ok && (restErr.HTTPCode == http.StatusForbidden || restErr.HTTPCode == http.StatusNotFound) {
+			log.Printf("[DEBUG] Not validating the interface of virtual device %s of the %s access point: %v", virtualDevice.GetUuid(), side, err)
+			return nil
+		}
+		return fmt.Errorf("error reading virtual device %s of the %s access point: %w", virtualDevice.GetUuid(), side, err)
+	}
+	if device.InterfaceCount != nil && id > *device.InterfaceCount {
+		return fmt.Errorf("%s access point interface %d is out of range, virtual device %s has %d interfaces",
+			side, id, virtualDevice.GetUuid(), *device.InterfaceCount)
+	}
+	return nil
+}
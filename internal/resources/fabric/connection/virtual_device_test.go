@@ -0,0 +1,91 @@
+package connection
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/fabricv4"
+	"github.com/equinix/ne-go"
+	"github.com/equinix/rest-go"
+)
+
+type mockNEDeviceClient struct {
+	ne.Client
+	device *ne.Device
+}
+
+func (c mockNEDeviceClient) GetDevice(uuid string) (*ne.Device, error) {
+	switch uuid {
+	case "otherAccountDeviceId":
+		return nil, rest.Error{HTTPCode: http.StatusForbidden, Message: "forbidden"}
+	case "failingDeviceId":
+		return nil, rest.Error{HTTPCode: http.StatusInternalServerError, Message: "internal error"}
+	}
+	return c.device, nil
+}
+
+func TestValidateVirtualDeviceInterface(t *testing.T) {
+	vdSide := func(deviceUUID string, interfaceID int32) fabricv4.ConnectionSide {
+		accessPoint := fabricv4.AccessPoint{}
+		accessPoint.SetType(fabricv4.ACCESSPOINTTYPE_VD)
+		accessPoint.SetVirtualDevice(fabricv4.VirtualDevice{Uuid: fabricv4.PtrString(deviceUUID)})
+		accessPoint.SetInterface(fabricv4.Interface{Id: fabricv4.PtrInt32(interfaceID)})
+		return fabricv4.ConnectionSide{AccessPoint: &accessPoint}
+	}
+	portSide := fabricv4.ConnectionSide{AccessPoint: &fabricv4.AccessPoint{Type: fabricv4.ACCESSPOINTTYPE_COLO.Ptr()}}
+	client := mockNEDeviceClient{device: &ne.Device{InterfaceCount: ne.Int(10)}}
+
+	tests := []struct {
+		name    string
+		side    fabricv4.ConnectionSide
+		wantErr string
+	}{
+		{
+			name: "port access point",
+			side: portSide,
+		},
+		{
+			name: "interface in range",
+			side: vdSide("deviceId", 10),
+		},
+		{
+			name: "interface picked by Fabric",
+			side: vdSide("deviceId", 0),
+		},
+		{
+			name:    "interface out of range",
+			side:    vdSide("deviceId", 11),
+			wantErr: "out of range",
+		},
+		{
+			name: "device of another account",
+			side: vdSide("otherAccountDeviceId", 11),
+		},
+		{
+			name:    "device read failed",
+			side:    vdSide("failingDeviceId", 1),
+			wantErr: "error reading virtual device",
+		},
+		{
+			name:    "no device",
+			side:    vdSide("", 1),
+			wantErr: "requires a virtual_device uuid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVirtualDeviceInterface(client, "a_side", tt.side)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateVirtualDeviceInterface() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateVirtualDeviceInterface() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
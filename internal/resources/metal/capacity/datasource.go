@@ -0,0 +1,74 @@
+package capacity
+
+import (
+	"context"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{
+		BaseDataSource: framework.NewBaseDataSource(
+			framework.BaseDataSourceConfig{
+				Name: "equinix_metal_capacity",
+			},
+		),
+	}
+}
+
+type DataSource struct {
+	framework.BaseDataSource
+}
+
+func (r *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = dataSourceSchema(ctx)
+}
+
+func (r *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	// Retrieve values from config
+	var data DataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	servers, diags := data.Servers.ToSlice(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Use API client to check the capacity for all the servers at once
+	capacity, _, err := client.CapacityApi.CheckCapacityForMetro(ctx).
+		CapacityInput(metalv1.CapacityInput{Servers: expandServers(servers)}).
+		Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error checking Metal capacity",
+			equinix_errors.FriendlyError(err).Error(),
+		)
+		return
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(data.parse(ctx, servers, capacity)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update the Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
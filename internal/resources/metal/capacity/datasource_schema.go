@@ -0,0 +1,64 @@
+package capacity
+
+import (
+	"context"
+
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	fwtypes "github.com/equinix/terraform-provider-equinix/internal/framework/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func dataSourceSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": framework.IDAttributeDefaultDescription(),
+			"available": schema.BoolAttribute{
+				Description: "Whether there is enough capacity for all the servers",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"servers": schema.ListNestedBlock{
+				Description: "Servers to check the capacity for",
+				Validators: []validator.List{
+					listvalidator.IsRequired(),
+					listvalidator.SizeAtLeast(1),
+				},
+				CustomType: fwtypes.NewListNestedObjectTypeOf[ServerModel](ctx),
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"plan": schema.StringAttribute{
+							Description: "The plan slug or ID of the servers",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"metro": schema.StringAttribute{
+							Description: "The metro code or ID to check the capacity in",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"quantity": schema.Int64Attribute{
+							Description: "The number of servers to check the capacity for",
+							Required:    true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"available": schema.BoolAttribute{
+							Description: "Whether there is enough capacity in the metro for the quantity of servers",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
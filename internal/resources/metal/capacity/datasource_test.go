@@ -0,0 +1,55 @@
+package capacity_test
+
+import (
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceMetalCapacity_basic(t *testing.T) {
+	datasourceName := "data.equinix_metal_capacity.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheckMetal(t) },
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMetalCapacityConfig_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						datasourceName, "available"),
+					resource.TestCheckResourceAttr(
+						datasourceName, "servers.#", "2"),
+					resource.TestCheckResourceAttr(
+						datasourceName, "servers.0.plan", "c3.small.x86"),
+					resource.TestCheckResourceAttrSet(
+						datasourceName, "servers.0.available"),
+					// nobody has this many servers available in a metro
+					resource.TestCheckResourceAttr(
+						datasourceName, "servers.1.available", "false"),
+					resource.TestCheckResourceAttr(
+						datasourceName, "available", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceMetalCapacityConfig_basic() string {
+	return `
+data "equinix_metal_capacity" "test" {
+  servers {
+    plan     = "c3.small.x86"
+    metro    = "sv"
+    quantity = 1
+  }
+
+  servers {
+    plan     = "c3.small.x86"
+    metro    = "sv"
+    quantity = 100000
+  }
+}
+`
+}
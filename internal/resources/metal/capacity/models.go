@@ -0,0 +1,67 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	fwtypes "github.com/equinix/terraform-provider-equinix/internal/framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type DataSourceModel struct {
+	ID        types.String                                 `tfsdk:"id"`
+	Available types.Bool                                   `tfsdk:"available"`
+	Servers   fwtypes.ListNestedObjectValueOf[ServerModel] `tfsdk:"servers"`
+}
+
+type ServerModel struct {
+	Plan      types.String `tfsdk:"plan"`
+	Metro     types.String `tfsdk:"metro"`
+	Quantity  types.Int64  `tfsdk:"quantity"`
+	Available types.Bool   `tfsdk:"available"`
+}
+
+func expandServers(servers []*ServerModel) []metalv1.ServerInfo {
+	infos := make([]metalv1.ServerInfo, 0, len(servers))
+	for _, server := range servers {
+		infos = append(infos, metalv1.ServerInfo{
+			Plan:     server.Plan.ValueStringPointer(),
+			Metro:    server.Metro.ValueStringPointer(),
+			Quantity: metalv1.PtrString(strconv.FormatInt(server.Quantity.ValueInt64(), 10)),
+		})
+	}
+	return infos
+}
+
+// parse sets the availability of each of the requested servers. The API
+// reports the servers in the order they were sent.
+func (m *DataSourceModel) parse(ctx context.Context, servers []*ServerModel, capacity *metalv1.CapacityCheckPerMetroList) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	infos := capacity.GetServers()
+	if len(infos) != len(servers) {
+		diags.AddError(
+			"Error checking Metal capacity",
+			fmt.Sprintf("capacity was reported for %d servers, expected %d", len(infos), len(servers)),
+		)
+		return diags
+	}
+
+	ids := make([]string, 0, len(servers))
+	available := true
+	for i, server := range servers {
+		server.Available = types.BoolValue(infos[i].GetAvailable())
+		available = available && infos[i].GetAvailable()
+		ids = append(ids, fmt.Sprintf("%s:%s:%d", server.Metro.ValueString(), server.Plan.ValueString(), server.Quantity.ValueInt64()))
+	}
+
+	m.ID = types.StringValue(strings.Join(ids, ","))
+	m.Available = types.BoolValue(available)
+	m.Servers = fwtypes.NewListNestedObjectValueOfSlice(ctx, servers)
+
+	return diags
+}
@@ -0,0 +1,69 @@
+package capacity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDataSourceModel_parse(t *testing.T) {
+	ctx := context.Background()
+
+	newServers := func() []*ServerModel {
+		return []*ServerModel{
+			{Plan: types.StringValue("c3.small.x86"), Metro: types.StringValue("sv"), Quantity: types.Int64Value(1)},
+			{Plan: types.StringValue("m3.large.x86"), Metro: types.StringValue("da"), Quantity: types.Int64Value(5)},
+		}
+	}
+
+	infos := expandServers(newServers())
+	if got := infos[1].GetQuantity(); got != "5" {
+		t.Errorf("expandServers() quantity = %q, want %q", got, "5")
+	}
+
+	tests := []struct {
+		name          string
+		available     []bool
+		wantAvailable bool
+		wantErr       bool
+	}{
+		{name: "all available", available: []bool{true, true}, wantAvailable: true},
+		{name: "one unavailable", available: []bool{true, false}, wantAvailable: false},
+		{name: "missing servers", available: []bool{true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capacity := &metalv1.CapacityCheckPerMetroList{}
+			for _, available := range tt.available {
+				capacity.Servers = append(capacity.Servers, metalv1.CapacityCheckPerMetroInfo{Available: metalv1.PtrBool(available)})
+			}
+
+			var m DataSourceModel
+			servers := newServers()
+			diags := m.parse(ctx, servers, capacity)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("parse() diags = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := m.Available.ValueBool(); got != tt.wantAvailable {
+				t.Errorf("parse() available = %v, want %v", got, tt.wantAvailable)
+			}
+			if got, want := m.ID.ValueString(), "sv:c3.small.x86:1,da:m3.large.x86:5"; got != want {
+				t.Errorf("parse() id = %q, want %q", got, want)
+			}
+
+			parsed, _ := m.Servers.ToSlice(ctx)
+			for i, server := range parsed {
+				if got := server.Available.ValueBool(); got != tt.available[i] {
+					t.Errorf("parse() servers[%d].available = %v, want %v", i, got, tt.available[i])
+				}
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	fwtypes "github.com/equinix/terraform-provider-equinix/internal/framework/types"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -36,6 +37,7 @@ type ResourceModel struct {
 	Token             types.String                                       `tfsdk:"token"`
 	Ports             fwtypes.ListNestedObjectValueOf[PortModel]         `tfsdk:"ports"`          // List of Port
 	ServiceTokens     fwtypes.ListNestedObjectValueOf[ServiceTokenModel] `tfsdk:"service_tokens"` // List of ServiceToken
+	Timeouts          timeouts.Value                                     `tfsdk:"timeouts"`
 }
 
 type DataSourceModel struct {
@@ -4,28 +4,35 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 )
 
 func NewResource() resource.Resource {
-	return &Resource{
+	r := Resource{
 		BaseResource: framework.NewBaseResource(
 			framework.BaseResourceConfig{
 				Name: "equinix_metal_connection",
 			},
 		),
 	}
+	r.SetDefaultDeleteTimeout(10 * time.Minute)
+
+	return &r
 }
 
 type Resource struct {
 	framework.BaseResource
+	framework.WithTimeouts
 }
 
 func (r *Resource) Schema(
@@ -33,7 +40,14 @@ func (r *Resource) Schema(
 	req resource.SchemaRequest,
 	resp *resource.SchemaResponse,
 ) {
-	resp.Schema = resourceSchema(ctx)
+	s := resourceSchema(ctx)
+	if s.Blocks == nil {
+		s.Blocks = make(map[string]schema.Block)
+	}
+	s.Blocks["timeouts"] = timeouts.Block(ctx, timeouts.Opts{
+		Delete: true,
+	})
+	resp.Schema = s
 }
 
 func (r *Resource) Create(
@@ -163,6 +177,7 @@ func (r *Resource) Read(
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(serviceTokenExpiryDiagnostics(conn, time.Now())...)
 
 	// Update the Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -259,6 +274,19 @@ func (r *Resource) Delete(
 	// Extract the ID of the resource from the state
 	id := state.ID.ValueString()
 
+	// The virtual circuits of shared connections are deleted with the
+	// connection, the ones of dedicated connections must be deleted first
+	if state.Type.ValueString() == string(metalv1.INTERCONNECTIONTYPE_DEDICATED) {
+		metalClient := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+		if err := waitForConnectionVirtualCircuits(ctx, metalClient, id, r.DeleteTimeout(ctx, state.Timeouts)); err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Failed to delete Metal Connection %s", id),
+				err.Error(),
+			)
+			return
+		}
+	}
+
 	// API call to delete the Metal Connection
 	deleteResp, err := client.Connections.Delete(id, true)
 	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(deleteResp, err) != nil {
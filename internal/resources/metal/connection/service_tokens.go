@@ -0,0 +1,47 @@
+package connection
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// serviceTokenExpiryWarning is how long before a service token expires the
+// connection starts reporting it
+const serviceTokenExpiryWarning = 7 * 24 * time.Hour
+
+// serviceTokenExpiryDiagnostics warns about the service tokens of a connection
+// that is not active yet when they have expired or expire soon. The Metal API
+// cannot reissue the service tokens of a connection, so the warning tells the
+// user to replace the connection while the tokens are still needed.
+func serviceTokenExpiryDiagnostics(conn *metalv1.Interconnection, now time.Time) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if conn.GetStatus() == "active" {
+		// the tokens have been redeemed in Fabric already
+		return diags
+	}
+
+	id := conn.GetId()
+	for _, token := range conn.GetServiceTokens() {
+		if token.ExpiresAt == nil {
+			continue
+		}
+		expiresAt := token.GetExpiresAt()
+		if expiresAt.Sub(now) > serviceTokenExpiryWarning {
+			continue
+		}
+
+		detail := fmt.Sprintf("Service token %s of Metal connection %s expires at %s.", token.GetId(), id, expiresAt.Format(time.RFC3339))
+		if !expiresAt.After(now) {
+			detail = fmt.Sprintf("Service token %s of Metal connection %s expired at %s.", token.GetId(), id, expiresAt.Format(time.RFC3339))
+		}
+		diags.AddWarning(
+			"Metal connection service token expiring",
+			detail+" The service tokens of a connection cannot be reissued, replace the connection, "+
+				"e.g. with terraform apply -replace, to get new tokens if it still has to be set up in Fabric.",
+		)
+	}
+	return diags
+}
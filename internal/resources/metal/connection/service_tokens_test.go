@@ -0,0 +1,75 @@
+package connection
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+func TestServiceTokenExpiryDiagnostics(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newConn := func(status string, expiresIn time.Duration) *metalv1.Interconnection {
+		return &metalv1.Interconnection{
+			Id:     metalv1.PtrString("connId"),
+			Status: metalv1.PtrString(status),
+			ServiceTokens: []metalv1.FabricServiceToken{
+				{Id: metalv1.PtrString("tokenId"), ExpiresAt: metalv1.PtrTime(now.Add(expiresIn))},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		conn       *metalv1.Interconnection
+		wantDetail string
+	}{
+		{
+			name: "far from expiry",
+			conn: newConn("pending", 30*24*time.Hour),
+		},
+		{
+			name:       "expiring",
+			conn:       newConn("pending", 24*time.Hour),
+			wantDetail: "Service token tokenId of Metal connection connId expires at 2024-05-02T12:00:00Z.",
+		},
+		{
+			name:       "expired",
+			conn:       newConn("pending", -time.Hour),
+			wantDetail: "Service token tokenId of Metal connection connId expired at 2024-05-01T11:00:00Z.",
+		},
+		{
+			name: "active connection",
+			conn: newConn("active", -time.Hour),
+		},
+		{
+			name: "no expiry",
+			conn: &metalv1.Interconnection{
+				Status:        metalv1.PtrString("pending"),
+				ServiceTokens: []metalv1.FabricServiceToken{{Id: metalv1.PtrString("tokenId")}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := serviceTokenExpiryDiagnostics(tt.conn, now)
+			if diags.HasError() {
+				t.Fatalf("serviceTokenExpiryDiagnostics() unexpected errors %v", diags)
+			}
+			if tt.wantDetail == "" {
+				if len(diags) != 0 {
+					t.Errorf("serviceTokenExpiryDiagnostics() = %v, want no warnings", diags)
+				}
+				return
+			}
+			if len(diags) != 1 {
+				t.Fatalf("serviceTokenExpiryDiagnostics() = %v, want 1 warning", diags)
+			}
+			if got := diags[0].Detail(); !strings.HasPrefix(got, tt.wantDetail) {
+				t.Errorf("serviceTokenExpiryDiagnostics() detail = %q, want prefix %q", got, tt.wantDetail)
+			}
+		})
+	}
+}
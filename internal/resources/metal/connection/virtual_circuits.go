@@ -0,0 +1,57 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// waitForConnectionVirtualCircuits waits for the virtual circuits on the ports
+// of the connection to be deleted, e.g. by resources that are destroyed in
+// parallel, and returns an error listing the ones that remain at the timeout
+func waitForConnectionVirtualCircuits(ctx context.Context, client *metalv1.APIClient, id string, timeout time.Duration) error {
+	var vcIDs []string
+	refresh := func() (interface{}, string, error) {
+		conn, resp, err := client.InterconnectionsApi.GetInterconnection(ctx, id).Execute()
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return id, "detached", nil
+			}
+			return nil, "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+		vcIDs = connectionVirtualCircuitIDs(conn)
+		if len(vcIDs) > 0 {
+			return conn, "attached", nil
+		}
+		return conn, "detached", nil
+	}
+
+	_, err := wait.ForState(ctx, refresh, []string{"attached"}, []string{"detached"}, timeout, wait.WithDelay(0), wait.WithMinTimeout(5*time.Second))
+	var timeoutErr *retry.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return fmt.Errorf("connection %s still has virtual circuits %s, delete them before the connection", id, strings.Join(vcIDs, ", "))
+	}
+	return err
+}
+
+// connectionVirtualCircuitIDs returns the IDs of the virtual circuits on all
+// the ports of the connection
+func connectionVirtualCircuitIDs(conn *metalv1.Interconnection) []string {
+	var ids []string
+	for _, p := range conn.GetPorts() {
+		for _, vc := range p.GetVirtualCircuits() {
+			if vc, ok := vc.GetActualInstance().(abstractVirtualCircuit); ok {
+				ids = append(ids, vc.GetId())
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,77 @@
+package connection
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+)
+
+func TestWaitForConnectionVirtualCircuits(t *testing.T) {
+	const (
+		connID = "b1cf5d9d-d4b9-4bd4-8b3c-0ea2f5fbd145"
+		vcID   = "8a3b1f1e-0d4c-4a4a-9b7e-2f6c7f0b6c3d"
+	)
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{
+			name:   "no virtual circuits",
+			status: http.StatusOK,
+			body:   `{"id":"` + connID + `","ports":[{"id":"port1","virtual_circuits":[]}]}`,
+		},
+		{
+			name:    "virtual circuits remain",
+			status:  http.StatusOK,
+			body:    `{"id":"` + connID + `","ports":[{"id":"port1","virtual_circuits":[{"id":"` + vcID + `","vnid":1000}]}]}`,
+			wantErr: "still has virtual circuits " + vcID,
+		},
+		{
+			name:   "connection deleted",
+			status: http.StatusNotFound,
+			body:   `{"errors":["Not found"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || !strings.HasSuffix(r.URL.Path, "/connections/"+connID) {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer mockAPI.Close()
+
+			meta := &config.Config{
+				BaseURL: mockAPI.URL,
+				Token:   "fakeTokenForMock",
+			}
+			meta.Load(ctx)
+
+			err := waitForConnectionVirtualCircuits(ctx, meta.NewMetalClientForTesting(), connID, time.Second)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("waitForConnectionVirtualCircuits() error = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("waitForConnectionVirtualCircuits() error = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package deviceevents
+
+import (
+	"context"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{
+		BaseDataSource: framework.NewBaseDataSource(
+			framework.BaseDataSourceConfig{
+				Name: "equinix_metal_device_events",
+			},
+		),
+	}
+}
+
+type DataSource struct {
+	framework.BaseDataSource
+}
+
+func (r *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = dataSourceSchema(ctx)
+}
+
+func (r *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	// Retrieve values from config
+	var data DataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API lists the most recent events first, so a single page of the
+	// requested size holds the events to return
+	id := data.DeviceID.ValueString()
+	events, _, err := client.EventsApi.FindDeviceEvents(ctx, id).
+		PerPage(int32(data.limit())).
+		Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Metal device events",
+			"Could not read the events of Metal device with ID "+id+": "+equinix_errors.FriendlyError(err).Error(),
+		)
+		return
+	}
+
+	// Set state to fully populated data
+	data.parse(ctx, events.GetEvents())
+
+	// Update the Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,40 @@
+package deviceevents
+
+import (
+	"context"
+
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	fwtypes "github.com/equinix/terraform-provider-equinix/internal/framework/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func dataSourceSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": framework.IDAttributeDefaultDescription(),
+			"device_id": schema.StringAttribute{
+				Description: "The ID of the device to read the events of",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"limit": schema.Int64Attribute{
+				Description: "The maximum number of events to return, starting with the most recent one. Defaults to 20",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, maxLimit),
+				},
+			},
+			"events": schema.ListAttribute{
+				Description: "The most recent events of the device, newest first",
+				CustomType:  fwtypes.NewListNestedObjectTypeOf[EventModel](ctx),
+				ElementType: fwtypes.NewObjectTypeOf[EventModel](ctx),
+				Computed:    true,
+			},
+		},
+	}
+}
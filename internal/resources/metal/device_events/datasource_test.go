@@ -0,0 +1,64 @@
+package deviceevents_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceMetalDeviceEvents_basic(t *testing.T) {
+	datasourceName := "data.equinix_metal_device_events.test"
+	rs := acctest.RandString(10)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheckMetal(t) },
+		ExternalProviders:        acceptance.TestExternalProviders,
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMetalDeviceEventsConfig_basic(rs),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						datasourceName, "id",
+						"equinix_metal_device.test", "id"),
+					resource.TestCheckResourceAttr(
+						datasourceName, "events.#", "2"),
+					resource.TestCheckResourceAttrSet(
+						datasourceName, "events.0.type"),
+					resource.TestCheckResourceAttrSet(
+						datasourceName, "events.0.created_at"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceMetalDeviceEventsConfig_basic(name string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "equinix_metal_project" "test" {
+  name = "tfacc-device-events-%s"
+}
+
+resource "equinix_metal_device" "test" {
+  hostname         = "tfacc-device-events-test"
+  plan             = local.plan
+  metro            = local.metro
+  operating_system = local.os
+  billing_cycle    = "hourly"
+  project_id       = equinix_metal_project.test.id
+  termination_time = "%s"
+}
+
+data "equinix_metal_device_events" "test" {
+  device_id = equinix_metal_device.test.id
+  limit     = 2
+}
+`, acceptance.ConfAccMetalDevice_base(acceptance.Preferable_plans, acceptance.Preferable_metros, acceptance.Preferable_os),
+		name, acceptance.TestDeviceTerminationTime())
+}
@@ -0,0 +1,67 @@
+package deviceevents
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	fwtypes "github.com/equinix/terraform-provider-equinix/internal/framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 1000
+)
+
+type DataSourceModel struct {
+	ID       types.String                                `tfsdk:"id"`
+	DeviceID types.String                                `tfsdk:"device_id"`
+	Limit    types.Int64                                 `tfsdk:"limit"`
+	Events   fwtypes.ListNestedObjectValueOf[EventModel] `tfsdk:"events"`
+}
+
+type EventModel struct {
+	ID           types.String `tfsdk:"id"`
+	Type         types.String `tfsdk:"type"`
+	State        types.String `tfsdk:"state"`
+	Body         types.String `tfsdk:"body"`
+	Interpolated types.String `tfsdk:"interpolated"`
+	CreatedAt    types.String `tfsdk:"created_at"`
+}
+
+// limit returns the configured number of events, or the default when unset
+func (m *DataSourceModel) limit() int64 {
+	if m.Limit.IsNull() || m.Limit.IsUnknown() {
+		return defaultLimit
+	}
+	return m.Limit.ValueInt64()
+}
+
+// parse sets the most recent events of the device, newest first
+func (m *DataSourceModel) parse(ctx context.Context, events []metalv1.Event) {
+	sorted := make([]metalv1.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetCreatedAt().After(sorted[j].GetCreatedAt())
+	})
+	if limit := int(m.limit()); len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+
+	models := make([]EventModel, 0, len(sorted))
+	for _, event := range sorted {
+		models = append(models, EventModel{
+			ID:           types.StringValue(event.GetId()),
+			Type:         types.StringValue(event.GetType()),
+			State:        types.StringValue(event.GetState()),
+			Body:         types.StringValue(event.GetBody()),
+			Interpolated: types.StringValue(event.GetInterpolated()),
+			CreatedAt:    types.StringValue(event.GetCreatedAt().Format(time.RFC3339)),
+		})
+	}
+
+	m.ID = m.DeviceID
+	m.Events = fwtypes.NewListNestedObjectValueOfValueSlice(ctx, models)
+}
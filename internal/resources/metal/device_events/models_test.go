@@ -0,0 +1,64 @@
+package deviceevents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDataSourceModel_parse(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	newEvent := func(id string, minutes int) metalv1.Event {
+		return metalv1.Event{
+			Id:           metalv1.PtrString(id),
+			Type:         metalv1.PtrString("instance.provisioning"),
+			State:        metalv1.PtrString("success"),
+			Body:         metalv1.PtrString("Provisioning {{device}}"),
+			Interpolated: metalv1.PtrString("Provisioning tf-device"),
+			CreatedAt:    metalv1.PtrTime(created.Add(time.Duration(minutes) * time.Minute)),
+		}
+	}
+	events := []metalv1.Event{newEvent("old", 0), newEvent("new", 10), newEvent("middle", 5)}
+
+	tests := []struct {
+		name    string
+		limit   types.Int64
+		wantIDs []string
+	}{
+		{name: "default limit", limit: types.Int64Null(), wantIDs: []string{"new", "middle", "old"}},
+		{name: "limited", limit: types.Int64Value(2), wantIDs: []string{"new", "middle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := DataSourceModel{DeviceID: types.StringValue("deviceId"), Limit: tt.limit}
+			m.parse(ctx, events)
+
+			if got := m.ID.ValueString(); got != "deviceId" {
+				t.Errorf("parse() id = %q, want %q", got, "deviceId")
+			}
+			parsed, diags := m.Events.ToSlice(ctx)
+			if diags.HasError() {
+				t.Fatalf("Events.ToSlice() diags = %v", diags)
+			}
+			if len(parsed) != len(tt.wantIDs) {
+				t.Fatalf("parse() returned %d events, want %d", len(parsed), len(tt.wantIDs))
+			}
+			for i, event := range parsed {
+				if got := event.ID.ValueString(); got != tt.wantIDs[i] {
+					t.Errorf("parse() events[%d].id = %q, want %q", i, got, tt.wantIDs[i])
+				}
+			}
+			if got, want := parsed[0].CreatedAt.ValueString(), "2024-05-01T12:10:00Z"; got != want {
+				t.Errorf("parse() events[0].created_at = %q, want %q", got, want)
+			}
+			if got, want := parsed[0].Interpolated.ValueString(), "Provisioning tf-device"; got != want {
+				t.Errorf("parse() events[0].interpolated = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -32,6 +32,7 @@ func (r *DataSource) Read(
 	// Retrieve the API client from the provider metadata
 	r.Meta.AddFwModuleToMetalUserAgent(ctx, req.ProviderMeta)
 	client := r.Meta.Metal
+	metalClient := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
 
 	// Retrieve values from plan
 	var data DataSourceModel
@@ -55,8 +56,17 @@ func (r *DataSource) Read(
 		return
 	}
 
+	vcIDs, err := getGatewayVirtualCircuitIDs(ctx, metalClient, gw)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Metal Gateway",
+			"Could not read Virtual Circuits of Metal Gateway with ID "+id+": "+err.Error(),
+		)
+		return
+	}
+
 	// Set state to fully populated data
-	resp.Diagnostics.Append(data.parse(gw)...)
+	resp.Diagnostics.Append(data.parse(ctx, gw, vcIDs)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -3,6 +3,7 @@ package gateway
 import (
 	"github.com/equinix/terraform-provider-equinix/internal/framework"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var dataSourceSchema = schema.Schema{
@@ -36,5 +37,10 @@ var dataSourceSchema = schema.Schema{
 			Description: "Status of the gateway resource",
 			Computed:    true,
 		},
+		"virtual_circuit_ids": schema.ListAttribute{
+			Description: "UUIDs of the Virtual Circuits attached to the VRF of this gateway",
+			Computed:    true,
+			ElementType: types.StringType,
+		},
 	},
 }
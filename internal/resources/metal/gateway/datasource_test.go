@@ -23,6 +23,8 @@ func TestAccDataSourceMetalGateway_privateIPv4(t *testing.T) {
 						"equinix_metal_project.test", "id"),
 					resource.TestCheckResourceAttr(
 						"data.equinix_metal_gateway.test", "private_ipv4_subnet_size", "8"),
+					resource.TestCheckResourceAttr(
+						"data.equinix_metal_gateway.test", "virtual_circuit_ids.#", "0"),
 				),
 			},
 		},
@@ -1,6 +1,8 @@
 package gateway
 
 import (
+	"context"
+
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -16,10 +18,11 @@ type ResourceModel struct {
 	IPReservationID       types.String   `tfsdk:"ip_reservation_id"`
 	PrivateIPv4SubnetSize types.Int64    `tfsdk:"private_ipv4_subnet_size"`
 	State                 types.String   `tfsdk:"state"`
+	VirtualCircuitIDs     types.List     `tfsdk:"virtual_circuit_ids"` // List of strings
 	Timeouts              timeouts.Value `tfsdk:"timeouts"`
 }
 
-func (m *ResourceModel) parse(gw *packngo.MetalGateway) diag.Diagnostics {
+func (m *ResourceModel) parse(ctx context.Context, gw *packngo.MetalGateway, vcIDs []string) diag.Diagnostics {
 	// Convert Metal Gateway data to the Terraform state
 	m.ID = types.StringValue(gw.ID)
 	m.ProjectID = types.StringValue(gw.Project.ID)
@@ -39,7 +42,10 @@ func (m *ResourceModel) parse(gw *packngo.MetalGateway) diag.Diagnostics {
 
 	m.PrivateIPv4SubnetSize = calculateSubnetSize(gw.IPReservation)
 	m.State = types.StringValue(string(gw.State))
-	return nil
+
+	var diags diag.Diagnostics
+	m.VirtualCircuitIDs, diags = types.ListValueFrom(ctx, types.StringType, vcIDs)
+	return diags
 }
 
 type DataSourceModel struct {
@@ -51,9 +57,10 @@ type DataSourceModel struct {
 	IPReservationID       types.String `tfsdk:"ip_reservation_id"`
 	PrivateIPv4SubnetSize types.Int64  `tfsdk:"private_ipv4_subnet_size"`
 	State                 types.String `tfsdk:"state"`
+	VirtualCircuitIDs     types.List   `tfsdk:"virtual_circuit_ids"` // List of strings
 }
 
-func (m *DataSourceModel) parse(gw *packngo.MetalGateway) diag.Diagnostics {
+func (m *DataSourceModel) parse(ctx context.Context, gw *packngo.MetalGateway, vcIDs []string) diag.Diagnostics {
 
 	// Convert Metal Gateway data to the Terraform state
 	m.ID = types.StringValue(gw.ID)
@@ -74,7 +81,10 @@ func (m *DataSourceModel) parse(gw *packngo.MetalGateway) diag.Diagnostics {
 
 	m.PrivateIPv4SubnetSize = calculateSubnetSize(gw.IPReservation)
 	m.State = types.StringValue(string(gw.State))
-	return nil
+
+	var diags diag.Diagnostics
+	m.VirtualCircuitIDs, diags = types.ListValueFrom(ctx, types.StringType, vcIDs)
+	return diags
 }
 
 func calculateSubnetSize(ip *packngo.IPAddressReservation) basetypes.Int64Value {
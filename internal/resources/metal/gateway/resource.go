@@ -7,11 +7,14 @@ import (
 
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/packethost/packngo"
 )
 
@@ -59,6 +62,14 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	// Retrieve the API client from the provider metadata
 	r.Meta.AddFwModuleToMetalUserAgent(ctx, req.ProviderMeta)
 	client := r.Meta.Metal
+	metalClient := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	if vrfID := plan.VrfID.ValueString(); vrfID != "" {
+		resp.Diagnostics.Append(checkReservationVRF(client, plan.IPReservationID.ValueString(), vrfID)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Build the create request based on the plan
 	createRequest := packngo.MetalGatewayCreateRequest{
@@ -75,7 +86,7 @@ func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp
 	}
 
 	// API call to get the Metal Gateway
-	diags, err = getGatewayAndParse(client, &plan, gw.ID)
+	diags, err = getGatewayAndParse(ctx, client, metalClient, &plan, gw.ID)
 	resp.Diagnostics.Append(diags...)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -100,12 +111,13 @@ func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *res
 	// Retrieve the API client from the provider metadata
 	r.Meta.AddFwModuleToMetalUserAgent(ctx, req.ProviderMeta)
 	client := r.Meta.Metal
+	metalClient := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
 
 	// Extract the ID of the resource from the state
 	id := state.ID.ValueString()
 
 	// API call to get the Metal Gateway
-	diags, err := getGatewayAndParse(client, &state, id)
+	diags, err := getGatewayAndParse(ctx, client, metalClient, &state, id)
 	resp.Diagnostics.Append(diags...)
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -150,14 +162,14 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 		deleteResp = nil
 		// Wait for the deletion to be completed
 		deleteTimeout := r.DeleteTimeout(ctx, state.Timeouts)
-		deleteWaiter := getGatewayStateWaiter(
+		err = waitForGatewayState(
+			ctx,
 			client,
 			id,
 			deleteTimeout,
 			[]string{string(packngo.MetalGatewayDeleting)},
 			[]string{},
 		)
-		_, err = deleteWaiter.WaitForStateContext(ctx)
 	}
 
 	if equinix_errors.IgnoreResponseErrors(equinix_errors.HttpForbidden, equinix_errors.HttpNotFound)(deleteResp, err) != nil {
@@ -168,7 +180,7 @@ func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp
 	}
 }
 
-func getGatewayAndParse(client *packngo.Client, state *ResourceModel, id string) (diags diag.Diagnostics, err error) {
+func getGatewayAndParse(ctx context.Context, client *packngo.Client, metalClient *metalv1.APIClient, state *ResourceModel, id string) (diags diag.Diagnostics, err error) {
 	// API call to get the Metal Gateway
 	includes := &packngo.GetOptions{Includes: []string{"project", "ip_reservation", "virtual_network", "vrf"}}
 	gw, _, err := client.MetalGateways.Get(id, includes)
@@ -176,8 +188,13 @@ func getGatewayAndParse(client *packngo.Client, state *ResourceModel, id string)
 		return diags, equinix_errors.FriendlyError(err)
 	}
 
+	vcIDs, err := getGatewayVirtualCircuitIDs(ctx, metalClient, gw)
+	if err != nil {
+		return diags, err
+	}
+
 	// Parse the API response into the Terraform state
-	diags = state.parse(gw)
+	diags = state.parse(ctx, gw, vcIDs)
 	if diags.HasError() {
 		return diags, fmt.Errorf("error parsing Metal Gateway response")
 	}
@@ -185,21 +202,63 @@ func getGatewayAndParse(client *packngo.Client, state *ResourceModel, id string)
 	return diags, nil
 }
 
-func getGatewayStateWaiter(client *packngo.Client, id string, timeout time.Duration, pending, target []string) *retry.StateChangeConf {
-	return &retry.StateChangeConf{
-		Pending: pending,
-		Target:  target,
-		Refresh: func() (interface{}, string, error) {
-			getOpts := &packngo.GetOptions{Includes: []string{"project", "ip_reservation", "virtual_network", "vrf"}}
-
-			gw, _, err := client.MetalGateways.Get(id, getOpts) // TODO: we are not using the returned gw. Remove the includes?
-			if err != nil {
-				return 0, "", err
-			}
-			return gw, string(gw.State), nil
-		},
-		Timeout:    timeout,
-		Delay:      10 * time.Second,
-		MinTimeout: 5 * time.Second,
+// checkReservationVRF makes sure the IP Reservation of a VRF gateway belongs to
+// the configured VRF. The API derives the VRF of the gateway from the
+// reservation, so a mismatch would otherwise only show up as a diff after the
+// gateway has been created.
+func checkReservationVRF(client *packngo.Client, reservationID, vrfID string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	getOpts := &packngo.GetOptions{Includes: []string{"vrf"}}
+	reservation, _, err := client.ProjectIPs.Get(reservationID, getOpts)
+	if err != nil {
+		diags.AddError(
+			"Error reading IP Reservation",
+			"Could not read IP Reservation "+reservationID+": "+equinix_errors.FriendlyError(err).Error(),
+		)
+		return diags
+	}
+
+	if reservation.VRF == nil || reservation.VRF.ID != vrfID {
+		diags.AddAttributeError(
+			path.Root("ip_reservation_id"),
+			"IP Reservation does not belong to the VRF",
+			fmt.Sprintf("IP Reservation %s is not a reservation of VRF %s, use a reservation created with equinix_metal_reserved_ip_block and type \"vrf\" in that VRF", reservationID, vrfID),
+		)
+	}
+	return diags
+}
+
+// getGatewayVirtualCircuitIDs returns the IDs of the Virtual Circuits attached
+// to the VRF of a VRF gateway. The gateway API does not list them, so they are
+// read from the VRF itself. Gateways without a VRF have no such circuits.
+func getGatewayVirtualCircuitIDs(ctx context.Context, client *metalv1.APIClient, gw *packngo.MetalGateway) ([]string, error) {
+	ids := []string{}
+	if gw.VRF == nil {
+		return ids, nil
+	}
+
+	vrf, _, err := client.VRFsApi.FindVrfById(ctx, gw.VRF.ID).Include([]string{"virtual_circuits"}).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyError(err)
+	}
+
+	for _, vc := range vrf.GetVirtualCircuits() {
+		ids = append(ids, vc.GetId())
+	}
+	return ids, nil
+}
+
+func waitForGatewayState(ctx context.Context, client *packngo.Client, id string, timeout time.Duration, pending, target []string) error {
+	refresh := func() (interface{}, string, error) {
+		getOpts := &packngo.GetOptions{Includes: []string{"project", "ip_reservation", "virtual_network", "vrf"}}
+
+		gw, _, err := client.MetalGateways.Get(id, getOpts) // TODO: we are not using the returned gw. Remove the includes?
+		if err != nil {
+			return 0, "", err
+		}
+		return gw, string(gw.State), nil
 	}
+	_, err := wait.ForState(ctx, refresh, pending, target, timeout, wait.WithDelay(wait.DefaultDelay), wait.WithMinTimeout(5*time.Second))
+	return err
 }
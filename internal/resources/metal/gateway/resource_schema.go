@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var subnetSizes = []int64{8, 16, 32, 64, 128}
@@ -40,8 +41,17 @@ func resourceSchema(ctx context.Context) schema.Schema {
 				},
 			},
 			"vrf_id": schema.StringAttribute{
-				Description: "UUID of the VRF associated with the IP Reservation",
+				Description: "UUID of the VRF associated with the IP Reservation. When set, the IP Reservation must belong to this VRF",
+				Optional:    true,
 				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.Expressions{
+						path.MatchRoot("ip_reservation_id"),
+					}...),
+				},
 			},
 			"ip_reservation_id": schema.StringAttribute{
 				Description: "UUID of the Public or VRF IP Reservation to associate",
@@ -75,6 +85,11 @@ func resourceSchema(ctx context.Context) schema.Schema {
 				Description: "Status of the gateway resource",
 				Computed:    true,
 			},
+			"virtual_circuit_ids": schema.ListAttribute{
+				Description: "UUIDs of the Virtual Circuits attached to the VRF of this gateway",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
 		},
 	}
 }
@@ -26,6 +26,8 @@ func TestAccMetalGateway_privateIPv4(t *testing.T) {
 						"equinix_metal_project.test", "id"),
 					resource.TestCheckResourceAttr(
 						"equinix_metal_gateway.test", "private_ipv4_subnet_size", "8"),
+					resource.TestCheckResourceAttr(
+						"equinix_metal_gateway.test", "virtual_circuit_ids.#", "0"),
 				),
 			},
 		},
@@ -100,6 +102,76 @@ resource "equinix_metal_gateway" "test" {
 `
 }
 
+func TestAccMetalGateway_vrf(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheckMetal(t) },
+		ExternalProviders:        acceptance.TestExternalProviders,
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactories,
+		CheckDestroy:             testAccMetalGatewayCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalGatewayConfig_vrf(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_gateway.test", "vrf_id",
+						"equinix_metal_vrf.test", "id"),
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_gateway.test", "ip_reservation_id",
+						"equinix_metal_reserved_ip_block.test", "id"),
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_gateway.test", "vlan_id",
+						"equinix_metal_vlan.test", "id"),
+					resource.TestCheckResourceAttrSet(
+						"equinix_metal_gateway.test", "state"),
+				),
+			},
+			{
+				ResourceName:      "equinix_metal_gateway.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccMetalGatewayConfig_vrf() string {
+	return `
+resource "equinix_metal_project" "test" {
+    name = "tfacc-gateway-test"
+}
+
+resource "equinix_metal_vrf" "test" {
+    name       = "tfacc-gateway-vrf"
+    metro      = "da"
+    local_asn  = "65000"
+    ip_ranges  = ["192.168.100.0/25"]
+    project_id = equinix_metal_project.test.id
+}
+
+resource "equinix_metal_reserved_ip_block" "test" {
+    vrf_id     = equinix_metal_vrf.test.id
+    cidr       = 29
+    network    = "192.168.100.0"
+    type       = "vrf"
+    metro      = "da"
+    project_id = equinix_metal_project.test.id
+}
+
+resource "equinix_metal_vlan" "test" {
+    description = "tfacc-vlan in DA"
+    metro       = "da"
+    project_id  = equinix_metal_project.test.id
+}
+
+resource "equinix_metal_gateway" "test" {
+    project_id        = equinix_metal_project.test.id
+    vlan_id           = equinix_metal_vlan.test.id
+    vrf_id            = equinix_metal_vrf.test.id
+    ip_reservation_id = equinix_metal_reserved_ip_block.test.id
+}
+`
+}
+
 func testAccMetalGatewayCheckDestroyed(s *terraform.State) error {
 	client := acceptance.TestAccProvider.Meta().(*config.Config).Metal
 
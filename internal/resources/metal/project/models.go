@@ -8,6 +8,7 @@ import (
 	"time"
 
 	fwtypes "github.com/equinix/terraform-provider-equinix/internal/framework/types"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -21,6 +22,7 @@ type ResourceModel struct {
 	PaymentMethodID types.String                                    `tfsdk:"payment_method_id"`
 	OrganizationID  types.String                                    `tfsdk:"organization_id"`
 	BGPConfig       fwtypes.ListNestedObjectValueOf[BGPConfigModel] `tfsdk:"bgp_config"`
+	Timeouts        timeouts.Value                                  `tfsdk:"timeouts"`
 }
 
 type DataSourceModel struct {
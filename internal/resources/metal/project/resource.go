@@ -3,28 +3,38 @@ package project
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/equinix/equinix-sdk-go/services/metalv1"
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 )
 
 func NewResource() resource.Resource {
-	return &Resource{
+	r := Resource{
 		BaseResource: framework.NewBaseResource(
 			framework.BaseResourceConfig{
 				Name: "equinix_metal_project",
 			},
 		),
 	}
+	r.SetDefaultUpdateTimeout(10 * time.Minute)
+
+	return &r
 }
 
 type Resource struct {
 	framework.BaseResource
+	framework.WithTimeouts
 }
 
 func (r *Resource) Schema(
@@ -32,7 +42,14 @@ func (r *Resource) Schema(
 	req resource.SchemaRequest,
 	resp *resource.SchemaResponse,
 ) {
-	resp.Schema = resourceSchema(ctx)
+	s := resourceSchema(ctx)
+	if s.Blocks == nil {
+		s.Blocks = make(map[string]schema.Block)
+	}
+	s.Blocks["timeouts"] = timeouts.Block(ctx, timeouts.Opts{
+		Update: true,
+	})
+	resp.Schema = s
 }
 
 func (r *Resource) Create(
@@ -64,9 +81,20 @@ func (r *Resource) Create(
 	// API call to create the project
 	project, createResp, err := client.ProjectsApi.CreateProject(ctx).ProjectCreateFromRootInput(createRequest).Execute()
 	if err != nil {
+		friendlyErr := equinix_errors.FriendlyErrorForMetalGo(err, createResp)
+		if isNameTakenError(createResp, friendlyErr) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Project name already in use",
+				fmt.Sprintf("Could not create project: %s. Project names must be unique within an organization. ", friendlyErr)+
+					"If this project is being replaced with create_before_destroy, rename the existing project in place first "+
+					"and apply, then make the remaining changes in a second apply.",
+			)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error creating project",
-			"Could not create project: "+equinix_errors.FriendlyErrorForMetalGo(err, createResp).Error(),
+			"Could not create project: "+friendlyErr.Error(),
 		)
 		return
 	}
@@ -205,6 +233,16 @@ func fetchProject(ctx context.Context, client *metalv1.APIClient, projectID stri
 	return project, diags
 }
 
+// isNameTakenError reports whether a project create failure was caused by
+// another project in the organization already using the requested name
+func isNameTakenError(resp *http.Response, err error) bool {
+	if resp == nil || resp.StatusCode != http.StatusUnprocessableEntity {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "name") && strings.Contains(msg, "already been taken")
+}
+
 func (r *Resource) Update(
 	ctx context.Context,
 	req resource.UpdateRequest,
@@ -224,6 +262,16 @@ func (r *Resource) Update(
 	// Extract the ID of the resource from the state
 	id := state.ID.ValueString()
 
+	// Transfer the project first, so that the remaining changes, such as a
+	// payment method, are applied within the new organization
+	if !plan.OrganizationID.IsUnknown() && !plan.OrganizationID.Equal(state.OrganizationID) {
+		updateTimeout := r.UpdateTimeout(ctx, plan.Timeouts)
+		resp.Diagnostics.Append(transferProject(ctx, client, id, state.OrganizationID.ValueString(), plan.OrganizationID.ValueString(), updateTimeout)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Handle BGP Config changes
 	bgpConfig, diags := handleBGPConfigChanges(ctx, client, &plan, &state, id)
 	resp.Diagnostics.Append(diags...)
@@ -55,11 +55,10 @@ func resourceSchema(ctx context.Context) schema.Schema {
 				},
 			},
 			"organization_id": schema.StringAttribute{
-				Description: "The UUID of organization under which the project is created",
+				Description: "The UUID of organization under which the project is created. Changing this transfers the project to the new organization",
 				Optional:    true,
 				Computed:    true,
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
 					stringplanmodifier.UseStateForUnknown(),
 				},
 				Validators: []validator.String{
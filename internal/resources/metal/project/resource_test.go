@@ -91,6 +91,29 @@ func TestAccMetalProject_apiErrorHandling(t *testing.T) {
 	})
 }
 
+func TestAccMetalProject_nameTakenErrorHandling(t *testing.T) {
+	rInt := acctest.RandInt()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"errors":["Name has already been taken"]}`))
+	}
+	mockAPI := httptest.NewServer(http.HandlerFunc(handler))
+	providerConfig := testAccMetalProviderConfig(mockAPI.URL, "fake-for-mock-test", "fake-for-mock-test")
+	projectConfig := testAccMetalProjectConfig_basic(rInt)
+
+	resource.ParallelTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: mockProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + "\n" + projectConfig,
+				ExpectError: regexp.MustCompile(`Project name already in use`),
+			},
+		},
+	})
+}
+
 func mockProviderFactories() map[string]func() (tfprotov5.ProviderServer, error) {
 	mockProviders := map[string]*schema.Provider{
 		"equinix": equinix.Provider(),
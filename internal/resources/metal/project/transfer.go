@@ -0,0 +1,125 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	projectTransferring = "transferring"
+	projectTransferred  = "transferred"
+)
+
+// projectTransferWaitOpts tune the waiter for a project transfer, which is
+// usually applied as soon as the target organization accepts it
+var projectTransferWaitOpts = []wait.Option{
+	wait.WithDelay(2 * time.Second),
+	wait.WithMinTimeout(wait.DefaultMinTimeout),
+}
+
+// transferProject moves a project from its current organization to another
+// one. The transfer request is accepted right away, which requires the
+// credentials to be an owner of the target organization as well. When they
+// are not, the pending transfer request is reported so it can be accepted by
+// an owner of the target organization, after which the next refresh picks up
+// the new organization. A pending transfer request of the project to the same
+// organization, left by an earlier apply, is accepted instead of requesting
+// the transfer again.
+func transferProject(ctx context.Context, client *metalv1.APIClient, projectID, currentOrganizationID, organizationID string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	transfer, err := findPendingProjectTransfer(ctx, client, projectID, currentOrganizationID, organizationID)
+	if err != nil {
+		diags.AddError(
+			"Error transferring project",
+			fmt.Sprintf("Could not look up pending transfer requests of project %s: %s", projectID, err),
+		)
+		return diags
+	}
+	if transfer == nil {
+		input := metalv1.TransferRequestInput{TargetOrganizationId: &organizationID}
+		var createResp *http.Response
+		transfer, createResp, err = client.ProjectsApi.CreateTransferRequest(ctx, projectID).TransferRequestInput(input).Execute()
+		if err != nil {
+			err = equinix_errors.FriendlyErrorForMetalGo(err, createResp)
+			diags.AddError(
+				"Error transferring project",
+				fmt.Sprintf("Could not request the transfer of project %s to organization %s: %s", projectID, organizationID, err),
+			)
+			return diags
+		}
+	}
+
+	acceptResp, err := client.TransferRequestsApi.AcceptTransferRequest(ctx, transfer.GetId()).Execute()
+	if err != nil {
+		err = equinix_errors.FriendlyErrorForMetalGo(err, acceptResp)
+		if equinix_errors.IsForbidden(err) || equinix_errors.IsNotFound(err) {
+			diags.AddError(
+				"Project transfer awaiting approval",
+				fmt.Sprintf("Transfer request %s of project %s to organization %s was created, but could not be accepted with the "+
+					"configured credentials: %s. An owner of the target organization must accept it, the new organization "+
+					"is picked up by the next refresh once they do.", transfer.GetId(), projectID, organizationID, err),
+			)
+			return diags
+		}
+		diags.AddError(
+			"Error transferring project",
+			fmt.Sprintf("Could not accept transfer request %s of project %s: %s", transfer.GetId(), projectID, err),
+		)
+		return diags
+	}
+
+	refresh := func() (interface{}, string, error) {
+		project, resp, err := client.ProjectsApi.FindProjectById(ctx, projectID).Execute()
+		if err != nil {
+			return nil, "", equinix_errors.FriendlyErrorForMetalGo(err, resp)
+		}
+		if projectOrganizationID(project) == organizationID {
+			return project, projectTransferred, nil
+		}
+		return project, projectTransferring, nil
+	}
+	_, err = wait.ForState(ctx, refresh, []string{projectTransferring}, []string{projectTransferred}, timeout, projectTransferWaitOpts...)
+	if err != nil {
+		diags.AddError(
+			"Error transferring project",
+			fmt.Sprintf("Error waiting for project %s to be transferred to organization %s: %s", projectID, organizationID, err),
+		)
+	}
+	return diags
+}
+
+// findPendingProjectTransfer returns the pending transfer request of a project
+// to an organization, listed under the organization the project belongs to, or
+// nil when there is none
+func findPendingProjectTransfer(ctx context.Context, client *metalv1.APIClient, projectID, currentOrganizationID, organizationID string) (*metalv1.TransferRequest, error) {
+	transfers, resp, err := client.OrganizationsApi.FindOrganizationTransfers(ctx, currentOrganizationID).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, resp)
+	}
+	for i := range transfers.GetTransfers() {
+		transfer := transfers.Transfers[i]
+		project, target := transfer.GetProject(), transfer.GetTargetOrganization()
+		if path.Base(project.GetHref()) == projectID && path.Base(target.GetHref()) == organizationID {
+			return &transfer, nil
+		}
+	}
+	return nil, nil
+}
+
+// projectOrganizationID returns the ID of the organization a project belongs
+// to, which the API only exposes as a link
+func projectOrganizationID(project *metalv1.Project) string {
+	org := project.GetOrganization()
+	href, _ := org.AdditionalProperties["href"].(string)
+	return path.Base(href)
+}
@@ -0,0 +1,115 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
+)
+
+func TestTransferProject(t *testing.T) {
+	waitOpts := projectTransferWaitOpts
+	t.Cleanup(func() { projectTransferWaitOpts = waitOpts })
+	projectTransferWaitOpts = []wait.Option{wait.WithDelay(0)}
+
+	tests := []struct {
+		name         string
+		pending      string
+		acceptStatus int
+		wantErr      string
+	}{
+		{
+			name:         "accepted",
+			acceptStatus: http.StatusNoContent,
+		},
+		{
+			name:         "pending transfer accepted",
+			pending:      "newOrgId",
+			acceptStatus: http.StatusNoContent,
+		},
+		{
+			name:         "pending transfer to another organization",
+			pending:      "otherOrgId",
+			acceptStatus: http.StatusNoContent,
+		},
+		{
+			name:         "pending transfer awaiting approval",
+			pending:      "newOrgId",
+			acceptStatus: http.StatusForbidden,
+			wantErr:      "Project transfer awaiting approval",
+		},
+		{
+			name:         "awaiting approval",
+			acceptStatus: http.StatusForbidden,
+			wantErr:      "Project transfer awaiting approval",
+		},
+		{
+			name:         "accept failed",
+			acceptStatus: http.StatusUnprocessableEntity,
+			wantErr:      "Error transferring project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accepted, created := false, false
+			mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("Content-Type", "application/json")
+				w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+				switch {
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/organizations/oldOrgId/transfers"):
+					transfers := `[]`
+					if tt.pending != "" {
+						transfers = `[{"id": "transferId", "project": {"href": "/metal/v1/projects/projectId"}, "target_organization": {"href": "/metal/v1/organizations/` + tt.pending + `"}}]`
+					}
+					w.Write([]byte(`{"transfers": ` + transfers + `}`))
+				case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/projects/projectId/transfers"):
+					created = true
+					w.WriteHeader(http.StatusCreated)
+					w.Write([]byte(`{"id": "transferId"}`))
+				case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/transfers/transferId"):
+					accepted = tt.acceptStatus == http.StatusNoContent
+					w.WriteHeader(tt.acceptStatus)
+					if !accepted {
+						w.Write([]byte(`{"errors": ["not allowed"]}`))
+					}
+				case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/projects/projectId"):
+					org := "oldOrgId"
+					if accepted {
+						org = "newOrgId"
+					}
+					w.Write([]byte(`{"id": "projectId", "organization": {"href": "/metal/v1/organizations/` + org + `"}}`))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer mockAPI.Close()
+
+			ctx := context.Background()
+			meta := &config.Config{BaseURL: mockAPI.URL, Token: "fakeTokenForMock"}
+			if err := meta.Load(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			diags := transferProject(ctx, meta.NewMetalClientForTesting(), "projectId", "oldOrgId", "newOrgId", time.Minute)
+			if wantCreated := tt.pending != "newOrgId"; created != wantCreated {
+				t.Errorf("transferProject() created a transfer request = %v, want %v", created, wantCreated)
+			}
+			if tt.wantErr == "" {
+				if diags.HasError() {
+					t.Fatalf("transferProject() = %v, want no error", diags)
+				}
+				return
+			}
+			if !diags.HasError() || !strings.Contains(diags.Errors()[0].Summary(), tt.wantErr) {
+				t.Errorf("transferProject() = %v, want %q", diags, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package projectbgpconfig
+
+import (
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	ProjectID      types.String `tfsdk:"project_id"`
+	DeploymentType types.String `tfsdk:"deployment_type"`
+	ASN            types.Int64  `tfsdk:"asn"`
+	MD5            types.String `tfsdk:"md5"`
+	Status         types.String `tfsdk:"status"`
+	MaxPrefix      types.Int64  `tfsdk:"max_prefix"`
+}
+
+func (m *ResourceModel) requestInput() metalv1.BgpConfigRequestInput {
+	input := metalv1.BgpConfigRequestInput{
+		DeploymentType: metalv1.BgpConfigRequestInputDeploymentType(m.DeploymentType.ValueString()),
+		Asn:            m.ASN.ValueInt64(),
+	}
+	if !m.MD5.IsNull() && !m.MD5.IsUnknown() {
+		input.Md5 = m.MD5.ValueStringPointer()
+	}
+	return input
+}
+
+// parse sets the BGP config of the project. The md5 password is not read
+// back, as the API does not always return it, and is kept as configured.
+func (m *ResourceModel) parse(projectID string, bgpConfig *metalv1.BgpConfig) {
+	m.ID = types.StringValue(projectID)
+	m.ProjectID = types.StringValue(projectID)
+	m.DeploymentType = types.StringValue(string(bgpConfig.GetDeploymentType()))
+	m.ASN = types.Int64Value(bgpConfig.GetAsn())
+	m.Status = types.StringValue(string(bgpConfig.GetStatus()))
+	m.MaxPrefix = types.Int64Value(int64(bgpConfig.GetMaxPrefix()))
+}
+
+// isEmptyBGPConfig reports whether BGP has not been requested in the project,
+// in which case the API returns a config without any of its attributes set
+func isEmptyBGPConfig(bgpConfig *metalv1.BgpConfig) bool {
+	if metalv1.IsNil(bgpConfig) {
+		return true
+	}
+	return metalv1.IsNil(bgpConfig.DeploymentType) &&
+		metalv1.IsNil(bgpConfig.Asn) &&
+		metalv1.IsNil(bgpConfig.Status)
+}
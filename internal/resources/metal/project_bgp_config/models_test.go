@@ -0,0 +1,82 @@
+package projectbgpconfig
+
+import (
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestResourceModel_parse(t *testing.T) {
+	deploymentType := metalv1.BGPCONFIGDEPLOYMENTTYPE_LOCAL
+	status := metalv1.BGPCONFIGSTATUS_ENABLED
+	bgpConfig := &metalv1.BgpConfig{
+		Asn:            metalv1.PtrInt64(65000),
+		DeploymentType: &deploymentType,
+		MaxPrefix:      metalv1.PtrInt32(10),
+		Status:         &status,
+	}
+
+	m := ResourceModel{MD5: types.StringValue("configured")}
+	m.parse("projectId", bgpConfig)
+
+	if got := m.ID.ValueString(); got != "projectId" {
+		t.Errorf("parse() id = %q, want %q", got, "projectId")
+	}
+	if got := m.DeploymentType.ValueString(); got != "local" {
+		t.Errorf("parse() deployment_type = %q, want %q", got, "local")
+	}
+	if got := m.ASN.ValueInt64(); got != 65000 {
+		t.Errorf("parse() asn = %d, want %d", got, 65000)
+	}
+	if got := m.Status.ValueString(); got != "enabled" {
+		t.Errorf("parse() status = %q, want %q", got, "enabled")
+	}
+	if got := m.MaxPrefix.ValueInt64(); got != 10 {
+		t.Errorf("parse() max_prefix = %d, want %d", got, 10)
+	}
+	if got := m.MD5.ValueString(); got != "configured" {
+		t.Errorf("parse() md5 = %q, want the configured md5", got)
+	}
+
+	// a removed md5 stays removed even if the API still returns the old one
+	bgpConfig.Md5 = *metalv1.NewNullableString(metalv1.PtrString("fromApi"))
+	m.MD5 = types.StringNull()
+	m.parse("projectId", bgpConfig)
+	if !m.MD5.IsNull() {
+		t.Errorf("parse() md5 = %q, want the removed md5 to stay null", m.MD5.ValueString())
+	}
+}
+
+func TestResourceModel_requestInput(t *testing.T) {
+	m := ResourceModel{
+		DeploymentType: types.StringValue("global"),
+		ASN:            types.Int64Value(65100),
+		MD5:            types.StringNull(),
+	}
+	input := m.requestInput()
+	if input.DeploymentType != metalv1.BGPCONFIGREQUESTINPUTDEPLOYMENTTYPE_GLOBAL || input.Asn != 65100 {
+		t.Errorf("requestInput() = %+v, want a global config with ASN 65100", input)
+	}
+	if input.Md5 != nil {
+		t.Errorf("requestInput() md5 = %q, want none", *input.Md5)
+	}
+
+	m.MD5 = types.StringValue("2SFsdfsg43")
+	if input := m.requestInput(); input.Md5 == nil || *input.Md5 != "2SFsdfsg43" {
+		t.Errorf("requestInput() md5 = %v, want %q", input.Md5, "2SFsdfsg43")
+	}
+}
+
+func TestIsEmptyBGPConfig(t *testing.T) {
+	status := metalv1.BGPCONFIGSTATUS_REQUESTED
+	if !isEmptyBGPConfig(nil) {
+		t.Error("isEmptyBGPConfig(nil) = false, want true")
+	}
+	if !isEmptyBGPConfig(&metalv1.BgpConfig{}) {
+		t.Error("isEmptyBGPConfig() of a config without attributes = false, want true")
+	}
+	if isEmptyBGPConfig(&metalv1.BgpConfig{Status: &status}) {
+		t.Error("isEmptyBGPConfig() of a requested config = true, want false")
+	}
+}
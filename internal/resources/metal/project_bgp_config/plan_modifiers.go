@@ -0,0 +1,51 @@
+package projectbgpconfig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+)
+
+// unchangeableBGPConfig rejects changes to the attributes of a BGP config that
+// has already been requested. The Metal API can neither disable BGP in a
+// project nor request it again with other values, so replacing the resource
+// would not change the config of the project either.
+func unchangeableBGPConfig() unchangeableBGPConfigModifier {
+	return unchangeableBGPConfigModifier{}
+}
+
+type unchangeableBGPConfigModifier struct{}
+
+func (m unchangeableBGPConfigModifier) Description(ctx context.Context) string {
+	return "Rejects changes once the BGP config of the project has been requested."
+}
+
+func (m unchangeableBGPConfigModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m unchangeableBGPConfigModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsUnknown() || req.PlanValue.Equal(req.StateValue) {
+		return
+	}
+	addUnchangeableBGPConfigError(&resp.Diagnostics, req.Path, req.StateValue.ValueString(), req.PlanValue.ValueString())
+}
+
+func (m unchangeableBGPConfigModifier) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.StateValue.IsNull() || req.PlanValue.IsUnknown() || req.PlanValue.Equal(req.StateValue) {
+		return
+	}
+	addUnchangeableBGPConfigError(&resp.Diagnostics, req.Path, req.StateValue.ValueInt64(), req.PlanValue.ValueInt64())
+}
+
+func addUnchangeableBGPConfigError(diags *diag.Diagnostics, p path.Path, old, new interface{}) {
+	diags.AddAttributeError(
+		p,
+		"Change not allowed",
+		fmt.Sprintf("Cannot change `%s` from %v to %v. BGP stays enabled in a project with the values it was first requested with, "+
+			"the Equinix Metal API can not change them. Keep the current value, or enable BGP in another project.", p, old, new),
+	)
+}
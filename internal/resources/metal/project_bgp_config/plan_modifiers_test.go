@@ -0,0 +1,59 @@
+package projectbgpconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUnchangeableBGPConfig_string(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     types.String
+		plan      types.String
+		wantError bool
+	}{
+		{name: "create", state: types.StringNull(), plan: types.StringValue("local")},
+		{name: "unchanged", state: types.StringValue("local"), plan: types.StringValue("local")},
+		{name: "unknown", state: types.StringValue("local"), plan: types.StringUnknown()},
+		{name: "changed", state: types.StringValue("local"), plan: types.StringValue("global"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.StringRequest{Path: path.Root("deployment_type"), StateValue: tt.state, PlanValue: tt.plan}
+			resp := &planmodifier.StringResponse{PlanValue: tt.plan}
+			unchangeableBGPConfig().PlanModifyString(context.Background(), req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("PlanModifyString() diagnostics = %v, wantError %v", resp.Diagnostics, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestUnchangeableBGPConfig_int64(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     types.Int64
+		plan      types.Int64
+		wantError bool
+	}{
+		{name: "create", state: types.Int64Null(), plan: types.Int64Value(65000)},
+		{name: "unchanged", state: types.Int64Value(65000), plan: types.Int64Value(65000)},
+		{name: "changed", state: types.Int64Value(65000), plan: types.Int64Value(65100), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.Int64Request{Path: path.Root("asn"), StateValue: tt.state, PlanValue: tt.plan}
+			resp := &planmodifier.Int64Response{PlanValue: tt.plan}
+			unchangeableBGPConfig().PlanModifyInt64(context.Background(), req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("PlanModifyInt64() diagnostics = %v, wantError %v", resp.Diagnostics, tt.wantError)
+			}
+		})
+	}
+}
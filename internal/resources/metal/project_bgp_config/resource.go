@@ -0,0 +1,168 @@
+package projectbgpconfig
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+type Resource struct {
+	framework.BaseResource
+}
+
+func NewResource() resource.Resource {
+	r := Resource{
+		BaseResource: framework.NewBaseResource(
+			framework.BaseResourceConfig{
+				Name: "equinix_metal_project_bgp_config",
+			},
+		),
+	}
+
+	return &r
+}
+
+func (r *Resource) Schema(
+	ctx context.Context,
+	req resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = resourceSchema(ctx)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	var plan ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := plan.ProjectID.ValueString()
+	resp.Diagnostics.Append(requestBGPConfig(ctx, client, projectID, plan.requestInput())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(getBGPConfigAndParse(ctx, client, projectID, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.ProjectID.IsNull() {
+		// imported by the ID of the project
+		state.ProjectID = state.ID
+	}
+
+	projectID := state.ProjectID.ValueString()
+	bgpConfig, httpResp, err := client.BGPApi.FindBgpConfigByProject(ctx, projectID).Execute()
+	if err != nil && (httpResp == nil || httpResp.StatusCode != http.StatusNotFound) {
+		resp.Diagnostics.AddError(
+			"Error reading Metal project BGP config",
+			"Could not read BGP config of project "+projectID+": "+equinix_errors.FriendlyErrorForMetalGo(err, httpResp).Error(),
+		)
+		return
+	}
+	if err != nil || isEmptyBGPConfig(bgpConfig) {
+		resp.Diagnostics.AddWarning(
+			"Equinix Metal project BGP config not found during refresh",
+			fmt.Sprintf("[WARN] BGP config of project (%s) not found, removing from state", projectID),
+		)
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.parse(projectID, bgpConfig)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	var state, plan ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only md5 can change in place, the BGP config is requested again with
+	// the new password
+	projectID := plan.ProjectID.ValueString()
+	if !plan.MD5.Equal(state.MD5) {
+		resp.Diagnostics.Append(requestBGPConfig(ctx, client, projectID, plan.requestInput())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(getBGPConfigAndParse(ctx, client, projectID, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the BGP config from the Terraform state, the Metal API
+// cannot disable BGP in a project once it has been requested
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Equinix Metal project BGP config not disabled",
+		fmt.Sprintf("BGP cannot be disabled in a project, the BGP config of project %s was removed from the Terraform state only and stays enabled until the project is deleted", state.ProjectID.ValueString()),
+	)
+}
+
+func requestBGPConfig(ctx context.Context, client *metalv1.APIClient, projectID string, input metalv1.BgpConfigRequestInput) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	httpResp, err := client.BGPApi.RequestBgpConfig(ctx, projectID).BgpConfigRequestInput(input).Execute()
+	if err != nil {
+		diags.AddError(
+			"Error requesting Metal project BGP config",
+			"Could not request BGP config for project "+projectID+": "+equinix_errors.FriendlyErrorForMetalGo(err, httpResp).Error(),
+		)
+	}
+	return diags
+}
+
+func getBGPConfigAndParse(ctx context.Context, client *metalv1.APIClient, projectID string, m *ResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	bgpConfig, httpResp, err := client.BGPApi.FindBgpConfigByProject(ctx, projectID).Execute()
+	if err != nil {
+		diags.AddError(
+			"Error reading Metal project BGP config",
+			"Could not read BGP config of project "+projectID+": "+equinix_errors.FriendlyErrorForMetalGo(err, httpResp).Error(),
+		)
+		return diags
+	}
+
+	m.parse(projectID, bgpConfig)
+	return diags
+}
@@ -0,0 +1,67 @@
+package projectbgpconfig
+
+import (
+	"context"
+
+	equinix_validation "github.com/equinix/terraform-provider-equinix/internal/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+func resourceSchema(ctx context.Context) schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the project",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description: "UUID of the project to enable BGP in",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					equinix_validation.UUID(),
+				},
+			},
+			"deployment_type": schema.StringAttribute{
+				MarkdownDescription: "The BGP deployment type, either 'local' or 'global'. The local is likely to be usable immediately, the global will need to be review by Equinix Metal engineers",
+				Description:         "The BGP deployment type, either 'local' or 'global'",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					unchangeableBGPConfig(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("local", "global"),
+				},
+			},
+			"asn": schema.Int64Attribute{
+				Description: "Autonomous System Number for the BGP sessions of the project",
+				Required:    true,
+				PlanModifiers: []planmodifier.Int64{
+					unchangeableBGPConfig(),
+				},
+			},
+			"md5": schema.StringAttribute{
+				Description: "Password for BGP session in plaintext (not a checksum)",
+				Sensitive:   true,
+				Optional:    true,
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of BGP configuration in the project",
+				Computed:    true,
+			},
+			"max_prefix": schema.Int64Attribute{
+				Description: "The maximum number of route filters allowed per server",
+				Computed:    true,
+			},
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package projectbgpconfig_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccMetalProjectBGPConfigConfig(name, md5 string) string {
+	return fmt.Sprintf(`
+resource "equinix_metal_project" "test" {
+  name = "tfacc-project-bgp-config-%s"
+}
+
+resource "equinix_metal_project_bgp_config" "test" {
+  project_id      = equinix_metal_project.test.id
+  deployment_type = "local"
+  asn             = 65000
+  md5             = "%s"
+}
+`, name, md5)
+}
+
+func TestAccMetalProjectBGPConfig_basic(t *testing.T) {
+	rs := acctest.RandString(10)
+	res := "equinix_metal_project_bgp_config.test"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheckMetal(t) },
+		ExternalProviders:        acceptance.TestExternalProviders,
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalProjectBGPConfigConfig(rs, "2SFsdfsg43"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						res, "id",
+						"equinix_metal_project.test", "id"),
+					resource.TestCheckResourceAttr(res, "deployment_type", "local"),
+					resource.TestCheckResourceAttr(res, "asn", "65000"),
+					resource.TestCheckResourceAttr(res, "md5", "2SFsdfsg43"),
+					resource.TestCheckResourceAttrSet(res, "status"),
+				),
+			},
+			{
+				ResourceName:            res,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"md5"},
+			},
+			{
+				Config: testAccMetalProjectBGPConfigConfig(rs, "fdsfsdf432G"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(res, "md5", "fdsfsdf432G"),
+				),
+			},
+		},
+	})
+}
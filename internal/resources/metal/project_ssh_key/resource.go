@@ -104,6 +104,7 @@ func (r *Resource) Read(
 			fmt.Sprintf("Failed to get Project SSHKey %s", id),
 			err.Error(),
 		)
+		return
 	}
 
 	// Set state to fully populated data
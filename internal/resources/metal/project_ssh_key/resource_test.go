@@ -79,6 +79,11 @@ func TestAccMetalProjectSSHKey_basic(t *testing.T) {
 					),
 				),
 			},
+			{
+				ResourceName:      "equinix_metal_project_ssh_key.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
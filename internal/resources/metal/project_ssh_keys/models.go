@@ -0,0 +1,59 @@
+package project_ssh_keys
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	ProjectID types.String `tfsdk:"project_id"`
+	Keys      types.Map    `tfsdk:"keys"`    // Map of strings
+	KeyIDs    types.Map    `tfsdk:"key_ids"` // Map of strings
+}
+
+func (m *ResourceModel) parse(ctx context.Context, projectID string, keys []metalv1.SSHKey) (diags diag.Diagnostics) {
+	m.ID = types.StringValue(projectID)
+	m.ProjectID = types.StringValue(projectID)
+
+	// Keep the configured public key when it only differs from the API value
+	// in surrounding whitespace, to avoid a perpetual diff
+	known := map[string]string{}
+	if !m.Keys.IsNull() && !m.Keys.IsUnknown() {
+		diags.Append(m.Keys.ElementsAs(ctx, &known, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	publicKeys := make(map[string]string, len(keys))
+	keyIDs := make(map[string]string, len(keys))
+	for _, key := range keys {
+		name := key.GetLabel()
+		if _, ok := publicKeys[name]; ok {
+			diags.AddWarning(
+				"Duplicate project SSH key name",
+				fmt.Sprintf("Project %s has more than one SSH key named %q, only key %s is tracked", projectID, name, keyIDs[name]),
+			)
+			continue
+		}
+		publicKey := key.GetKey()
+		if k, ok := known[name]; ok && strings.TrimSpace(k) == strings.TrimSpace(publicKey) {
+			publicKey = k
+		}
+		publicKeys[name] = publicKey
+		keyIDs[name] = key.GetId()
+	}
+
+	var d diag.Diagnostics
+	m.Keys, d = types.MapValueFrom(ctx, types.StringType, publicKeys)
+	diags.Append(d...)
+	m.KeyIDs, d = types.MapValueFrom(ctx, types.StringType, keyIDs)
+	diags.Append(d...)
+	return diags
+}
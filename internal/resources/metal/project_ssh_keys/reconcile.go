@@ -0,0 +1,32 @@
+package project_ssh_keys
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+// diffProjectSSHKeys compares the SSH keys currently in a project with the
+// desired map of key names to public keys. It returns the keys that must be
+// created and the IDs of the keys that must be deleted so that the project
+// keys match the desired map. A key whose public key changed is deleted and
+// created again, as public keys can not be updated in place.
+func diffProjectSSHKeys(current []metalv1.SSHKey, desired map[string]string) (toCreate map[string]string, toDelete []string) {
+	toCreate = map[string]string{}
+	for name, publicKey := range desired {
+		toCreate[name] = publicKey
+	}
+
+	for _, key := range current {
+		publicKey, ok := toCreate[key.GetLabel()]
+		if ok && strings.TrimSpace(publicKey) == strings.TrimSpace(key.GetKey()) {
+			delete(toCreate, key.GetLabel())
+			continue
+		}
+		toDelete = append(toDelete, key.GetId())
+	}
+
+	sort.Strings(toDelete)
+	return toCreate, toDelete
+}
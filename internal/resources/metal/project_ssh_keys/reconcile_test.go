@@ -0,0 +1,68 @@
+package project_ssh_keys
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+func TestDiffProjectSSHKeys(t *testing.T) {
+	key := func(id, name, publicKey string) metalv1.SSHKey {
+		return metalv1.SSHKey{
+			Id:    metalv1.PtrString(id),
+			Label: metalv1.PtrString(name),
+			Key:   metalv1.PtrString(publicKey),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		current    []metalv1.SSHKey
+		desired    map[string]string
+		wantCreate map[string]string
+		wantDelete []string
+	}{
+		{
+			name:       "empty project",
+			current:    nil,
+			desired:    map[string]string{"alice": "ssh-ed25519 AAAA alice"},
+			wantCreate: map[string]string{"alice": "ssh-ed25519 AAAA alice"},
+		},
+		{
+			name:       "in sync",
+			current:    []metalv1.SSHKey{key("1", "alice", "ssh-ed25519 AAAA alice\n")},
+			desired:    map[string]string{"alice": "ssh-ed25519 AAAA alice"},
+			wantCreate: map[string]string{},
+		},
+		{
+			name: "out of band key is deleted",
+			current: []metalv1.SSHKey{
+				key("1", "alice", "ssh-ed25519 AAAA alice"),
+				key("2", "mallory", "ssh-ed25519 BBBB mallory"),
+			},
+			desired:    map[string]string{"alice": "ssh-ed25519 AAAA alice"},
+			wantCreate: map[string]string{},
+			wantDelete: []string{"2"},
+		},
+		{
+			name:       "changed public key is replaced",
+			current:    []metalv1.SSHKey{key("1", "alice", "ssh-ed25519 AAAA alice")},
+			desired:    map[string]string{"alice": "ssh-ed25519 CCCC alice"},
+			wantCreate: map[string]string{"alice": "ssh-ed25519 CCCC alice"},
+			wantDelete: []string{"1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCreate, gotDelete := diffProjectSSHKeys(tt.current, tt.desired)
+			if !reflect.DeepEqual(gotCreate, tt.wantCreate) {
+				t.Errorf("diffProjectSSHKeys() toCreate = %v, want %v", gotCreate, tt.wantCreate)
+			}
+			if !reflect.DeepEqual(gotDelete, tt.wantDelete) {
+				t.Errorf("diffProjectSSHKeys() toDelete = %v, want %v", gotDelete, tt.wantDelete)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package project_ssh_keys
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+func NewResource() resource.Resource {
+	return &Resource{
+		BaseResource: framework.NewBaseResource(
+			framework.BaseResourceConfig{
+				Name:   "equinix_metal_project_ssh_keys",
+				Schema: GetResourceSchema(),
+			},
+		),
+	}
+}
+
+type Resource struct {
+	framework.BaseResource
+}
+
+func (r *Resource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	// Retrieve values from plan
+	var plan ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := plan.ProjectID.ValueString()
+
+	// Make the project SSH keys match the plan
+	keys, diags := reconcileProjectSSHKeys(ctx, client, projectID, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Parse API response into the Terraform state
+	resp.Diagnostics.Append(plan.parse(ctx, projectID, keys)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Resource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	// Retrieve values from state
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Extract the ID of the resource from the state, the ID is the project ID
+	id := state.ID.ValueString()
+
+	// Use API client to get the current keys of the project, keys added out
+	// of band are read as well so they show up as drift
+	keys, err := listProjectSSHKeys(ctx, client, id)
+	if err != nil {
+		// If the project is somehow already destroyed, mark as
+		// succesfully gone
+		if equinix_errors.IsNotFound(err) {
+			resp.Diagnostics.AddWarning(
+				"Equinix Metal Project SSHKeys not found during refresh",
+				fmt.Sprintf("[WARN] Project (%s) not found, removing from state", id),
+			)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Failed to get SSHKeys of project %s", id),
+			err.Error(),
+		)
+		return
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(state.parse(ctx, id, keys)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update the Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *Resource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	// Retrieve values from plan
+	var plan ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := plan.ProjectID.ValueString()
+
+	// Make the project SSH keys match the plan
+	keys, diags := reconcileProjectSSHKeys(ctx, client, projectID, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(plan.parse(ctx, projectID, keys)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read the updated state back into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Resource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	// Retrieve values from plan
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyIDs := map[string]string{}
+	resp.Diagnostics.Append(state.KeyIDs.ElementsAs(ctx, &keyIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Use API client to delete the keys
+	for _, id := range keyIDs {
+		deleteResp, err := client.SSHKeysApi.DeleteSSHKey(ctx, id).Execute()
+		if err != nil && !isGone(deleteResp) {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Failed to delete Project SSHKey %s", id),
+				equinix_errors.FriendlyErrorForMetalGo(err, deleteResp).Error(),
+			)
+		}
+	}
+}
+
+func listProjectSSHKeys(ctx context.Context, client *metalv1.APIClient, projectID string) ([]metalv1.SSHKey, error) {
+	keysList, httpResp, err := client.SSHKeysApi.FindProjectSSHKeys(ctx, projectID).Execute()
+	if err != nil {
+		return nil, equinix_errors.FriendlyErrorForMetalGo(err, httpResp)
+	}
+	return keysList.GetSshKeys(), nil
+}
+
+// isGone reports whether a key or project is already deleted, or not visible
+// to the user anymore
+func isGone(httpResp *http.Response) bool {
+	return httpResp != nil && (httpResp.StatusCode == http.StatusForbidden || httpResp.StatusCode == http.StatusNotFound)
+}
+
+// reconcileProjectSSHKeys deletes and creates project SSH keys until the keys
+// of the project match the planned keys, and returns the resulting keys.
+func reconcileProjectSSHKeys(ctx context.Context, client *metalv1.APIClient, projectID string, plan ResourceModel) ([]metalv1.SSHKey, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	desired := map[string]string{}
+	diags.Append(plan.Keys.ElementsAs(ctx, &desired, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	current, err := listProjectSSHKeys(ctx, client, projectID)
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf("Failed to get SSHKeys of project %s", projectID),
+			err.Error(),
+		)
+		return nil, diags
+	}
+
+	toCreate, toDelete := diffProjectSSHKeys(current, desired)
+
+	// Delete first so a replaced key does not clash with its previous version
+	for _, id := range toDelete {
+		deleteResp, err := client.SSHKeysApi.DeleteSSHKey(ctx, id).Execute()
+		if err != nil && !isGone(deleteResp) {
+			diags.AddError(
+				fmt.Sprintf("Failed to delete Project SSHKey %s", id),
+				equinix_errors.FriendlyErrorForMetalGo(err, deleteResp).Error(),
+			)
+			return nil, diags
+		}
+	}
+
+	for name, publicKey := range toCreate {
+		createRequest := metalv1.SSHKeyCreateInput{
+			Label: metalv1.PtrString(name),
+			Key:   metalv1.PtrString(publicKey),
+		}
+		if _, createResp, err := client.SSHKeysApi.CreateProjectSSHKey(ctx, projectID).SSHKeyCreateInput(createRequest).Execute(); err != nil {
+			diags.AddError(
+				fmt.Sprintf("Failed to create Project SSH Key %s", name),
+				equinix_errors.FriendlyErrorForMetalGo(err, createResp).Error(),
+			)
+			return nil, diags
+		}
+	}
+
+	keys, err := listProjectSSHKeys(ctx, client, projectID)
+	if err != nil {
+		diags.AddError(
+			fmt.Sprintf("Failed to get SSHKeys of project %s", projectID),
+			err.Error(),
+		)
+		return nil, diags
+	}
+	return keys, diags
+}
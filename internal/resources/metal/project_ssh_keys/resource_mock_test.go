@@ -0,0 +1,67 @@
+package project_ssh_keys
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testProviderMeta is an empty provider_meta block, which the resource reads
+// the module name of the user agent from
+func testProviderMeta() tfsdk.Config {
+	s := schema.Schema{Attributes: map[string]schema.Attribute{
+		"module_name": schema.StringAttribute{Optional: true},
+	}}
+	return tfsdk.Config{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), map[string]tftypes.Value{
+		"module_name": tftypes.NewValue(tftypes.String, nil),
+	})}
+}
+
+func TestResource_readProjectNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+		// the project was deleted outside of terraform
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": ["Not found"]}`))
+	}))
+	defer mockAPI.Close()
+	meta := &config.Config{BaseURL: mockAPI.URL, Token: "fakeTokenForMock"}
+	if err := meta.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	r := NewResource().(*Resource)
+	r.Meta = meta
+
+	s := *GetResourceSchema()
+	stringMap := tftypes.Map{ElementType: tftypes.String}
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "projectId"),
+		"project_id": tftypes.NewValue(tftypes.String, "projectId"),
+		"keys": tftypes.NewValue(stringMap, map[string]tftypes.Value{
+			"deploy": tftypes.NewValue(tftypes.String, "ssh-ed25519 AAAA"),
+		}),
+		"key_ids": tftypes.NewValue(stringMap, map[string]tftypes.Value{
+			"deploy": tftypes.NewValue(tftypes.String, "keyId"),
+		}),
+	})}
+
+	readResp := &resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{ProviderMeta: testProviderMeta(), State: state}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v, want the resource removed from state", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.IsNull() {
+		t.Errorf("Read() state = %v, want it removed", readResp.State.Raw)
+	}
+}
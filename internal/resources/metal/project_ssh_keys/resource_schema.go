@@ -0,0 +1,39 @@
+package project_ssh_keys
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func GetResourceSchema() *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The ID of the project whose SSH keys are managed (same as project_id)",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description: "The ID of parent project",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"keys": schema.MapAttribute{
+				Description: "Map of SSH key names to public keys. The project SSH keys are reconciled to exactly match this map, keys not listed here are deleted from the project",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"key_ids": schema.MapAttribute{
+				Description: "Map of SSH key names to the IDs of the project SSH keys",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
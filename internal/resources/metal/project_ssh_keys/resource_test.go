@@ -0,0 +1,151 @@
+package project_ssh_keys_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func testAccMetalProjectSSHKeysConfig_basic(name string, keys map[string]string) string {
+	keysBlock := ""
+	for k, v := range keys {
+		keysBlock += fmt.Sprintf("    %q = %q\n", k, v)
+	}
+
+	return fmt.Sprintf(`
+resource "equinix_metal_project" "test" {
+    name = "tfacc-project_ssh_keys-%s"
+}
+
+resource "equinix_metal_project_ssh_keys" "test" {
+    project_id = equinix_metal_project.test.id
+    keys = {
+%s    }
+}
+`, name, keysBlock)
+}
+
+func TestAccMetalProjectSSHKeys_basic(t *testing.T) {
+	var projectID string
+	rs := acctest.RandString(10)
+	r := "equinix_metal_project_ssh_keys.test"
+	keyA, _, err := acctest.RandSSHKeyPair("")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	keyB, _, err := acctest.RandSSHKeyPair("")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+	keyC, _, err := acctest.RandSSHKeyPair("")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheckMetal(t) },
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactories,
+		CheckDestroy:             testAccMetalProjectSSHKeysCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalProjectSSHKeysConfig_basic(rs, map[string]string{"key-a": keyA, "key-b": keyB}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_project.test", "id", r, "project_id"),
+					resource.TestCheckResourceAttr(r, "keys.%", "2"),
+					resource.TestCheckResourceAttr(r, "keys.key-a", keyA),
+					resource.TestCheckResourceAttrSet(r, "key_ids.key-a"),
+					resource.TestCheckResourceAttrSet(r, "key_ids.key-b"),
+				),
+			},
+			{
+				// Removing a key and adding another one is reconciled in place
+				Config: testAccMetalProjectSSHKeysConfig_basic(rs, map[string]string{"key-a": keyA, "key-c": keyC}),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(r, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(r, "keys.%", "2"),
+					resource.TestCheckNoResourceAttr(r, "keys.key-b"),
+					resource.TestCheckResourceAttr(r, "keys.key-c", keyC),
+					testAccMetalProjectSSHKeysProjectID(r, &projectID),
+				),
+			},
+			{
+				// A key added out of band is detected as drift and removed
+				PreConfig: testAccMetalProjectSSHKeysAddOutOfBand(t, &projectID, keyB),
+				Config:    testAccMetalProjectSSHKeysConfig_basic(rs, map[string]string{"key-a": keyA, "key-c": keyC}),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(r, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(r, "keys.%", "2"),
+					resource.TestCheckNoResourceAttr(r, "keys.out-of-band"),
+				),
+			},
+			{
+				ResourceName:      r,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccMetalProjectSSHKeysProjectID(n string, projectID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		*projectID = rs.Primary.Attributes["project_id"]
+		return nil
+	}
+}
+
+func testAccMetalProjectSSHKeysAddOutOfBand(t *testing.T, projectID *string, publicKey string) func() {
+	return func() {
+		client := acceptance.TestAccProvider.Meta().(*config.Config).NewMetalClientForTesting()
+		createRequest := metalv1.SSHKeyCreateInput{
+			Label: metalv1.PtrString("out-of-band"),
+			Key:   metalv1.PtrString(publicKey),
+		}
+		if _, _, err := client.SSHKeysApi.CreateProjectSSHKey(context.TODO(), *projectID).SSHKeyCreateInput(createRequest).Execute(); err != nil {
+			t.Fatalf("failed to create out of band SSH key in project %s: %v", *projectID, err)
+		}
+	}
+}
+
+func testAccMetalProjectSSHKeysCheckDestroyed(s *terraform.State) error {
+	client := acceptance.TestAccProvider.Meta().(*config.Config).Metal
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "equinix_metal_project_ssh_keys" {
+			continue
+		}
+		for k, v := range rs.Primary.Attributes {
+			if !strings.HasPrefix(k, "key_ids.") || k == "key_ids.%" {
+				continue
+			}
+			if _, _, err := client.SSHKeys.Get(v, nil); err == nil {
+				return fmt.Errorf("Metal SSH key still exists")
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package ssh_key
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+)
+
+func NewDataSource() datasource.DataSource {
+	return &DataSource{
+		BaseDataSource: framework.NewBaseDataSource(
+			framework.BaseDataSourceConfig{
+				Name:   "equinix_metal_ssh_key",
+				Schema: &dataSourceSchema,
+			},
+		),
+	}
+}
+
+type DataSource struct {
+	framework.BaseDataSource
+}
+
+func (r *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	// Retrieve values from config
+	var data DataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Use API client to list the SSH keys of the user
+	keysList, _, err := client.SSHKeysApi.FindSSHKeys(ctx).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error listing ssh keys",
+			equinix_errors.FriendlyError(err).Error(),
+		)
+		return
+	}
+
+	key, err := findKey(
+		keysList.GetSshKeys(),
+		data.ID.ValueString(),
+		data.Search.ValueString(),
+		data.Fingerprint.ValueString(),
+		data.MostRecent.ValueBool(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error listing ssh keys",
+			err.Error(),
+		)
+		return
+	}
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(data.parse(key)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update the Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// findKey returns the key with the given id or fingerprint, or the key whose
+// name contains search. A search matching more than one key is an error
+// unless mostRecent is set, in which case the newest of the matches is used.
+func findKey(keys []metalv1.SSHKey, id, search, fingerprint string, mostRecent bool) (*metalv1.SSHKey, error) {
+	var matches []metalv1.SSHKey
+	for _, key := range keys {
+		switch {
+		case id != "" && key.GetId() == id,
+			fingerprint != "" && key.GetFingerprint() == fingerprint,
+			search != "" && strings.Contains(key.GetLabel(), search):
+			matches = append(matches, key)
+		}
+	}
+
+	// only one of id, search and fingerprint is set
+	query := id + search + fingerprint
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("SSH Key matching %q was not found", query)
+	}
+
+	if len(matches) > 1 && !mostRecent {
+		return nil, fmt.Errorf("%d SSH Keys match %q, use a more specific search or set most_recent", len(matches), query)
+	}
+
+	key := matches[0]
+	for _, match := range matches[1:] {
+		if match.GetCreatedAt().After(key.GetCreatedAt()) {
+			key = match
+		}
+	}
+	return &key, nil
+}
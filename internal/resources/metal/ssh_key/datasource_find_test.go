@@ -0,0 +1,47 @@
+package ssh_key
+
+import (
+	"testing"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+func TestFindKey(t *testing.T) {
+	older := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	keys := []metalv1.SSHKey{
+		{Id: metalv1.PtrString("1"), Label: metalv1.PtrString("alice@laptop"), Fingerprint: metalv1.PtrString("aa:bb"), CreatedAt: &older},
+		{Id: metalv1.PtrString("2"), Label: metalv1.PtrString("alice@desktop"), Fingerprint: metalv1.PtrString("cc:dd"), CreatedAt: &newer},
+		{Id: metalv1.PtrString("3"), Label: metalv1.PtrString("bob@laptop"), Fingerprint: metalv1.PtrString("ee:ff"), CreatedAt: &older},
+	}
+
+	tests := []struct {
+		name        string
+		id          string
+		search      string
+		fingerprint string
+		mostRecent  bool
+		wantID      string
+		wantErr     bool
+	}{
+		{name: "id", id: "3", wantID: "3"},
+		{name: "fingerprint", fingerprint: "cc:dd", wantID: "2"},
+		{name: "search", search: "bob", wantID: "3"},
+		{name: "searchAmbiguous", search: "alice", wantErr: true},
+		{name: "searchMostRecent", search: "alice", mostRecent: true, wantID: "2"},
+		{name: "notFound", fingerprint: "00:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := findKey(keys, tt.id, tt.search, tt.fingerprint, tt.mostRecent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && key.GetId() != tt.wantID {
+				t.Errorf("findKey() id = %s, want %s", key.GetId(), tt.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package ssh_key
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var dataSourceSchema = schema.Schema{
+	Attributes: map[string]schema.Attribute{
+		"search": schema.StringAttribute{
+			Description: "A substring of the name of the SSH Key to search for",
+			Optional:    true,
+			Validators: []validator.String{
+				stringvalidator.ExactlyOneOf(path.Expressions{
+					path.MatchRoot("fingerprint"),
+					path.MatchRoot("id"),
+				}...),
+				stringvalidator.LengthAtLeast(1),
+			},
+		},
+		"most_recent": schema.BoolAttribute{
+			Description: "Use the most recently created SSH Key when more than one key matches `search`",
+			Optional:    true,
+		},
+		"id": schema.StringAttribute{
+			Description: "The id of the SSH Key",
+			Optional:    true,
+			Computed:    true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
+			},
+		},
+		"fingerprint": schema.StringAttribute{
+			Description: "The fingerprint of the SSH key",
+			Optional:    true,
+			Computed:    true,
+			Validators: []validator.String{
+				stringvalidator.LengthAtLeast(1),
+			},
+		},
+		"name": schema.StringAttribute{
+			Description: "The label of the Equinix Metal SSH Key",
+			Computed:    true,
+		},
+		"public_key": schema.StringAttribute{
+			Description: "The public key",
+			Computed:    true,
+		},
+		"created": schema.StringAttribute{
+			Description: "The timestamp for when the SSH key was created",
+			Computed:    true,
+		},
+		"updated": schema.StringAttribute{
+			Description: "The timestamp for the last time the SSH key was updated",
+			Computed:    true,
+		},
+		"owner_id": schema.StringAttribute{
+			Description: "The UUID of the Equinix Metal API User who owns this key",
+			Computed:    true,
+		},
+	},
+}
@@ -0,0 +1,96 @@
+package ssh_key_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceMetalSSHKey_bySearchAndFingerprint(t *testing.T) {
+	datasourceName := "data.equinix_metal_ssh_key.foobar"
+	keyName := acctest.RandomWithPrefix("tfacc-user-key")
+
+	publicKeyMaterial, _, err := acctest.RandSSHKeyPair("")
+	if err != nil {
+		t.Fatalf("Cannot generate test SSH key pair: %s", err)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                  func() { acceptance.TestAccPreCheckMetal(t) },
+		ProtoV5ProviderFactories:  acceptance.ProtoV5ProviderFactories,
+		PreventPostDestroyRefresh: true,
+		CheckDestroy:              testAccMetalSSHKeyCheckDestroyed,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceMetalSSHKeyConfig_bySearch(keyName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						datasourceName, "name", keyName),
+					resource.TestCheckResourceAttr(
+						datasourceName, "public_key", publicKeyMaterial),
+					resource.TestCheckResourceAttrPair(
+						datasourceName, "id", "equinix_metal_ssh_key.foobar", "id"),
+					resource.TestCheckResourceAttrSet(
+						datasourceName, "owner_id"),
+				),
+			},
+			{
+				Config: testAccDataSourceMetalSSHKeyConfig_byFingerprint(keyName, publicKeyMaterial),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						datasourceName, "name", keyName),
+					resource.TestCheckResourceAttrPair(
+						datasourceName, "id", "equinix_metal_ssh_key.foobar", "id"),
+				),
+			},
+			{
+				Config:      testAccDataSourceMetalSSHKeyConfig_noKey(keyName),
+				ExpectError: regexp.MustCompile("was not found"),
+			},
+			{
+				// Exit the tests with an empty state and a valid config
+				// following the previous error config. This is needed for the
+				// destroy step to succeed.
+				Config: `/* this config intentionally left blank */`,
+			},
+		},
+	})
+}
+
+func testAccDataSourceMetalSSHKeyConfig_bySearch(keyName, publicSshKey string) string {
+	return fmt.Sprintf(`
+resource "equinix_metal_ssh_key" "foobar" {
+	name = "%s"
+	public_key = "%s"
+}
+
+data "equinix_metal_ssh_key" "foobar" {
+	search = equinix_metal_ssh_key.foobar.name
+}
+`, keyName, publicSshKey)
+}
+
+func testAccDataSourceMetalSSHKeyConfig_byFingerprint(keyName, publicSshKey string) string {
+	return fmt.Sprintf(`
+resource "equinix_metal_ssh_key" "foobar" {
+	name = "%s"
+	public_key = "%s"
+}
+
+data "equinix_metal_ssh_key" "foobar" {
+	fingerprint = equinix_metal_ssh_key.foobar.fingerprint
+}
+`, keyName, publicSshKey)
+}
+
+func testAccDataSourceMetalSSHKeyConfig_noKey(keyName string) string {
+	return fmt.Sprintf(`
+data "equinix_metal_ssh_key" "foobar" {
+	search = "%s"
+}
+`, keyName)
+}
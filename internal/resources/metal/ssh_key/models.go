@@ -30,3 +30,42 @@ func (m *ResourceModel) parse(key *metalv1.SSHKey) diag.Diagnostics {
 
 	return nil
 }
+
+type DataSourceModel struct {
+	Search      types.String `tfsdk:"search"`
+	MostRecent  types.Bool   `tfsdk:"most_recent"`
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	PublicKey   types.String `tfsdk:"public_key"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+	Created     types.String `tfsdk:"created"`
+	Updated     types.String `tfsdk:"updated"`
+	OwnerID     types.String `tfsdk:"owner_id"`
+}
+
+func (m *DataSourceModel) parse(key *metalv1.SSHKey) diag.Diagnostics {
+	m.ID = types.StringValue(key.GetId())
+	m.Name = types.StringValue(key.GetLabel())
+	m.PublicKey = types.StringValue(key.GetKey())
+	m.Fingerprint = types.StringValue(key.GetFingerprint())
+	m.Created = types.StringValue(key.CreatedAt.GoString())
+	m.Updated = types.StringValue(key.UpdatedAt.GoString())
+	m.OwnerID = ownerID(key)
+
+	return nil
+}
+
+// ownerID returns the ID of the owner of the key, from the href of the owner
+// the API returns along with the key. It is null when the owner is missing or
+// in another shape.
+func ownerID(key *metalv1.SSHKey) types.String {
+	owner, ok := key.AdditionalProperties["owner"].(map[string]interface{})
+	if !ok {
+		return types.StringNull()
+	}
+	href, ok := owner["href"].(string)
+	if !ok {
+		return types.StringNull()
+	}
+	return types.StringValue(path.Base(href))
+}
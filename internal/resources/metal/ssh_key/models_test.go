@@ -0,0 +1,40 @@
+package ssh_key
+
+import (
+	"testing"
+	"time"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDataSourceModelParse_owner(t *testing.T) {
+	tests := []struct {
+		name  string
+		owner interface{}
+		want  types.String
+	}{
+		{name: "owner", owner: map[string]interface{}{"href": "/metal/v1/users/ownerId"}, want: types.StringValue("ownerId")},
+		{name: "missing", want: types.StringNull()},
+		{name: "other shape", owner: "/metal/v1/users/ownerId", want: types.StringNull()},
+		{name: "no href", owner: map[string]interface{}{"id": "ownerId"}, want: types.StringNull()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			key := &metalv1.SSHKey{Id: metalv1.PtrString("keyId"), CreatedAt: &created, UpdatedAt: &created, AdditionalProperties: map[string]interface{}{}}
+			if tt.owner != nil {
+				key.AdditionalProperties["owner"] = tt.owner
+			}
+
+			var m DataSourceModel
+			if diags := m.parse(key); diags.HasError() {
+				t.Fatalf("parse() = %v", diags)
+			}
+			if !m.OwnerID.Equal(tt.want) {
+				t.Errorf("parse() owner_id = %v, want %v", m.OwnerID, tt.want)
+			}
+		})
+	}
+}
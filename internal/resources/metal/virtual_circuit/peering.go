@@ -0,0 +1,44 @@
+package virtual_circuit
+
+import (
+	"context"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// customizeDiffPeeringIPs marks the IPs the API picks from the subnet as
+// unknown when the subnet of a VRF virtual circuit changes, as the IPs of the
+// old subnet are replaced. IPs set in the configuration are kept.
+func customizeDiffPeeringIPs(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("subnet") {
+		return nil
+	}
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+	for _, attr := range []string{"metal_ip", "customer_ip"} {
+		if !raw.GetAttr(attr).IsNull() {
+			continue
+		}
+		if err := d.SetNewComputed(attr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setVrfUpdateMd5 sets the md5 password of a VRF virtual circuit update. An
+// empty password removes it: the typed field can not hold null, so null is
+// sent as an additional property instead.
+func setVrfUpdateMd5(input *metalv1.VrfVirtualCircuitUpdateInput, md5 string) {
+	if md5 != "" {
+		input.Md5 = metalv1.PtrString(md5)
+		return
+	}
+	if input.AdditionalProperties == nil {
+		input.AdditionalProperties = map[string]interface{}{}
+	}
+	input.AdditionalProperties["md5"] = nil
+}
@@ -0,0 +1,120 @@
+package virtual_circuit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestCustomizeDiffPeeringIPs(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "vcId",
+		Attributes: map[string]string{
+			"id":            "vcId",
+			"connection_id": "connection",
+			"project_id":    "project",
+			"port_id":       "port",
+			"vrf_id":        "vrf",
+			"peer_asn":      "65530",
+			"subnet":        "192.168.100.16/31",
+			"metal_ip":      "192.168.100.16",
+			"customer_ip":   "192.168.100.17",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantUnknown []string
+		wantKept    []string
+	}{
+		{
+			name:     "subnet unchanged",
+			config:   map[string]interface{}{"subnet": "192.168.100.16/31"},
+			wantKept: []string{"metal_ip", "customer_ip"},
+		},
+		{
+			name:        "subnet changed",
+			config:      map[string]interface{}{"subnet": "192.168.100.32/31"},
+			wantUnknown: []string{"metal_ip", "customer_ip"},
+		},
+		{
+			name:        "subnet changed with a configured metal_ip",
+			config:      map[string]interface{}{"subnet": "192.168.100.32/31", "metal_ip": "192.168.100.33"},
+			wantUnknown: []string{"customer_ip"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{
+				"connection_id": "connection",
+				"project_id":    "project",
+				"port_id":       "port",
+				"vrf_id":        "vrf",
+				"peer_asn":      65530,
+			}
+			for k, v := range tt.config {
+				raw[k] = v
+			}
+
+			// Terraform sends the raw config along with the state, the IPs
+			// are only marked unknown when it does not set them
+			r := Resource()
+			rawJSON, err := json.Marshal(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rawConfig, err := ctyjson.Unmarshal(rawJSON, r.CoreConfigSchema().ImpliedType())
+			if err != nil {
+				t.Fatal(err)
+			}
+			state := state.DeepCopy()
+			state.RawConfig = rawConfig
+
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			for _, attr := range tt.wantUnknown {
+				if diff == nil || diff.Attributes[attr] == nil || !diff.Attributes[attr].NewComputed {
+					t.Errorf("Diff() %s is not unknown, want it to be picked from the new subnet", attr)
+				}
+			}
+			for _, attr := range tt.wantKept {
+				if diff != nil && diff.Attributes[attr] != nil {
+					t.Errorf("Diff() %s = %v, want no change", attr, diff.Attributes[attr])
+				}
+			}
+		})
+	}
+}
+
+func TestSetVrfUpdateMd5(t *testing.T) {
+	tests := []struct {
+		name string
+		md5  string
+		want string
+	}{
+		{name: "set", md5: "2SFsdfsg43", want: `{"md5":"2SFsdfsg43"}`},
+		{name: "removed", want: `{"md5":null}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := &metalv1.VrfVirtualCircuitUpdateInput{}
+			setVrfUpdateMd5(input, tt.md5)
+			got, err := json.Marshal(input)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
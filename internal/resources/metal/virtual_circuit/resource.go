@@ -14,6 +14,7 @@ import (
 	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
 
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/wait"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
@@ -29,6 +30,7 @@ func Resource() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customizeDiffPeeringIPs,
 
 		Schema: map[string]*schema.Schema{
 			"connection_id": {
@@ -89,40 +91,43 @@ func Resource() *schema.Resource {
 				Optional:     true,
 				Description:  "UUID of the VRF to associate",
 				ExactlyOneOf: []string{"vlan_id", "vrf_id"},
+				RequiredWith: []string{"peer_asn", "subnet"},
 				ForceNew:     true,
 			},
 			"peer_asn": {
-				Type:         schema.TypeInt,
-				Optional:     true,
-				RequiredWith: []string{"vrf_id"},
-				Description:  "The BGP ASN of the peer. The same ASN may be the used across several VCs, but it cannot be the same as the local_asn of the VRF.",
-				ForceNew:     true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"vlan_id"},
+				Description:   "The BGP ASN of the peer. The same ASN may be the used across several VCs, but it cannot be the same as the local_asn of the VRF.",
 			},
 			"subnet": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				RequiredWith: []string{"vrf_id"},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"vlan_id"},
 				Description: `A subnet from one of the IP blocks associated with the VRF that we will help create an IP reservation for. Can only be either a /30 or /31.
 				 * For a /31 block, it will only have two IP addresses, which will be used for the metal_ip and customer_ip.
 				 * For a /30 block, it will have four IP addresses, but the first and last IP addresses are not usable. We will default to the first usable IP address for the metal_ip.`,
 			},
 			"metal_ip": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				RequiredWith: []string{"vrf_id"},
-				Description:  "The Metal IP address for the SVI (Switch Virtual Interface) of the VirtualCircuit. Will default to the first usable IP in the subnet.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"vlan_id"},
+				Description:   "The Metal IP address for the SVI (Switch Virtual Interface) of the VirtualCircuit. Will default to the first usable IP in the subnet.",
 			},
 			"customer_ip": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				RequiredWith: []string{"vrf_id"},
-				Description:  "The Customer IP address which the CSR switch will peer with. Will default to the other usable IP in the subnet.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"vlan_id"},
+				Description:   "The Customer IP address which the CSR switch will peer with. Will default to the other usable IP in the subnet.",
 			},
 			"md5": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Sensitive:   true,
-				Description: "The password that can be set for the VRF BGP peer",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"vlan_id"},
+				Description:   "The password that can be set for the VRF BGP peer",
 			},
 
 			"vnid": {
@@ -145,6 +150,7 @@ func Resource() *schema.Resource {
 }
 
 func resourceMetalVirtualCircuitCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	start := time.Now()
 	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	vncr := metalv1.VirtualCircuitCreateInput{}
 
@@ -169,11 +175,16 @@ func resourceMetalVirtualCircuitCreate(ctx context.Context, d *schema.ResourceDa
 			Speed:       metalv1.PtrString(d.Get("speed").(string)),
 			Vrf:         d.Get("vrf_id").(string),
 			// TODO: woof
-			Md5:        *metalv1.NewNullableString(metalv1.PtrString(d.Get("md5").(string))),
-			PeerAsn:    int64(d.Get("peer_asn").(int)),
-			Subnet:     d.Get("subnet").(string),
-			CustomerIp: metalv1.PtrString(d.Get("customer_ip").(string)),
-			MetalIp:    metalv1.PtrString(d.Get("metal_ip").(string)),
+			Md5:     *metalv1.NewNullableString(metalv1.PtrString(d.Get("md5").(string))),
+			PeerAsn: int64(d.Get("peer_asn").(int)),
+			Subnet:  d.Get("subnet").(string),
+		}
+		// the API picks the IPs from the subnet when they are not set
+		if customerIP, ok := d.GetOk("customer_ip"); ok {
+			vncr.VrfVirtualCircuitCreateInput.CustomerIp = metalv1.PtrString(customerIP.(string))
+		}
+		if metalIP, ok := d.GetOk("metal_ip"); ok {
+			vncr.VrfVirtualCircuitCreateInput.MetalIp = metalv1.PtrString(metalIP.(string))
 		}
 	}
 
@@ -216,13 +227,17 @@ func resourceMetalVirtualCircuitCreate(ctx context.Context, d *schema.ResourceDa
 		vcId = vc.VrfVirtualCircuit.GetId()
 	}
 
-	// TODO: offer to wait while VCStatusPending
+	// VLAN and VRF virtual circuits share the pending, activating and
+	// active status values
 	createWaiter := getVCStateWaiter(
 		ctx,
 		client,
 		vcId,
-		d.Timeout(schema.TimeoutCreate)-30*time.Second,
-		[]string{string(metalv1.VLANVIRTUALCIRCUITSTATUS_ACTIVATING)},
+		wait.Remaining(d.Timeout(schema.TimeoutCreate), start),
+		[]string{
+			string(metalv1.VLANVIRTUALCIRCUITSTATUS_PENDING),
+			string(metalv1.VLANVIRTUALCIRCUITSTATUS_ACTIVATING),
+		},
 		[]string{string(metalv1.VLANVIRTUALCIRCUITSTATUS_ACTIVE)},
 	)
 
@@ -310,38 +325,33 @@ func resourceMetalVirtualCircuitRead(ctx context.Context, d *schema.ResourceData
 }
 
 func getVCStateWaiter(ctx context.Context, client *metalv1.APIClient, id string, timeout time.Duration, pending, target []string) *retry.StateChangeConf {
-	return &retry.StateChangeConf{
-		Pending: pending,
-		Target:  target,
-		Refresh: func() (interface{}, string, error) {
-			vc, resp, err := client.InterconnectionsApi.GetVirtualCircuit(ctx, id).Execute()
-			if err != nil {
-				if resp != nil {
-					// The resource delete function uses this waiter and relies
-					// on it to return an ErrorResponse error so it can treat
-					// a 404 as success.  This conversion is done here for now
-					// to avoid a larger refactoring.
-					err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
-				}
-				return 0, "", err
-			}
-			vcStatus := ""
-			if vc.VlanVirtualCircuit != nil {
-				vcStatus = string(vc.VlanVirtualCircuit.GetStatus())
-			} else {
-				vcStatus = string(vc.VrfVirtualCircuit.GetStatus())
+	return wait.StateConf(func() (interface{}, string, error) {
+		vc, resp, err := client.InterconnectionsApi.GetVirtualCircuit(ctx, id).Execute()
+		if err != nil {
+			if resp != nil {
+				// The resource delete function uses this waiter and relies
+				// on it to return an ErrorResponse error so it can treat
+				// a 404 as success.  This conversion is done here for now
+				// to avoid a larger refactoring.
+				err = equinix_errors.FriendlyErrorForMetalGo(err, resp)
 			}
-			return vc, vcStatus, nil
-		},
-		Timeout:    timeout,
-		Delay:      10 * time.Second,
-		MinTimeout: 5 * time.Second,
-	}
+			return 0, "", err
+		}
+		vcStatus := ""
+		if vc.VlanVirtualCircuit != nil {
+			vcStatus = string(vc.VlanVirtualCircuit.GetStatus())
+		} else {
+			vcStatus = string(vc.VrfVirtualCircuit.GetStatus())
+		}
+		return vc, vcStatus, nil
+	}, pending, target, timeout, wait.WithDelay(wait.DefaultDelay), wait.WithMinTimeout(5*time.Second))
 }
 
 func resourceMetalVirtualCircuitUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	start := time.Now()
 	client := meta.(*config.Config).NewMetalClientForSDK(d)
 	needsUpdate := false
+	peeringChanged := false
 
 	ur := metalv1.VirtualCircuitUpdateInput{}
 
@@ -422,6 +432,36 @@ func resourceMetalVirtualCircuitUpdate(ctx context.Context, d *schema.ResourceDa
 				return diag.Errorf("garbage in tags: %s", ts)
 			}
 		}
+
+		if d.HasChange("peer_asn") {
+			needsUpdate = true
+			peeringChanged = true
+			ur.VrfVirtualCircuitUpdateInput.PeerAsn = metalv1.PtrInt64(int64(d.Get("peer_asn").(int)))
+		}
+
+		if d.HasChange("subnet") {
+			needsUpdate = true
+			peeringChanged = true
+			ur.VrfVirtualCircuitUpdateInput.Subnet = metalv1.PtrString(d.Get("subnet").(string))
+		}
+
+		if d.HasChange("metal_ip") {
+			needsUpdate = true
+			peeringChanged = true
+			ur.VrfVirtualCircuitUpdateInput.MetalIp = metalv1.PtrString(d.Get("metal_ip").(string))
+		}
+
+		if d.HasChange("customer_ip") {
+			needsUpdate = true
+			peeringChanged = true
+			ur.VrfVirtualCircuitUpdateInput.CustomerIp = metalv1.PtrString(d.Get("customer_ip").(string))
+		}
+
+		if d.HasChange("md5") {
+			needsUpdate = true
+			peeringChanged = true
+			setVrfUpdateMd5(ur.VrfVirtualCircuitUpdateInput, d.Get("md5").(string))
+		}
 	}
 
 	if needsUpdate {
@@ -429,10 +469,25 @@ func resourceMetalVirtualCircuitUpdate(ctx context.Context, d *schema.ResourceDa
 			return diag.FromErr(err)
 		}
 	}
+
+	if peeringChanged {
+		updateWaiter := getVCStateWaiter(
+			ctx,
+			client,
+			d.Id(),
+			wait.Remaining(d.Timeout(schema.TimeoutUpdate), start),
+			[]string{string(metalv1.VRFVIRTUALCIRCUITSTATUS_CHANGING_PEERING_DETAILS)},
+			[]string{string(metalv1.VRFVIRTUALCIRCUITSTATUS_ACTIVE)},
+		)
+		if _, err := updateWaiter.WaitForStateContext(ctx); err != nil {
+			return diag.Errorf("Error waiting for peering details of virtual circuit %s to be changed: %s", d.Id(), err.Error())
+		}
+	}
 	return resourceMetalVirtualCircuitRead(ctx, d, meta)
 }
 
 func resourceMetalVirtualCircuitDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	start := time.Now()
 	client := meta.(*config.Config).NewMetalClientForSDK(d)
 
 	_, resp, err := client.InterconnectionsApi.DeleteVirtualCircuit(ctx, d.Id()).Execute()
@@ -452,7 +507,7 @@ func resourceMetalVirtualCircuitDelete(ctx context.Context, d *schema.ResourceDa
 		ctx,
 		client,
 		d.Id(),
-		d.Timeout(schema.TimeoutDelete)-30*time.Second,
+		wait.Remaining(d.Timeout(schema.TimeoutDelete), start),
 		[]string{string(metalv1.VLANVIRTUALCIRCUITSTATUS_DELETING)},
 		[]string{},
 	)
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
 	"github.com/equinix/terraform-provider-equinix/internal/config"
+	"github.com/equinix/terraform-provider-equinix/internal/resources/metal/virtual_circuit"
 
+	sdkterraform "github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -130,3 +133,61 @@ func TestAccMetalVirtualCircuit_dedicated(t *testing.T) {
 		},
 	})
 }
+
+func TestMetalVirtualCircuit_vrfValidation(t *testing.T) {
+	base := map[string]interface{}{
+		"connection_id": "connection",
+		"project_id":    "project",
+		"port_id":       "port",
+	}
+	tests := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "vlan",
+			raw:  map[string]interface{}{"vlan_id": "vlan"},
+		},
+		{
+			name: "vrf",
+			raw:  map[string]interface{}{"vrf_id": "vrf", "peer_asn": 65530, "subnet": "192.168.100.16/31"},
+		},
+		{
+			name:    "vrf without peering details",
+			raw:     map[string]interface{}{"vrf_id": "vrf"},
+			wantErr: "peer_asn",
+		},
+		{
+			name:    "vlan with peering details",
+			raw:     map[string]interface{}{"vlan_id": "vlan", "customer_ip": "192.168.100.17"},
+			wantErr: "conflicts with vlan_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := map[string]interface{}{}
+			for k, v := range base {
+				raw[k] = v
+			}
+			for k, v := range tt.raw {
+				raw[k] = v
+			}
+			diags := virtual_circuit.Resource().Validate(sdkterraform.NewResourceConfigRaw(raw))
+			if tt.wantErr == "" {
+				if diags.HasError() {
+					t.Fatalf("Validate() = %v, want no error", diags)
+				}
+				return
+			}
+			found := false
+			for _, d := range diags {
+				found = found || strings.Contains(d.Summary+d.Detail, tt.wantErr)
+			}
+			if !found {
+				t.Errorf("Validate() = %v, want an error about %q", diags, tt.wantErr)
+			}
+		})
+	}
+}
@@ -125,7 +125,12 @@ func MatchingVlan(vlans []packngo.VirtualNetwork, vxlan int, projectID, facility
 		matches = append(matches, v)
 	}
 	if len(matches) > 1 {
-		return nil, equinix_errors.FriendlyError(fmt.Errorf("Project %s has more than one matching VLAN", projectID))
+		found := make([]string, 0, len(matches))
+		for _, v := range matches {
+			found = append(found, fmt.Sprintf("%s (vxlan %d, metro %s)", v.ID, v.VXLAN, v.MetroCode))
+		}
+		return nil, equinix_errors.FriendlyError(fmt.Errorf("Project %s has more than one matching VLAN, set vxlan and metro or use vlan_id: %s",
+			projectID, strings.Join(found, ", ")))
 	}
 
 	if len(matches) == 0 {
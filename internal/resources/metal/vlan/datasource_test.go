@@ -3,6 +3,7 @@ package vlan_test
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
@@ -277,6 +278,27 @@ func TestMetalVlan_matchingVlan(t *testing.T) {
 	}
 }
 
+func TestMetalVlan_matchingVlanListsMatches(t *testing.T) {
+	vlans := []packngo.VirtualNetwork{
+		{ID: "vlan-1", VXLAN: 1001, MetroCode: "da"},
+		{ID: "vlan-2", VXLAN: 1002, MetroCode: "da"},
+		{ID: "vlan-3", VXLAN: 1003, MetroCode: "sv"},
+	}
+
+	_, err := vlan.MatchingVlan(vlans, 0, "pid", "", "da")
+	if err == nil {
+		t.Fatal("matchingVlan() expected an error for two matching VLANs")
+	}
+	for _, want := range []string{"vlan-1 (vxlan 1001, metro da)", "vlan-2 (vxlan 1002, metro da)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("matchingVlan() error = %q, want it to mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "vlan-3") {
+		t.Errorf("matchingVlan() error = %q, should not mention vlan-3", err)
+	}
+}
+
 func testAccMetalDatasourceVlanCheckDestroyed(s *terraform.State) error {
 	client := acceptance.TestAccProvider.Meta().(*config.Config).Metal
 
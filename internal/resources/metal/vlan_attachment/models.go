@@ -0,0 +1,102 @@
+package vlanattachment
+
+import (
+	"context"
+	"path"
+	"slices"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	VlanID  types.String `tfsdk:"vlan_id"`
+	PortIDs types.Set    `tfsdk:"port_ids"`
+}
+
+func (m *ResourceModel) portIDs(ctx context.Context) ([]string, diag.Diagnostics) {
+	portIDs := []string{}
+	if m.PortIDs.IsNull() || m.PortIDs.IsUnknown() {
+		return portIDs, nil
+	}
+	diags := m.PortIDs.ElementsAs(ctx, &portIDs, false)
+	slices.Sort(portIDs)
+	return portIDs, diags
+}
+
+func (m *ResourceModel) setPortIDs(ctx context.Context, portIDs []string) diag.Diagnostics {
+	portIDsSet, diags := types.SetValueFrom(ctx, types.StringType, portIDs)
+	m.PortIDs = portIDsSet
+	return diags
+}
+
+// parse keeps the ports of the attachment that the VLAN is still attached to,
+// as reported by each of the ports. Without known ports, as after an import,
+// all the ports the instances of the VLAN report are used.
+func (m *ResourceModel) parse(ctx context.Context, vlan *metalv1.VirtualNetwork, attached []string) diag.Diagnostics {
+	m.ID = types.StringValue(vlan.GetId())
+	m.VlanID = types.StringValue(vlan.GetId())
+
+	portIDs := []string{}
+	if m.PortIDs.IsNull() {
+		portIDs = attached
+	} else {
+		known, diags := m.portIDs(ctx)
+		if diags.HasError() {
+			return diags
+		}
+		for _, id := range known {
+			if slices.Contains(attached, id) {
+				portIDs = append(portIDs, id)
+			}
+		}
+	}
+	return m.setPortIDs(ctx, portIDs)
+}
+
+// attachedPortIDs returns the sorted IDs of the device ports the VLAN is
+// attached to, as reported in the instances of the VLAN
+func attachedPortIDs(vlan *metalv1.VirtualNetwork) []string {
+	ids := []string{}
+	for _, device := range vlan.GetInstances() {
+		for _, port := range device.GetNetworkPorts() {
+			if portHasVlan(&port, vlan.GetId()) && !slices.Contains(ids, port.GetId()) {
+				ids = append(ids, port.GetId())
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// portHasVlan reports whether the virtual networks of a port include the VLAN
+func portHasVlan(port *metalv1.Port, vlanID string) bool {
+	for _, vn := range port.GetVirtualNetworks() {
+		vnID := vn.GetId()
+		if vnID == "" {
+			vnID = path.Base(vn.GetHref())
+		}
+		if vnID == vlanID {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPortIDs returns the ports to attach and to detach to go from the old to
+// the new set of ports
+func diffPortIDs(old, new []string) (attach, detach []string) {
+	for _, id := range new {
+		if !slices.Contains(old, id) {
+			attach = append(attach, id)
+		}
+	}
+	for _, id := range old {
+		if !slices.Contains(new, id) {
+			detach = append(detach, id)
+		}
+	}
+	return attach, detach
+}
@@ -0,0 +1,44 @@
+package vlanattachment
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+)
+
+func TestAttachedPortIDs(t *testing.T) {
+	vlan := &metalv1.VirtualNetwork{
+		Id: metalv1.PtrString("vlan"),
+		Instances: []metalv1.Device{
+			{NetworkPorts: []metalv1.Port{
+				{Id: metalv1.PtrString("port2"), VirtualNetworks: []metalv1.VirtualNetwork{
+					{Href: metalv1.PtrString("/metal/v1/virtual-networks/vlan")},
+				}},
+				{Id: metalv1.PtrString("port3"), VirtualNetworks: []metalv1.VirtualNetwork{
+					{Href: metalv1.PtrString("/metal/v1/virtual-networks/other")},
+				}},
+			}},
+			{NetworkPorts: []metalv1.Port{
+				{Id: metalv1.PtrString("port1"), VirtualNetworks: []metalv1.VirtualNetwork{
+					{Id: metalv1.PtrString("vlan")},
+				}},
+			}},
+		},
+	}
+
+	want := []string{"port1", "port2"}
+	if got := attachedPortIDs(vlan); !reflect.DeepEqual(got, want) {
+		t.Errorf("attachedPortIDs() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffPortIDs(t *testing.T) {
+	attach, detach := diffPortIDs([]string{"port1", "port2"}, []string{"port2", "port3"})
+	if want := []string{"port3"}; !reflect.DeepEqual(attach, want) {
+		t.Errorf("diffPortIDs() attach = %v, want %v", attach, want)
+	}
+	if want := []string{"port1"}; !reflect.DeepEqual(detach, want) {
+		t.Errorf("diffPortIDs() detach = %v, want %v", detach, want)
+	}
+}
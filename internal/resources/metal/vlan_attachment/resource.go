@@ -0,0 +1,241 @@
+package vlanattachment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	equinix_errors "github.com/equinix/terraform-provider-equinix/internal/errors"
+	"github.com/equinix/terraform-provider-equinix/internal/framework"
+	"github.com/equinix/terraform-provider-equinix/internal/mutexkv"
+
+	"github.com/equinix/equinix-sdk-go/services/metalv1"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+type Resource struct {
+	framework.BaseResource
+}
+
+func NewResource() resource.Resource {
+	r := Resource{
+		BaseResource: framework.NewBaseResource(
+			framework.BaseResourceConfig{
+				Name: "equinix_metal_vlan_attachment",
+			},
+		),
+	}
+
+	return &r
+}
+
+func (r *Resource) Schema(
+	ctx context.Context,
+	req resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = resourceSchema(ctx)
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	var plan ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vlanID := plan.VlanID.ValueString()
+	portIDs, diags := plan.portIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	attached, err := attachPorts(ctx, client, vlanID, portIDs)
+	if err != nil {
+		// Keep the ports that were attached in the state, so that they are
+		// detached when the tainted attachment is replaced
+		if len(attached) > 0 {
+			plan.ID = plan.VlanID
+			resp.Diagnostics.Append(plan.setPortIDs(ctx, attached)...)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		}
+		resp.Diagnostics.AddError("Error attaching Metal VLAN to ports", err.Error())
+		return
+	}
+
+	// The VLAN was attached to all the planned ports, which the ports may
+	// take a while to report, so the plan is kept rather than read back
+	plan.ID = plan.VlanID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.VlanID.IsNull() {
+		// imported by the ID of the VLAN
+		state.VlanID = state.ID
+	}
+
+	vlanID := state.VlanID.ValueString()
+	vlan, httpResp, err := client.VLANsApi.GetVirtualNetwork(ctx, vlanID).Include([]string{"instances"}).Execute()
+	if err != nil {
+		if httpResp != nil && httpResp.StatusCode == http.StatusNotFound {
+			resp.Diagnostics.AddWarning(
+				"Equinix Metal VLAN not found during refresh",
+				fmt.Sprintf("[WARN] VLAN (%s) not found, removing VLAN attachment from state", vlanID),
+			)
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading Metal VLAN "+vlanID, equinix_errors.FriendlyErrorForMetalGo(err, httpResp).Error())
+		return
+	}
+
+	attached := attachedPortIDs(vlan)
+	if !state.PortIDs.IsNull() {
+		portIDs, diags := state.portIDs(ctx)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		attached, err = portsWithVlan(ctx, client, vlanID, portIDs)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading ports of Metal VLAN "+vlanID, err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(state.parse(ctx, vlan, attached)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	var state, plan ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	vlanID := plan.VlanID.ValueString()
+	oldPortIDs, diags := state.portIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	newPortIDs, diags := plan.portIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Ports are detached first, so that a port that is moved to another
+	// attachment by the same apply can be attached there
+	attach, detach := diffPortIDs(oldPortIDs, newPortIDs)
+	detached, err := detachPorts(ctx, client, vlanID, detach)
+	attached := []string{}
+	if err == nil {
+		attached, err = attachPorts(ctx, client, vlanID, attach)
+	}
+	if err != nil {
+		// Record the ports the VLAN is still attached to: those that were
+		// not detached and those that were attached before the error
+		remaining, _ := diffPortIDs(detached, oldPortIDs)
+		resp.Diagnostics.Append(state.setPortIDs(ctx, append(remaining, attached...))...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		resp.Diagnostics.AddError("Error updating ports of Metal VLAN "+vlanID, err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	client := r.Meta.NewMetalClientForFramework(ctx, req.ProviderMeta)
+
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	portIDs, diags := state.portIDs(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := detachPorts(ctx, client, state.VlanID.ValueString(), portIDs); err != nil {
+		resp.Diagnostics.AddError("Error detaching Metal VLAN from ports", err.Error())
+	}
+}
+
+// portsWithVlan returns the ports the VLAN is attached to, as reported by each
+// of the ports. The instances of a VLAN do not always list the virtual networks
+// of their ports, or list them late, so the ports are looked up one by one.
+// Ports that no longer exist are not attached.
+func portsWithVlan(ctx context.Context, client *metalv1.APIClient, vlanID string, portIDs []string) ([]string, error) {
+	attached := []string{}
+	for _, portID := range portIDs {
+		port, resp, err := client.PortsApi.FindPortById(ctx, portID).Include([]string{"virtual_networks"}).Execute()
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return nil, fmt.Errorf("could not read port %s: %w", portID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		}
+		if portHasVlan(port, vlanID) {
+			attached = append(attached, portID)
+		}
+	}
+	return attached, nil
+}
+
+// attachPorts attaches the VLAN to the ports and returns the ports it was
+// attached to, up to the first error
+func attachPorts(ctx context.Context, client *metalv1.APIClient, vlanID string, portIDs []string) ([]string, error) {
+	attached := []string{}
+	for _, portID := range portIDs {
+		// Equinix Metal doesn't allow multiple VLANs to be assigned
+		// to the same port at the same time
+		lockID := "vlan-attachment-" + portID
+		mutexkv.Metal.Lock(lockID)
+		_, resp, err := client.PortsApi.AssignPort(ctx, portID).PortAssignInput(metalv1.PortAssignInput{Vnid: &vlanID}).Execute()
+		mutexkv.Metal.Unlock(lockID)
+		if err != nil {
+			return attached, fmt.Errorf("could not attach VLAN %s to port %s: %w", vlanID, portID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		}
+		attached = append(attached, portID)
+	}
+	return attached, nil
+}
+
+// detachPorts detaches the VLAN from the ports and returns the ports it was
+// detached from, up to the first error. Ports that no longer exist are
+// considered detached.
+func detachPorts(ctx context.Context, client *metalv1.APIClient, vlanID string, portIDs []string) ([]string, error) {
+	detached := []string{}
+	for _, portID := range portIDs {
+		lockID := "vlan-attachment-" + portID
+		mutexkv.Metal.Lock(lockID)
+		_, resp, err := client.PortsApi.UnassignPort(ctx, portID).PortAssignInput(metalv1.PortAssignInput{Vnid: &vlanID}).Execute()
+		mutexkv.Metal.Unlock(lockID)
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return detached, fmt.Errorf("could not detach VLAN %s from port %s: %w", vlanID, portID, equinix_errors.FriendlyErrorForMetalGo(err, resp))
+		}
+		detached = append(detached, portID)
+	}
+	return detached, nil
+}
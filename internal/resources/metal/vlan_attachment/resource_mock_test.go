@@ -0,0 +1,209 @@
+package vlanattachment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/config"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// mockPortsAPI serves the VLAN and the ports the tests attach it to. The VLAN
+// does not list the ports of its instances, so that the attachment has to
+// read its ports one by one.
+type mockPortsAPI struct {
+	mu       sync.Mutex
+	attached map[string]bool
+	failPort string
+}
+
+func (m *mockPortsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w.Header().Add("Content-Type", "application/json")
+	w.Header().Add("X-Request-Id", "needed for equinix_errors.FriendlyError")
+
+	parts := strings.FieldsFunc(strings.TrimPrefix(r.URL.Path, "/metal/v1"), func(c rune) bool { return c == '/' })
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "virtual-networks" && parts[1] == "vlanId":
+		w.Write([]byte(`{"id": "vlanId", "instances": [{"href": "/metal/v1/devices/deviceId"}]}`))
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "ports" && parts[2] == "assign":
+		if parts[1] == m.failPort {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors": ["port can not be assigned"]}`))
+			return
+		}
+		m.attached[parts[1]] = true
+		m.writePort(w, parts[1])
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "ports" && parts[2] == "unassign":
+		delete(m.attached, parts[1])
+		m.writePort(w, parts[1])
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "ports":
+		m.writePort(w, parts[1])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": ["not found"]}`))
+	}
+}
+
+func (m *mockPortsAPI) writePort(w http.ResponseWriter, portID string) {
+	port := map[string]interface{}{"id": portID, "virtual_networks": []interface{}{}}
+	if m.attached[portID] {
+		port["virtual_networks"] = []interface{}{map[string]interface{}{"id": "vlanId"}}
+	}
+	json.NewEncoder(w).Encode(port)
+}
+
+func testVlanAttachmentResource(t *testing.T, mock *mockPortsAPI) *Resource {
+	mockAPI := httptest.NewServer(mock)
+	t.Cleanup(mockAPI.Close)
+	meta := &config.Config{BaseURL: mockAPI.URL, Token: "fakeTokenForMock"}
+	if err := meta.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r := NewResource().(*Resource)
+	r.Meta = meta
+	return r
+}
+
+func testVlanAttachmentValue(t *testing.T, id string, portIDs ...string) tftypes.Value {
+	s := resourceSchema(context.Background())
+	ports := make([]tftypes.Value, len(portIDs))
+	for i, portID := range portIDs {
+		ports[i] = tftypes.NewValue(tftypes.String, portID)
+	}
+	idValue := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	if id != "" {
+		idValue = tftypes.NewValue(tftypes.String, id)
+	}
+	return tftypes.NewValue(s.Type().TerraformType(context.Background()), map[string]tftypes.Value{
+		"id":       idValue,
+		"vlan_id":  tftypes.NewValue(tftypes.String, "vlanId"),
+		"port_ids": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, ports),
+	})
+}
+
+// testProviderMeta is an empty provider_meta block, which the resource reads
+// the module name of the user agent from
+func testProviderMeta() tfsdk.Config {
+	s := schema.Schema{Attributes: map[string]schema.Attribute{
+		"module_name": schema.StringAttribute{Optional: true},
+	}}
+	return tfsdk.Config{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), map[string]tftypes.Value{
+		"module_name": tftypes.NewValue(tftypes.String, nil),
+	})}
+}
+
+func testVlanAttachmentPortIDs(t *testing.T, state tfsdk.State) []string {
+	var m ResourceModel
+	if diags := state.Get(context.Background(), &m); diags.HasError() {
+		t.Fatalf("state.Get() = %v", diags)
+	}
+	portIDs, diags := m.portIDs(context.Background())
+	if diags.HasError() {
+		t.Fatalf("portIDs() = %v", diags)
+	}
+	return portIDs
+}
+
+func TestResource_roundTrip(t *testing.T) {
+	ctx := context.Background()
+	mock := &mockPortsAPI{attached: map[string]bool{}}
+	r := testVlanAttachmentResource(t, mock)
+	s := resourceSchema(ctx)
+
+	createResp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+	r.Create(ctx, resource.CreateRequest{ProviderMeta: testProviderMeta(), Plan: tfsdk.Plan{Schema: s, Raw: testVlanAttachmentValue(t, "", "port1", "port2")}}, createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create() = %v", createResp.Diagnostics)
+	}
+	if got, want := testVlanAttachmentPortIDs(t, createResp.State), []string{"port1", "port2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Create() port_ids = %v, want %v", got, want)
+	}
+
+	readResp := &resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{ProviderMeta: testProviderMeta(), State: createResp.State}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", readResp.Diagnostics)
+	}
+	if got, want := testVlanAttachmentPortIDs(t, readResp.State), []string{"port1", "port2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() port_ids = %v, want %v", got, want)
+	}
+
+	updateResp := &resource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, resource.UpdateRequest{
+		ProviderMeta: testProviderMeta(),
+		State:        readResp.State,
+		Plan:         tfsdk.Plan{Schema: s, Raw: testVlanAttachmentValue(t, "vlanId", "port2", "port3")},
+	}, updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Update() = %v", updateResp.Diagnostics)
+	}
+	if got, want := testVlanAttachmentPortIDs(t, updateResp.State), []string{"port2", "port3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Update() port_ids = %v, want %v", got, want)
+	}
+	if mock.attached["port1"] || !mock.attached["port3"] {
+		t.Errorf("Update() attached ports = %v, want port2 and port3", mock.attached)
+	}
+
+	// port3 is detached outside of the attachment
+	delete(mock.attached, "port3")
+	readResp = &resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{ProviderMeta: testProviderMeta(), State: updateResp.State}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read() = %v", readResp.Diagnostics)
+	}
+	if got, want := testVlanAttachmentPortIDs(t, readResp.State), []string{"port2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() after detach port_ids = %v, want %v", got, want)
+	}
+}
+
+func TestResource_createPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	mock := &mockPortsAPI{attached: map[string]bool{}, failPort: "port2"}
+	r := testVlanAttachmentResource(t, mock)
+	s := resourceSchema(ctx)
+
+	createResp := &resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+	r.Create(ctx, resource.CreateRequest{ProviderMeta: testProviderMeta(), Plan: tfsdk.Plan{Schema: s, Raw: testVlanAttachmentValue(t, "", "port1", "port2", "port3")}}, createResp)
+	if !createResp.Diagnostics.HasError() {
+		t.Fatal("Create() returned no error, want the assign failure")
+	}
+	if got, want := testVlanAttachmentPortIDs(t, createResp.State), []string{"port1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Create() port_ids = %v, want %v", got, want)
+	}
+	if mock.attached["port2"] || mock.attached["port3"] {
+		t.Errorf("Create() attached ports = %v, want only port1", mock.attached)
+	}
+}
+
+func TestResource_updatePartialFailure(t *testing.T) {
+	ctx := context.Background()
+	mock := &mockPortsAPI{attached: map[string]bool{"port1": true, "port2": true}, failPort: "port4"}
+	r := testVlanAttachmentResource(t, mock)
+	s := resourceSchema(ctx)
+
+	state := tfsdk.State{Schema: s, Raw: testVlanAttachmentValue(t, "vlanId", "port1", "port2")}
+	updateResp := &resource.UpdateResponse{State: state}
+	r.Update(ctx, resource.UpdateRequest{
+		ProviderMeta: testProviderMeta(),
+		State:        state,
+		Plan:         tfsdk.Plan{Schema: s, Raw: testVlanAttachmentValue(t, "vlanId", "port2", "port3", "port4")},
+	}, updateResp)
+	if !updateResp.Diagnostics.HasError() {
+		t.Fatal("Update() returned no error, want the assign failure")
+	}
+	if got, want := testVlanAttachmentPortIDs(t, updateResp.State), []string{"port2", "port3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Update() port_ids = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package vlanattachment_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/equinix/terraform-provider-equinix/internal/acceptance"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func testAccMetalVlanAttachmentConfig(name string, devices int) string {
+	return fmt.Sprintf(`
+%s
+
+resource "equinix_metal_project" "test" {
+    name = "tfacc-vlan_attachment-%s"
+}
+
+resource "equinix_metal_device" "test" {
+  count            = 2
+  hostname         = "tfacc-device-vlan-attachment-test-${count.index}"
+  plan             = local.plan
+  metro            = local.metro
+  operating_system = local.os
+  billing_cycle    = "hourly"
+  project_id       = equinix_metal_project.test.id
+  termination_time = "%s"
+}
+
+resource "equinix_metal_device_network_type" "test" {
+  count     = 2
+  device_id = equinix_metal_device.test[count.index].id
+  type      = "hybrid-bonded"
+}
+
+resource "equinix_metal_vlan" "test" {
+  description = "tfacc-vlan test VLAN"
+  metro       = equinix_metal_device.test[0].metro
+  project_id  = equinix_metal_project.test.id
+}
+
+resource "equinix_metal_vlan_attachment" "test" {
+  vlan_id  = equinix_metal_vlan.test.id
+  port_ids = [
+    for d in slice(equinix_metal_device.test, 0, %d) :
+    one([for p in d.ports : p.id if p.name == "bond0"])
+  ]
+  depends_on = [equinix_metal_device_network_type.test]
+}
+`, acceptance.ConfAccMetalDevice_base(acceptance.Preferable_plans, acceptance.Preferable_metros, acceptance.Preferable_os),
+		name, acceptance.TestDeviceTerminationTime(), devices)
+}
+
+func TestAccMetalVlanAttachment_basic(t *testing.T) {
+	rs := acctest.RandString(10)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { acceptance.TestAccPreCheckMetal(t) },
+		ExternalProviders:        acceptance.TestExternalProviders,
+		ProtoV5ProviderFactories: acceptance.ProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMetalVlanAttachmentConfig(rs, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"equinix_metal_vlan_attachment.test", "id",
+						"equinix_metal_vlan.test", "id"),
+					resource.TestCheckResourceAttr(
+						"equinix_metal_vlan_attachment.test", "port_ids.#", "1"),
+				),
+			},
+			{
+				Config: testAccMetalVlanAttachmentConfig(rs, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"equinix_metal_vlan_attachment.test", "port_ids.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "equinix_metal_vlan_attachment.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccMetalVlanAttachmentConfig(rs, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"equinix_metal_vlan_attachment.test", "port_ids.#", "1"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package vrf
+
+import (
+	"fmt"
+	"net"
+)
+
+// validateIPRange checks that a VRF IP range is a CIDR within the sizes the
+// API accepts, /8 to /29 for IPv4 and /56 to /64 for IPv6
+func validateIPRange(v interface{}, k string) (warns []string, errs []error) {
+	value := v.(string)
+	ip, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, []error{fmt.Errorf("expected %q to be a CIDR, got %q: %w", k, value, err)}
+	}
+
+	ones, _ := ipNet.Mask.Size()
+	minOnes, maxOnes := 8, 29
+	if ip.To4() == nil {
+		minOnes, maxOnes = 56, 64
+	}
+	if ones < minOnes || ones > maxOnes {
+		errs = append(errs, fmt.Errorf("expected %q to have a prefix length between /%d and /%d, got %q", k, minOnes, maxOnes, value))
+	}
+	if !ip.Equal(ipNet.IP) {
+		errs = append(errs, fmt.Errorf("expected %q to be a network address, got %q, did you mean %q?", k, value, ipNet.String()))
+	}
+	return warns, errs
+}
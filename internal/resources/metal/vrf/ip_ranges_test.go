@@ -0,0 +1,31 @@
+package vrf
+
+import "testing"
+
+func TestValidateIPRange(t *testing.T) {
+	tests := []struct {
+		ipRange string
+		wantErr bool
+	}{
+		{ipRange: "192.168.100.0/25"},
+		{ipRange: "10.0.0.0/8"},
+		{ipRange: "192.168.100.0/29"},
+		{ipRange: "2001:db8::/56"},
+		{ipRange: "2001:db8::/64"},
+		{ipRange: "192.168.100.0/30", wantErr: true},
+		{ipRange: "10.0.0.0/7", wantErr: true},
+		{ipRange: "2001:db8::/48", wantErr: true},
+		{ipRange: "192.168.100.1/25", wantErr: true},
+		{ipRange: "192.168.100.0", wantErr: true},
+		{ipRange: "not-a-cidr", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ipRange, func(t *testing.T) {
+			_, errs := validateIPRange(tt.ipRange, "ip_ranges")
+			if (len(errs) > 0) != tt.wantErr {
+				t.Errorf("validateIPRange() errors = %v, wantErr %v", errs, tt.wantErr)
+			}
+		})
+	}
+}
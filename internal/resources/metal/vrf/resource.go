@@ -50,7 +50,10 @@ func Resource() *schema.Resource {
 				Type:        schema.TypeSet,
 				Optional:    true,
 				Description: "All IPv4 and IPv6 Ranges that will be available to BGP Peers. IPv4 addresses must be /8 or smaller with a minimum size of /29. IPv6 must be /56 or smaller with a minimum size of /64. Ranges must not overlap other ranges within the VRF.",
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIPRange,
+				},
 			},
 			"project_id": {
 				Type:        schema.TypeString,
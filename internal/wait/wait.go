@@ -0,0 +1,83 @@
+package wait
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// DefaultDelay and DefaultMinTimeout are the intervals most Metal waiters poll
+// with. StateConf does not apply them, a waiter opts in with WithDelay and
+// WithMinTimeout, so that sharing the helper does not change how often the
+// existing waiters poll.
+const (
+	// DefaultDelay is how long to wait before the first refresh
+	DefaultDelay = 10 * time.Second
+	// DefaultMinTimeout is the smallest interval between two refreshes. The
+	// interval grows exponentially from there, up to 10 seconds
+	DefaultMinTimeout = 3 * time.Second
+	// Margin is kept off the operation timeout so that the waiter gives up,
+	// and reports a meaningful error, before Terraform cancels the context
+	Margin = 30 * time.Second
+)
+
+// Option customizes the StateChangeConf built by StateConf and ForState
+type Option func(*retry.StateChangeConf)
+
+// WithDelay sets how long to wait before the first refresh
+func WithDelay(d time.Duration) Option {
+	return func(c *retry.StateChangeConf) {
+		c.Delay = d
+	}
+}
+
+// WithMinTimeout sets the smallest interval between two refreshes
+func WithMinTimeout(d time.Duration) Option {
+	return func(c *retry.StateChangeConf) {
+		c.MinTimeout = d
+	}
+}
+
+// WithNotFoundChecks sets how many consecutive refreshes may return a nil
+// result before the waiter fails
+func WithNotFoundChecks(n int) Option {
+	return func(c *retry.StateChangeConf) {
+		c.NotFoundChecks = n
+	}
+}
+
+// Remaining returns the part of an operation timeout that is left to wait for
+// a state, given the time the operation started and keeping Margin off
+func Remaining(timeout time.Duration, start time.Time) time.Duration {
+	return RemainingWithMargin(timeout, start, Margin)
+}
+
+// RemainingWithMargin is Remaining for waiters that keep another margin off
+// the operation timeout
+func RemainingWithMargin(timeout time.Duration, start time.Time, margin time.Duration) time.Duration {
+	return timeout - margin - time.Since(start)
+}
+
+// StateConf returns the StateChangeConf that ForState waits on. It is meant
+// for callers that need to wrap the wait itself, use ForState otherwise.
+// Without options the intervals are the ones of retry.StateChangeConf.
+func StateConf(refresh retry.StateRefreshFunc, pending, target []string, timeout time.Duration, opts ...Option) *retry.StateChangeConf {
+	conf := &retry.StateChangeConf{
+		Pending: pending,
+		Target:  target,
+		Refresh: refresh,
+		Timeout: timeout,
+	}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	return conf
+}
+
+// ForState polls refresh with exponential backoff until it returns one of the
+// target states, an error, or a state that is neither pending nor target. It
+// gives up once timeout has elapsed or ctx is done, whichever comes first.
+func ForState(ctx context.Context, refresh retry.StateRefreshFunc, pending, target []string, timeout time.Duration, opts ...Option) (interface{}, error) {
+	return StateConf(refresh, pending, target, timeout, opts...).WaitForStateContext(ctx)
+}
@@ -0,0 +1,103 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestForState(t *testing.T) {
+	fastOpts := []Option{WithDelay(0), WithMinTimeout(10 * time.Millisecond)}
+
+	tests := []struct {
+		name    string
+		states  []string
+		err     error
+		timeout time.Duration
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "target",
+			states:  []string{"pending", "pending", "done"},
+			timeout: time.Second,
+			want:    "done",
+		},
+		{
+			name:    "unexpected state",
+			states:  []string{"pending", "failed"},
+			timeout: time.Second,
+			wantErr: true,
+		},
+		{
+			name:    "refresh error",
+			states:  []string{"pending"},
+			err:     errors.New("boom"),
+			timeout: time.Second,
+			wantErr: true,
+		},
+		{
+			name:    "timeout",
+			states:  []string{"pending"},
+			timeout: 50 * time.Millisecond,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			refresh := func() (interface{}, string, error) {
+				state := tt.states[min(calls, len(tt.states)-1)]
+				calls++
+				if tt.err != nil && calls > 1 {
+					return nil, "", tt.err
+				}
+				return state, state, nil
+			}
+
+			got, err := ForState(context.Background(), refresh, []string{"pending"}, []string{"done"}, tt.timeout, fastOpts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ForState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ForState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForState_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	refresh := func() (interface{}, string, error) {
+		return "pending", "pending", nil
+	}
+	_, err := ForState(ctx, refresh, []string{"pending"}, []string{"done"}, time.Minute, WithDelay(0))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForState() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestStateConf_defaults(t *testing.T) {
+	conf := StateConf(nil, nil, nil, time.Minute)
+	if conf.Delay != 0 || conf.MinTimeout != 0 || conf.NotFoundChecks != 0 {
+		t.Errorf("StateConf() = %+v, want the retry.StateChangeConf intervals", conf)
+	}
+
+	conf = StateConf(nil, nil, nil, time.Minute, WithDelay(DefaultDelay), WithMinTimeout(DefaultMinTimeout), WithNotFoundChecks(5))
+	if conf.Delay != DefaultDelay || conf.MinTimeout != DefaultMinTimeout || conf.NotFoundChecks != 5 {
+		t.Errorf("StateConf() = %+v, want the delay, min timeout and not found checks of the options", conf)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	if got := Remaining(time.Minute, time.Now()); got > 30*time.Second || got < 29*time.Second {
+		t.Errorf("Remaining() = %v, want about 30s", got)
+	}
+	if got := RemainingWithMargin(time.Minute, time.Now(), 10*time.Second); got > 50*time.Second || got < 49*time.Second {
+		t.Errorf("RemainingWithMargin() = %v, want about 50s", got)
+	}
+}